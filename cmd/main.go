@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	cmdpkg "github.com/nathabonfim59/cerebras-code-monitor/internal/cmd"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
@@ -20,19 +23,23 @@ var rootCmd = &cobra.Command{
 	Use:   "cerebras-monitor",
 	Short: "A tool to monitor Cerebras AI usage",
 	Long:  "Real-time monitoring tool for Cerebras AI usage with rate limit tracking. Track your token consumption and request limits with predictions and warnings.",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	// Errors are reported by main so the exit code matches their class
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Migration check removed to prevent output
 		// Users should manually run migrations if needed
+		return cmdpkg.ValidateOutput()
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if version flag is set
 		if showVersion, _ := cmd.Flags().GetBool("version"); showVersion {
 			fmt.Printf("cerebras-code-monitor %s (commit: %s, built: %s)\n", version, commit, date)
-			return
+			return nil
 		}
 
 		// Default behavior - start dashboard
-		cmdpkg.DashboardCmd.Run(cmdpkg.DashboardCmd, args)
+		return cmdpkg.DashboardCmd.RunE(cmdpkg.DashboardCmd, args)
 	},
 }
 
@@ -41,6 +48,7 @@ func init() {
 	rootCmd.PersistentFlags().String("session-token", "", "Cerebras session token (can be set via environment variable)")
 	rootCmd.PersistentFlags().String("org-id", "", "Organization ID to monitor")
 	rootCmd.PersistentFlags().String("model", "qwen-3-coder-480b", "Model to monitor")
+	rootCmd.PersistentFlags().String("region", "", "Region to monitor (default every region, summed)")
 	rootCmd.PersistentFlags().Int("refresh-rate", 10, "Data refresh rate in seconds (1-60)")
 	rootCmd.PersistentFlags().Float64("refresh-per-second", 0.75, "Display refresh rate in Hz (0.1-20.0)")
 	rootCmd.PersistentFlags().String("timezone", config.GetUserTimezone(), "Timezone (auto-detected)")
@@ -52,6 +60,8 @@ func init() {
 	rootCmd.PersistentFlags().Bool("clear", false, "Clear saved configuration")
 	rootCmd.PersistentFlags().String("icons", "emoji", "Icon set to use: emoji or nerdfont")
 	rootCmd.PersistentFlags().BoolP("version", "v", false, "Show version information")
+	rootCmd.PersistentFlags().StringP("output", "o", cmdpkg.OutputText, "Output format: text or json")
+	rootCmd.SetFlagErrorFunc(cmdpkg.FlagError)
 
 	// Bind flags to viper
 	err := viper.BindPFlag("session-token", rootCmd.PersistentFlags().Lookup("session-token"))
@@ -66,6 +76,10 @@ func init() {
 	if err != nil {
 		fmt.Printf("Error binding model flag: %v\n", err)
 	}
+	err = viper.BindPFlag("region", rootCmd.PersistentFlags().Lookup("region"))
+	if err != nil {
+		fmt.Printf("Error binding region flag: %v\n", err)
+	}
 	err = viper.BindPFlag("refresh-rate", rootCmd.PersistentFlags().Lookup("refresh-rate"))
 	if err != nil {
 		fmt.Printf("Error binding refresh-rate flag: %v\n", err)
@@ -110,6 +124,10 @@ func init() {
 	if err != nil {
 		fmt.Printf("Error binding version flag: %v\n", err)
 	}
+	err = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	if err != nil {
+		fmt.Printf("Error binding output flag: %v\n", err)
+	}
 
 	// Set environment variable support
 	viper.SetEnvPrefix("CEREBRAS")
@@ -133,11 +151,26 @@ func init() {
 	rootCmd.AddCommand(cmdpkg.MigrationsCmd)
 	rootCmd.AddCommand(cmdpkg.TestCmd)
 	rootCmd.AddCommand(cmdpkg.DashboardCmd)
+	rootCmd.AddCommand(cmdpkg.CostCmd)
+	rootCmd.AddCommand(cmdpkg.ProxyCmd)
+	rootCmd.AddCommand(cmdpkg.CacheCmd)
+	rootCmd.AddCommand(cmdpkg.DaemonCmd)
+	rootCmd.AddCommand(cmdpkg.ServeCmd)
+	rootCmd.AddCommand(cmdpkg.McpCmd)
+	rootCmd.AddCommand(cmdpkg.NotifyCmd)
+	rootCmd.AddCommand(cmdpkg.AlertsCmd)
+	rootCmd.AddCommand(cmdpkg.CheckCmd)
+	rootCmd.AddCommand(cmdpkg.StatusCmd)
+	cmdpkg.Version = version
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Interrupts cancel in-flight requests instead of waiting for timeouts
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
+		os.Exit(cmdpkg.ReportError(os.Stderr, err))
 	}
 }
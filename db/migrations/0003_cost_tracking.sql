@@ -0,0 +1,25 @@
+-- migrate:up
+-- Daily token counter so spend can be derived from snapshot history
+ALTER TABLE usage_snapshots ADD COLUMN tokens_used_day INTEGER;  -- From usage_tokens_day
+
+-- Per-request token splits recorded by an intercepting proxy
+CREATE TABLE request_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+
+    -- Token split as reported in the completion response
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+
+    -- Metadata
+    source TEXT NOT NULL               -- 'proxy'
+);
+
+CREATE INDEX IF NOT EXISTS idx_request_usage_org_model_time ON request_usage(organization_id, model_name, timestamp);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_request_usage_org_model_time;
+DROP TABLE IF EXISTS request_usage;
+ALTER TABLE usage_snapshots DROP COLUMN tokens_used_day;
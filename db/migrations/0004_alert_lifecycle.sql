@@ -0,0 +1,16 @@
+-- migrate:up
+-- Alert lifecycle: repeated conditions update one alert until it resolves
+ALTER TABLE alerts ADD COLUMN status TEXT NOT NULL DEFAULT 'active';  -- 'active', 'snoozed', 'resolved'
+ALTER TABLE alerts ADD COLUMN resolved_at DATETIME;
+ALTER TABLE alerts ADD COLUMN snoozed_until DATETIME;
+
+-- Alerts raised before lifecycle tracking are treated as resolved
+UPDATE alerts SET status = 'resolved', resolved_at = timestamp;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_org_model_status ON alerts(organization_id, model_name, status);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_alerts_org_model_status;
+ALTER TABLE alerts DROP COLUMN snoozed_until;
+ALTER TABLE alerts DROP COLUMN resolved_at;
+ALTER TABLE alerts DROP COLUMN status;
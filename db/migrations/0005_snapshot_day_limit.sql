@@ -0,0 +1,6 @@
+-- migrate:up
+-- Daily token limit so rules on the tokens-day window can be replayed from history
+ALTER TABLE usage_snapshots ADD COLUMN tokens_limit_day INTEGER;  -- From limit_tokens_day, -1 when unlimited
+
+-- migrate:down
+ALTER TABLE usage_snapshots DROP COLUMN tokens_limit_day;
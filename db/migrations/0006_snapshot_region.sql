@@ -0,0 +1,6 @@
+-- migrate:up
+-- Region each snapshot describes, so per-region history can be told apart
+ALTER TABLE usage_snapshots ADD COLUMN region_id TEXT NOT NULL DEFAULT '';  -- '' when summed across regions
+
+-- migrate:down
+ALTER TABLE usage_snapshots DROP COLUMN region_id;
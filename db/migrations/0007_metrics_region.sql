@@ -0,0 +1,99 @@
+-- migrate:up
+-- Rollups per region, like the snapshots they aggregate. SQLite cannot change
+-- a UNIQUE constraint, so the table is rebuilt.
+CREATE TABLE usage_metrics_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp DATETIME NOT NULL,
+    organization_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    time_window TEXT NOT NULL,         -- 'minute', 'hour', 'day'
+
+    -- Aggregated usage
+    total_tokens_used INTEGER,
+    total_requests_used INTEGER,
+
+    -- Burn rates
+    avg_burn_rate_tokens REAL,         -- tokens per minute average
+    peak_burn_rate_tokens REAL,        -- peak tokens per minute
+    avg_burn_rate_requests REAL,       -- requests per minute average
+
+    -- Statistical analysis
+    is_above_average BOOLEAN DEFAULT 0,
+    deviation_percentage REAL,         -- % above/below average
+
+    -- Sample counts
+    snapshot_count INTEGER,            -- Number of snapshots in window
+
+    region_id TEXT NOT NULL DEFAULT '',  -- '' when summed across regions
+
+    UNIQUE(organization_id, model_name, region_id, time_window, timestamp)
+);
+
+INSERT INTO usage_metrics_new (
+    id, timestamp, organization_id, model_name, time_window,
+    total_tokens_used, total_requests_used,
+    avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests,
+    is_above_average, deviation_percentage, snapshot_count
+)
+SELECT
+    id, timestamp, organization_id, model_name, time_window,
+    total_tokens_used, total_requests_used,
+    avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests,
+    is_above_average, deviation_percentage, snapshot_count
+FROM usage_metrics;
+
+DROP TABLE usage_metrics;
+ALTER TABLE usage_metrics_new RENAME TO usage_metrics;
+
+CREATE INDEX idx_metrics_window ON usage_metrics(time_window, timestamp DESC);
+CREATE INDEX idx_org_model_window ON usage_metrics(organization_id, model_name, region_id, time_window);
+CREATE INDEX idx_timestamp_window ON usage_metrics(timestamp, time_window);
+
+-- migrate:down
+CREATE TABLE usage_metrics_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp DATETIME NOT NULL,
+    organization_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    time_window TEXT NOT NULL,         -- 'minute', 'hour', 'day'
+
+    -- Aggregated usage
+    total_tokens_used INTEGER,
+    total_requests_used INTEGER,
+
+    -- Burn rates
+    avg_burn_rate_tokens REAL,         -- tokens per minute average
+    peak_burn_rate_tokens REAL,        -- peak tokens per minute
+    avg_burn_rate_requests REAL,       -- requests per minute average
+
+    -- Statistical analysis
+    is_above_average BOOLEAN DEFAULT 0,
+    deviation_percentage REAL,         -- % above/below average
+
+    -- Sample counts
+    snapshot_count INTEGER,            -- Number of snapshots in window
+
+    UNIQUE(organization_id, model_name, time_window, timestamp)
+);
+
+-- Only the organization-wide rollups fit the old key
+INSERT INTO usage_metrics_old (
+    id, timestamp, organization_id, model_name, time_window,
+    total_tokens_used, total_requests_used,
+    avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests,
+    is_above_average, deviation_percentage, snapshot_count
+)
+SELECT
+    id, timestamp, organization_id, model_name, time_window,
+    total_tokens_used, total_requests_used,
+    avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests,
+    is_above_average, deviation_percentage, snapshot_count
+FROM usage_metrics
+WHERE region_id = '';
+
+DROP TABLE usage_metrics;
+ALTER TABLE usage_metrics_old RENAME TO usage_metrics;
+
+CREATE INDEX idx_metrics_window ON usage_metrics(time_window, timestamp DESC);
+CREATE INDEX idx_org_model_window ON usage_metrics(organization_id, model_name, time_window);
+CREATE INDEX idx_timestamp_window ON usage_metrics(timestamp, time_window);
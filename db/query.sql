@@ -12,7 +12,10 @@ INSERT INTO usage_snapshots (
     reset_requests_seconds,
     reset_tokens_seconds,
     data_source,
-    is_complete
+    is_complete,
+    tokens_used_day,
+    tokens_limit_day,
+    region_id
 ) VALUES (
     ?,
     ?,
@@ -26,12 +29,15 @@ INSERT INTO usage_snapshots (
     ?,
     ?,
     ?,
+    ?,
+    ?,
+    ?,
     ?
 );
 
 -- name: GetLatestUsageSnapshot :one
 SELECT * FROM usage_snapshots
-WHERE organization_id = ? AND model_name = ?
+WHERE organization_id = ? AND model_name = ? AND region_id = ?
 ORDER BY timestamp DESC
 LIMIT 1;
 
@@ -40,10 +46,29 @@ SELECT * FROM usage_snapshots
 WHERE timestamp > datetime('now', ?)
 AND organization_id = ?
 AND model_name = ?
+AND region_id = ?
 ORDER BY timestamp ASC;
 
+-- name: GetUsageSnapshotsBetween :many
+SELECT * FROM usage_snapshots
+WHERE organization_id = ?
+AND model_name = ?
+AND region_id = ?
+AND timestamp >= sqlc.arg(start_time)
+AND timestamp < sqlc.arg(end_time)
+ORDER BY timestamp ASC;
+
+-- name: GetLatestUsageSnapshotBefore :one
+SELECT * FROM usage_snapshots
+WHERE organization_id = ?
+AND model_name = ?
+AND region_id = ?
+AND timestamp < ?
+ORDER BY timestamp DESC
+LIMIT 1;
+
 -- name: InsertUsageMetrics :exec
-INSERT INTO usage_metrics (
+INSERT OR REPLACE INTO usage_metrics (
     timestamp,
     organization_id,
     model_name,
@@ -55,7 +80,8 @@ INSERT INTO usage_metrics (
     avg_burn_rate_requests,
     is_above_average,
     deviation_percentage,
-    snapshot_count
+    snapshot_count,
+    region_id
 ) VALUES (
     ?,
     ?,
@@ -68,6 +94,7 @@ INSERT INTO usage_metrics (
     ?,
     ?,
     ?,
+    ?,
     ?
 );
 
@@ -75,6 +102,7 @@ INSERT INTO usage_metrics (
 SELECT * FROM usage_metrics
 WHERE organization_id = ?
 AND model_name = ?
+AND region_id = ?
 AND time_window = ?
 ORDER BY timestamp DESC
 LIMIT ?;
@@ -147,4 +175,103 @@ INSERT INTO alerts (
 SELECT * FROM alerts
 WHERE organization_id = ?
 AND acknowledged = 0
-ORDER BY timestamp DESC;
\ No newline at end of file
+AND status != 'resolved'
+ORDER BY timestamp DESC;
+
+-- name: CreateAlert :one
+INSERT INTO alerts (
+    timestamp,
+    organization_id,
+    model_name,
+    alert_type,
+    severity,
+    metric_name,
+    metric_value,
+    threshold_value,
+    message,
+    status,
+    snoozed_until
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING id;
+
+-- name: GetOpenAlerts :many
+SELECT * FROM alerts
+WHERE organization_id = ?
+AND model_name = ?
+AND status != 'resolved'
+ORDER BY timestamp ASC;
+
+-- name: GetAlertsResolvedSince :many
+SELECT * FROM alerts
+WHERE organization_id = ?
+AND model_name = ?
+AND status = 'resolved'
+AND resolved_at >= ?
+ORDER BY resolved_at ASC;
+
+-- name: GetRecentAlerts :many
+SELECT * FROM alerts
+WHERE organization_id = ?
+ORDER BY timestamp DESC
+LIMIT ?;
+
+-- name: UpdateAlertState :exec
+UPDATE alerts
+SET severity = ?,
+    metric_value = ?,
+    threshold_value = ?,
+    message = ?,
+    status = ?,
+    resolved_at = ?,
+    snoozed_until = ?
+WHERE id = ?;
+
+-- name: SnoozeAlert :execrows
+UPDATE alerts
+SET status = ?, snoozed_until = ?
+WHERE id = ?
+AND organization_id = ?
+AND status != 'resolved';
+
+-- name: SnoozeOpenAlerts :execrows
+UPDATE alerts
+SET status = ?, snoozed_until = ?
+WHERE organization_id = ?
+AND status != 'resolved';
+
+-- name: InsertRequestUsage :exec
+INSERT INTO request_usage (
+    timestamp,
+    organization_id,
+    model_name,
+    prompt_tokens,
+    completion_tokens,
+    source
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+);
+
+-- name: GetRequestUsageBetween :many
+SELECT * FROM request_usage
+WHERE organization_id = ?
+AND model_name = ?
+AND timestamp >= sqlc.arg(start_time)
+AND timestamp < sqlc.arg(end_time)
+ORDER BY timestamp ASC;
\ No newline at end of file
@@ -22,32 +22,7 @@ CREATE TABLE usage_snapshots (
     -- Metadata
     data_source TEXT NOT NULL,         -- 'api_key' or 'session'
     is_complete BOOLEAN DEFAULT 0      -- 1 if all fields populated
-);
-CREATE TABLE usage_metrics (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    timestamp DATETIME NOT NULL,
-    organization_id TEXT NOT NULL,
-    model_name TEXT NOT NULL,
-    time_window TEXT NOT NULL,         -- 'minute', 'hour', 'day'
-
-    -- Aggregated usage
-    total_tokens_used INTEGER,
-    total_requests_used INTEGER,
-
-    -- Burn rates
-    avg_burn_rate_tokens REAL,         -- tokens per minute average
-    peak_burn_rate_tokens REAL,        -- peak tokens per minute
-    avg_burn_rate_requests REAL,       -- requests per minute average
-
-    -- Statistical analysis
-    is_above_average BOOLEAN DEFAULT 0,
-    deviation_percentage REAL,         -- % above/below average
-
-    -- Sample counts
-    snapshot_count INTEGER,            -- Number of snapshots in window
-
-    UNIQUE(organization_id, model_name, time_window, timestamp)
-);
+, tokens_used_day INTEGER, tokens_limit_day INTEGER, region_id TEXT NOT NULL DEFAULT '');
 CREATE TABLE baseline_averages (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     organization_id TEXT NOT NULL,
@@ -87,10 +62,9 @@ CREATE TABLE alerts (
     -- Status
     acknowledged BOOLEAN DEFAULT 0,
     acknowledged_at DATETIME
-);
+, status TEXT NOT NULL DEFAULT 'active', resolved_at DATETIME, snoozed_until DATETIME);
 CREATE INDEX idx_snapshots_time ON usage_snapshots(timestamp DESC);
 CREATE INDEX idx_snapshots_org_model ON usage_snapshots(organization_id, model_name, timestamp DESC);
-CREATE INDEX idx_metrics_window ON usage_metrics(time_window, timestamp DESC);
 CREATE INDEX idx_alerts_unack ON alerts(organization_id, acknowledged, timestamp DESC);
 CREATE TABLE usage_metrics_archive (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -119,11 +93,59 @@ CREATE TABLE usage_metrics_archive (
 );
 CREATE INDEX idx_org_model_time ON usage_snapshots(organization_id, model_name, timestamp);
 CREATE INDEX idx_timestamp ON usage_snapshots(timestamp);
-CREATE INDEX idx_org_model_window ON usage_metrics(organization_id, model_name, time_window);
-CREATE INDEX idx_timestamp_window ON usage_metrics(timestamp, time_window);
 CREATE INDEX idx_timestamp_alerts ON alerts(timestamp);
 CREATE INDEX idx_org_unack ON alerts(organization_id, acknowledged);
+CREATE TABLE request_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+
+    -- Token split as reported in the completion response
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+
+    -- Metadata
+    source TEXT NOT NULL               -- 'proxy'
+);
+CREATE INDEX idx_request_usage_org_model_time ON request_usage(organization_id, model_name, timestamp);
+CREATE INDEX idx_alerts_org_model_status ON alerts(organization_id, model_name, status);
+CREATE TABLE IF NOT EXISTS "usage_metrics" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp DATETIME NOT NULL,
+    organization_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    time_window TEXT NOT NULL,         -- 'minute', 'hour', 'day'
+
+    -- Aggregated usage
+    total_tokens_used INTEGER,
+    total_requests_used INTEGER,
+
+    -- Burn rates
+    avg_burn_rate_tokens REAL,         -- tokens per minute average
+    peak_burn_rate_tokens REAL,        -- peak tokens per minute
+    avg_burn_rate_requests REAL,       -- requests per minute average
+
+    -- Statistical analysis
+    is_above_average BOOLEAN DEFAULT 0,
+    deviation_percentage REAL,         -- % above/below average
+
+    -- Sample counts
+    snapshot_count INTEGER,            -- Number of snapshots in window
+
+    region_id TEXT NOT NULL DEFAULT '',  -- '' when summed across regions
+
+    UNIQUE(organization_id, model_name, region_id, time_window, timestamp)
+);
+CREATE INDEX idx_metrics_window ON usage_metrics(time_window, timestamp DESC);
+CREATE INDEX idx_org_model_window ON usage_metrics(organization_id, model_name, region_id, time_window);
+CREATE INDEX idx_timestamp_window ON usage_metrics(timestamp, time_window);
 -- Dbmate schema migrations
 INSERT INTO "schema_migrations" (version) VALUES
   ('0001'),
-  ('0002');
+  ('0002'),
+  ('0003'),
+  ('0004'),
+  ('0005'),
+  ('0006'),
+  ('0007');
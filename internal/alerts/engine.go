@@ -0,0 +1,343 @@
+// Package alerts tracks alert state across polls on top of the alerts table:
+// a condition that stays true is one alert, notified once when it fires and
+// again when its severity escalates or it resolves. Alerts can be snoozed,
+// and notifications are held back during quiet hours.
+package alerts
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+)
+
+// Alert statuses stored in the alerts table
+const (
+	StatusActive   = "active"
+	StatusSnoozed  = "snoozed"
+	StatusResolved = "resolved"
+)
+
+// Condition is an alert condition that holds at the current poll
+type Condition struct {
+	Type      string  `json:"type"`
+	Metric    string  `json:"metric"`
+	Severity  string  `json:"severity"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message"`
+	// Notifiers limits delivery to the named notifiers; empty notifies all
+	Notifiers []string `json:"notifiers,omitempty"`
+}
+
+// key identifies the condition across polls
+func (c Condition) key() string {
+	return c.Type + "|" + c.Metric
+}
+
+// state is the tracked alert of one condition
+type state struct {
+	id   int64
+	cond Condition
+	// notified is the highest severity delivered, empty before the first
+	// notification
+	notified     string
+	snoozedUntil time.Time
+	// resolvedAt is set once the condition stopped holding
+	resolvedAt time.Time
+}
+
+func (s *state) status(now time.Time) string {
+	switch {
+	case !s.resolvedAt.IsZero():
+		return StatusResolved
+	case s.snoozedUntil.After(now):
+		return StatusSnoozed
+	default:
+		return StatusActive
+	}
+}
+
+// Engine tracks the alerts of one organization and model. It is safe for
+// concurrent use.
+type Engine struct {
+	organization string
+	model        string
+	policy       Policy
+
+	// Alert storage (optional, see WithStore)
+	queries    *db.Queries
+	storeOrgID string
+	loaded     bool
+
+	mu     sync.Mutex
+	states map[string]*state
+}
+
+// NewEngine creates an engine whose events name the organization and model
+func NewEngine(organization, model string, policy Policy) *Engine {
+	return &Engine{
+		organization: organization,
+		model:        model,
+		policy:       policy,
+		states:       make(map[string]*state),
+	}
+}
+
+// WithStore persists alerts in the usage database under the organization ID
+// and picks up alerts left open by a previous run
+func (e *Engine) WithStore(queries *db.Queries, organization string) *Engine {
+	e.queries = queries
+	e.storeOrgID = organization
+	return e
+}
+
+// Policy returns the notification policy
+func (e *Engine) Policy() Policy {
+	return e.policy
+}
+
+// Observe records the conditions holding at now and returns the
+// notifications due: newly fired and escalated alerts, and alerts resolved
+// for at least the cooldown. Conditions with the same type and metric are
+// one alert at the highest severity.
+func (e *Engine) Observe(ctx context.Context, now time.Time, conditions []Condition) []notify.Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sync(ctx, now)
+
+	var order []string
+	current := make(map[string]Condition, len(conditions))
+	for _, c := range conditions {
+		k := c.key()
+		prev, seen := current[k]
+		if !seen {
+			order = append(order, k)
+		}
+		if !seen || notify.SeverityRank(c.Severity) > notify.SeverityRank(prev.Severity) {
+			current[k] = c
+		}
+	}
+
+	for _, k := range order {
+		c := current[k]
+		s, ok := e.states[k]
+		switch {
+		case !ok:
+			s = &state{cond: c}
+			e.states[k] = s
+			e.create(ctx, now, s)
+			continue
+		case !s.resolvedAt.IsZero():
+			// Reopened within the cooldown: the earlier notifications stand
+			s.resolvedAt = time.Time{}
+		}
+		s.cond = c
+		e.save(ctx, now, s)
+	}
+
+	var resolved []string
+	for k, s := range e.states {
+		if _, ok := current[k]; !ok && s.resolvedAt.IsZero() {
+			s.resolvedAt = now
+			e.save(ctx, now, s)
+		}
+		if !s.resolvedAt.IsZero() && now.Sub(s.resolvedAt) >= e.policy.Cooldown {
+			resolved = append(resolved, k)
+		}
+	}
+	slices.Sort(resolved)
+
+	var events []notify.Event
+	for _, k := range order {
+		s := e.states[k]
+		if s.snoozedUntil.After(now) || e.policy.QuietHours.Holds(s.cond.Severity, now) {
+			continue
+		}
+		if !s.snoozedUntil.IsZero() {
+			s.snoozedUntil = time.Time{}
+			e.save(ctx, now, s)
+		}
+		if notify.SeverityRank(s.cond.Severity) <= notify.SeverityRank(s.notified) {
+			continue
+		}
+		status := notify.StatusFiring
+		if s.notified != "" {
+			status = notify.StatusEscalated
+		}
+		s.notified = s.cond.Severity
+		events = append(events, e.event(now, s.cond, status))
+	}
+	for _, k := range resolved {
+		s := e.states[k]
+		delete(e.states, k)
+		// Resolutions are dropped rather than held: there is nothing left to act on
+		if s.notified == "" || s.snoozedUntil.After(now) || e.policy.QuietHours.Holds(s.cond.Severity, now) {
+			continue
+		}
+		events = append(events, e.event(now, s.cond, notify.StatusResolved))
+	}
+
+	return events
+}
+
+// Snooze holds back the notifications of the open alerts until the given
+// time, or lifts the snooze when it is not in the future. It returns the
+// number of alerts changed.
+func (e *Engine) Snooze(ctx context.Context, now, until time.Time) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sync(ctx, now)
+	if !until.After(now) {
+		until = time.Time{}
+	}
+	n := 0
+	for _, s := range e.states {
+		if !s.resolvedAt.IsZero() {
+			continue
+		}
+		s.snoozedUntil = until
+		e.save(ctx, now, s)
+		n++
+	}
+	return n
+}
+
+// event builds the notification of a condition
+func (e *Engine) event(now time.Time, c Condition, status string) notify.Event {
+	return notify.Event{
+		Time:         now.UTC().Truncate(time.Second),
+		Organization: e.organization,
+		Model:        e.model,
+		Type:         c.Type,
+		Severity:     c.Severity,
+		Metric:       c.Metric,
+		Value:        c.Value,
+		Threshold:    c.Threshold,
+		Message:      c.Message,
+		Status:       status,
+		Notifiers:    c.Notifiers,
+	}
+}
+
+// sync loads the stored alerts on first use, and afterwards picks up
+// snoozes set from other processes such as "alerts snooze"
+func (e *Engine) sync(ctx context.Context, now time.Time) {
+	if e.queries == nil {
+		return
+	}
+	open, err := e.queries.GetOpenAlerts(ctx, db.GetOpenAlertsParams{
+		OrganizationID: e.storeOrgID,
+		ModelName:      e.model,
+	})
+	if err != nil {
+		return
+	}
+
+	if !e.loaded {
+		e.loaded = true
+		since := now.Add(-e.policy.Cooldown).UTC()
+		resolved, _ := e.queries.GetAlertsResolvedSince(ctx, db.GetAlertsResolvedSinceParams{
+			OrganizationID: e.storeOrgID,
+			ModelName:      e.model,
+			ResolvedAt:     &since,
+		})
+		// Open alerts were notified by the previous run; later rows win
+		for _, a := range append(resolved, open...) {
+			s := stateFromAlert(a)
+			e.states[s.cond.key()] = s
+		}
+		return
+	}
+
+	for _, a := range open {
+		for _, s := range e.states {
+			if s.id == a.ID {
+				s.snoozedUntil = time.Time{}
+				if a.SnoozedUntil != nil {
+					s.snoozedUntil = *a.SnoozedUntil
+				}
+			}
+		}
+	}
+}
+
+// stateFromAlert restores the state of a stored alert
+func stateFromAlert(a db.Alert) *state {
+	s := &state{
+		id: a.ID,
+		cond: Condition{
+			Type:      a.AlertType,
+			Metric:    a.MetricName,
+			Severity:  a.Severity,
+			Value:     a.MetricValue,
+			Threshold: a.ThresholdValue,
+		},
+		notified: a.Severity,
+	}
+	if a.Message != nil {
+		s.cond.Message = *a.Message
+	}
+	if a.SnoozedUntil != nil {
+		s.snoozedUntil = *a.SnoozedUntil
+	}
+	if a.ResolvedAt != nil {
+		s.resolvedAt = *a.ResolvedAt
+	}
+	return s
+}
+
+// create stores a new alert. Storage is best-effort: the engine keeps
+// tracking in memory when it fails.
+func (e *Engine) create(ctx context.Context, now time.Time, s *state) {
+	if e.queries == nil {
+		return
+	}
+	message := s.cond.Message
+	id, err := e.queries.CreateAlert(ctx, db.CreateAlertParams{
+		Timestamp:      now.UTC().Truncate(time.Second),
+		OrganizationID: e.storeOrgID,
+		ModelName:      e.model,
+		AlertType:      s.cond.Type,
+		Severity:       s.cond.Severity,
+		MetricName:     s.cond.Metric,
+		MetricValue:    s.cond.Value,
+		ThresholdValue: s.cond.Threshold,
+		Message:        &message,
+		Status:         s.status(now),
+	})
+	if err == nil {
+		s.id = id
+	}
+}
+
+// save updates a stored alert
+func (e *Engine) save(ctx context.Context, now time.Time, s *state) {
+	if e.queries == nil || s.id == 0 {
+		return
+	}
+	message := s.cond.Message
+	_ = e.queries.UpdateAlertState(ctx, db.UpdateAlertStateParams{
+		Severity:       s.cond.Severity,
+		MetricValue:    s.cond.Value,
+		ThresholdValue: s.cond.Threshold,
+		Message:        &message,
+		Status:         s.status(now),
+		ResolvedAt:     timePtr(s.resolvedAt),
+		SnoozedUntil:   timePtr(s.snoozedUntil),
+		ID:             s.id,
+	})
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC().Truncate(time.Second)
+	return &t
+}
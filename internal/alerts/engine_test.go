@@ -0,0 +1,205 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/viper"
+)
+
+func condition(severity string, value float64) Condition {
+	return Condition{Type: "budget", Metric: "cost_daily", Severity: severity, Value: value, Threshold: 5}
+}
+
+func statuses(events []notify.Event) []string {
+	var s []string
+	for _, e := range events {
+		s = append(s, e.Status+":"+e.Severity)
+	}
+	return s
+}
+
+func expectStatuses(t *testing.T, step string, events []notify.Event, expected ...string) {
+	t.Helper()
+	got := statuses(events)
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected %v, got %v", step, expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("%s: expected %v, got %v", step, expected, got)
+		}
+	}
+}
+
+func TestEngineLifecycle(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	e := NewEngine("org", "model", Policy{Cooldown: 5 * time.Minute})
+	poll := func(i int, conds ...Condition) []notify.Event {
+		return e.Observe(ctx, start.Add(time.Duration(i)*10*time.Second), conds)
+	}
+
+	expectStatuses(t, "fire", poll(0, condition(notify.SeverityWarning, 4)), "firing:warning")
+	for i := 1; i < 30; i++ {
+		expectStatuses(t, "duplicate", poll(i, condition(notify.SeverityWarning, 4.1)))
+	}
+	expectStatuses(t, "escalate", poll(30, condition(notify.SeverityCritical, 5.2)), "escalated:critical")
+	expectStatuses(t, "downgrade", poll(31, condition(notify.SeverityWarning, 4.5)))
+	expectStatuses(t, "critical again", poll(32, condition(notify.SeverityCritical, 5.1)))
+
+	// Resolution is only announced once it held for the cooldown
+	expectStatuses(t, "resolving", poll(33))
+	expectStatuses(t, "reopen", poll(34, condition(notify.SeverityCritical, 5.1)))
+	expectStatuses(t, "resolving again", poll(35))
+	expectStatuses(t, "within cooldown", poll(35+29))
+	events := poll(35 + 30)
+	expectStatuses(t, "resolved", events, "resolved:critical")
+	if events[0].Organization != "org" || events[0].Model != "model" || events[0].Value != 5.1 {
+		t.Errorf("Expected the resolution to carry the last condition, got %+v", events[0])
+	}
+
+	expectStatuses(t, "fire after resolution", poll(100, condition(notify.SeverityWarning, 4)), "firing:warning")
+}
+
+func TestEngineMergesConditionsOfOneAlert(t *testing.T) {
+	e := NewEngine("", "model", Policy{})
+	now := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	limit := func(threshold float64, severity string) Condition {
+		return Condition{Type: "soft_limit", Metric: "tokens-day", Severity: severity, Threshold: threshold}
+	}
+	events := e.Observe(context.Background(), now, []Condition{
+		limit(50, notify.SeverityWarning),
+		limit(80, notify.SeverityCritical),
+		{Type: "budget", Metric: "cost_daily", Severity: notify.SeverityWarning},
+	})
+	expectStatuses(t, "merge", events, "firing:critical", "firing:warning")
+	if events[0].Threshold != 80 {
+		t.Errorf("Expected the most severe condition to win, got threshold %v", events[0].Threshold)
+	}
+}
+
+func TestEngineSnooze(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	e := NewEngine("", "model", Policy{})
+
+	expectStatuses(t, "fire", e.Observe(ctx, now, []Condition{condition(notify.SeverityWarning, 4)}), "firing:warning")
+	if n := e.Snooze(ctx, now, now.Add(time.Hour)); n != 1 {
+		t.Fatalf("Expected 1 alert snoozed, got %d", n)
+	}
+	expectStatuses(t, "snoozed escalation", e.Observe(ctx, now.Add(time.Minute), []Condition{condition(notify.SeverityCritical, 6)}))
+	expectStatuses(t, "snooze over", e.Observe(ctx, now.Add(time.Hour), []Condition{condition(notify.SeverityCritical, 6)}), "escalated:critical")
+
+	e.Snooze(ctx, now.Add(time.Hour), now.Add(2*time.Hour))
+	expectStatuses(t, "snoozed resolution", e.Observe(ctx, now.Add(61*time.Minute), nil))
+	expectStatuses(t, "after snoozed resolution", e.Observe(ctx, now.Add(3*time.Hour), nil))
+}
+
+func TestEngineQuietHours(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	policy := Policy{QuietHours: QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, AllowCritical: true, Location: loc}}
+	e := NewEngine("", "model", policy)
+	night := time.Date(2025, 8, 4, 23, 30, 0, 0, loc)
+
+	expectStatuses(t, "held", e.Observe(ctx, night, []Condition{condition(notify.SeverityWarning, 4)}))
+	expectStatuses(t, "critical passes", e.Observe(ctx, night.Add(time.Hour), []Condition{condition(notify.SeverityCritical, 6)}), "firing:critical")
+
+	other := Condition{Type: "soft_limit", Metric: "tokens-day", Severity: notify.SeverityWarning}
+	expectStatuses(t, "held until morning", e.Observe(ctx, night.Add(2*time.Hour), []Condition{condition(notify.SeverityCritical, 6), other}))
+	morning := time.Date(2025, 8, 5, 7, 0, 0, 0, loc)
+	expectStatuses(t, "delivered in the morning", e.Observe(ctx, morning, []Condition{condition(notify.SeverityCritical, 6), other}), "firing:warning")
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		at       time.Time
+		expected bool
+	}{
+		{"disabled", QuietHours{}, time.Date(2025, 8, 4, 3, 0, 0, 0, loc), false},
+		{"same day inside", QuietHours{Start: 12 * time.Hour, End: 14 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 13, 0, 0, 0, loc), true},
+		{"same day end excluded", QuietHours{Start: 12 * time.Hour, End: 14 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 14, 0, 0, 0, loc), false},
+		{"wrapping before midnight", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 23, 0, 0, 0, loc), true},
+		{"wrapping after midnight", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 6, 59, 0, 0, loc), true},
+		{"wrapping daytime", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 12, 0, 0, 0, loc), false},
+		// 21:30 UTC is 23:30 in the configured timezone
+		{"converted to the timezone", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: loc}, time.Date(2025, 8, 4, 21, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.at); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		wantErr  bool
+		check    func(t *testing.T, p Policy)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, p Policy) {
+				if p.Cooldown != DefaultCooldown || p.QuietHours.Enabled() || !p.QuietHours.AllowCritical {
+					t.Errorf("Unexpected defaults: %+v", p)
+				}
+			},
+		},
+		{
+			name: "configured",
+			settings: map[string]any{
+				"timezone":                          "America/Sao_Paulo",
+				"alerts.cooldown":                   60,
+				"alerts.quiet-hours.start":          "22:30",
+				"alerts.quiet-hours.end":            "07:00",
+				"alerts.quiet-hours.allow-critical": false,
+			},
+			check: func(t *testing.T, p Policy) {
+				if p.Cooldown != time.Minute {
+					t.Errorf("Expected a 1m cooldown, got %v", p.Cooldown)
+				}
+				if p.QuietHours.String() != "22:30-07:00" || p.QuietHours.AllowCritical {
+					t.Errorf("Unexpected quiet hours: %+v", p.QuietHours)
+				}
+				if p.QuietHours.Location.String() != "America/Sao_Paulo" {
+					t.Errorf("Expected quiet hours in the configured timezone, got %v", p.QuietHours.Location)
+				}
+			},
+		},
+		{name: "negative cooldown", settings: map[string]any{"alerts.cooldown": -1}, wantErr: true},
+		{name: "start without end", settings: map[string]any{"alerts.quiet-hours.start": "22:00"}, wantErr: true},
+		{name: "invalid time", settings: map[string]any{"alerts.quiet-hours.start": "10pm", "alerts.quiet-hours.end": "07:00"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+			p, err := LoadPolicy()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, p)
+		})
+	}
+}
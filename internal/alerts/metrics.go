@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+)
+
+// Metric prefixes, followed by a window such as tokens_day. Limit, usage,
+// remaining and reset read the RateLimitInfo fields of the same name; the
+// others are derived.
+const (
+	MetricLimit     = "limit"
+	MetricUsage     = "usage"
+	MetricRemaining = "remaining"
+	MetricReset     = "reset"
+	// MetricPercent is the used share of the limit
+	MetricPercent = "percent"
+	// MetricBurnRate is the average usage per minute since the window started
+	MetricBurnRate = "burn_rate"
+	// MetricExhaustsIn is the predicted minutes until the limit runs out at
+	// the burn rate, only set when that happens before the window resets
+	MetricExhaustsIn = "exhausts_in"
+)
+
+// MetricDeviation is followed by a rollup window (hour or day): the percentage
+// the token usage of the current bucket deviates from the average of the
+// previous ones
+const MetricDeviation = "deviation"
+
+// Sample is the input of one rule evaluation
+type Sample struct {
+	Time    time.Time
+	Metrics *cerebras.RateLimitInfo
+	// Deviation is the deviation percentage of the current bucket, keyed by
+	// rollup window; missing when there is no baseline
+	Deviation map[string]float64
+}
+
+// metricFunc computes a metric, returning false when it is unknown
+type metricFunc func(s Sample) (float64, bool)
+
+// metrics maps every metric name to its computation
+var metrics = buildMetrics()
+
+func buildMetrics() map[string]metricFunc {
+	m := map[string]metricFunc{
+		"max_sequence_length": func(s Sample) (float64, bool) {
+			return float64(s.Metrics.MaxSequenceLength), s.Metrics.MaxSequenceLength > 0
+		},
+		"max_completion_tokens": func(s Sample) (float64, bool) {
+			return float64(s.Metrics.MaxCompletionTokens), s.Metrics.MaxCompletionTokens > 0
+		},
+	}
+	for _, window := range cerebras.Windows {
+		suffix := "_" + strings.ReplaceAll(window, "-", "_")
+		usage := func(s Sample) (cerebras.WindowUsage, bool) {
+			w, err := s.Metrics.Window(window)
+			return w, err == nil
+		}
+		m[MetricLimit+suffix] = func(s Sample) (float64, bool) {
+			v, ok := s.Metrics.LimitField(window).Value()
+			return float64(v), ok
+		}
+		m[MetricUsage+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			return float64(w.Used), ok && (w.Limit.IsKnown() || w.Used > 0)
+		}
+		m[MetricRemaining+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			_, limited := w.Limit.Value()
+			return float64(w.Remaining), ok && limited
+		}
+		m[MetricReset+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			return float64(w.Reset), ok && w.Reset > 0
+		}
+		m[MetricPercent+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			_, limited := w.Limit.Value()
+			return w.Percent(), ok && limited
+		}
+		m[MetricBurnRate+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			if !ok || (!w.Limit.IsKnown() && w.Used == 0) {
+				return 0, false
+			}
+			return pacing.AverageRate(w, s.Time)
+		}
+		m[MetricExhaustsIn+suffix] = func(s Sample) (float64, bool) {
+			w, ok := usage(s)
+			if !ok {
+				return 0, false
+			}
+			rate, ok := pacing.AverageRate(w, s.Time)
+			if !ok {
+				return 0, false
+			}
+			p, ok := pacing.Project(w, rate, s.Time)
+			if !ok || !p.Exhausts {
+				return 0, false
+			}
+			return p.ExhaustsIn.Minutes(), true
+		}
+	}
+	for _, window := range []string{collector.RollupHour, collector.RollupDay} {
+		m[MetricDeviation+"_"+window] = func(s Sample) (float64, bool) {
+			v, ok := s.Deviation[window]
+			return v, ok
+		}
+	}
+	return m
+}
+
+// Value returns the named metric of the sample, or false when it is unknown,
+// such as the percentage of an unlimited window
+func (s Sample) Value(metric string) (float64, bool) {
+	f, ok := metrics[metric]
+	if !ok || s.Metrics == nil {
+		return 0, false
+	}
+	return f(s)
+}
+
+// IsMetric reports whether the name is a known metric
+func IsMetric(name string) bool {
+	_, ok := metrics[name]
+	return ok
+}
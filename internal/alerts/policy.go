@@ -0,0 +1,130 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/viper"
+)
+
+// DefaultCooldown is how long a resolved alert can reopen without notifying
+// again
+const DefaultCooldown = 15 * time.Minute
+
+// Policy controls when alert notifications are delivered
+type Policy struct {
+	// Cooldown suppresses alerts that resolve and fire again within it; the
+	// resolution is only announced once it held for the cooldown
+	Cooldown   time.Duration
+	QuietHours QuietHours
+}
+
+// QuietHours is a daily period in which notifications are held back until it
+// ends. Start and End are times of day; equal values disable it.
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+	// AllowCritical delivers critical alerts during quiet hours
+	AllowCritical bool
+	// Location is the timezone of Start and End, local time when nil
+	Location *time.Location
+}
+
+// Enabled reports whether quiet hours are configured
+func (q QuietHours) Enabled() bool {
+	return q.Start != q.End
+}
+
+// Contains reports whether t falls within the quiet hours. Periods may wrap
+// around midnight, e.g. 22:00 to 07:00.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.Enabled() {
+		return false
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if q.Start < q.End {
+		return offset >= q.Start && offset < q.End
+	}
+	return offset >= q.Start || offset < q.End
+}
+
+// Holds reports whether a notification of the given severity is held back at t
+func (q QuietHours) Holds(severity string, t time.Time) bool {
+	if q.AllowCritical && severity == notify.SeverityCritical {
+		return false
+	}
+	return q.Contains(t)
+}
+
+// String describes the quiet hours, e.g. "22:00-07:00"
+func (q QuietHours) String() string {
+	if !q.Enabled() {
+		return "off"
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(q.Start) + "-" + clock(q.End)
+}
+
+// LoadPolicy reads the alerts section from the configuration. Quiet hours
+// are interpreted in the configured timezone.
+//
+//	alerts:
+//	  cooldown: 900
+//	  quiet-hours:
+//	    start: "22:00"
+//	    end: "07:00"
+//	    allow-critical: true
+func LoadPolicy() (Policy, error) {
+	p := Policy{
+		Cooldown: DefaultCooldown,
+		QuietHours: QuietHours{
+			AllowCritical: true,
+			Location:      config.GetLocation(),
+		},
+	}
+	if viper.IsSet("alerts.cooldown") {
+		seconds := viper.GetFloat64("alerts.cooldown")
+		if seconds < 0 {
+			return Policy{}, fmt.Errorf("alerts.cooldown must not be negative, got %v", seconds)
+		}
+		p.Cooldown = time.Duration(seconds * float64(time.Second))
+	}
+
+	start := viper.GetString("alerts.quiet-hours.start")
+	end := viper.GetString("alerts.quiet-hours.end")
+	if (start == "") != (end == "") {
+		return Policy{}, fmt.Errorf("alerts.quiet-hours needs both start and end")
+	}
+	if start != "" {
+		var err error
+		if p.QuietHours.Start, err = parseClock(start); err != nil {
+			return Policy{}, fmt.Errorf("alerts.quiet-hours: invalid start %q, expected HH:MM", start)
+		}
+		if p.QuietHours.End, err = parseClock(end); err != nil {
+			return Policy{}, fmt.Errorf("alerts.quiet-hours: invalid end %q, expected HH:MM", end)
+		}
+	}
+	if viper.IsSet("alerts.quiet-hours.allow-critical") {
+		p.QuietHours.AllowCritical = viper.GetBool("alerts.quiet-hours.allow-critical")
+	}
+
+	return p, nil
+}
+
+// parseClock parses a HH:MM time of day
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,94 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+// Period is a stretch of consecutive polls in which a rule fired
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Peak is the value furthest past the threshold
+	Peak float64 `json:"peak"`
+}
+
+// ReplayResult is the outcome of replaying one rule against history
+type ReplayResult struct {
+	Rule Rule `json:"rule"`
+	// Polls is the number of snapshots replayed, Unknown how many of them
+	// lacked the metric
+	Polls   int      `json:"polls"`
+	Unknown int      `json:"unknown"`
+	Fired   []Period `json:"fired"`
+}
+
+// Replay evaluates the rules covering the organization and model against
+// recorded snapshots, oldest first, as if each was a poll. Deviation metrics
+// read the rollups of the bucket each snapshot falls in.
+func Replay(rules []Rule, organization, model string, snapshots []db.UsageSnapshot, rollups []db.UsageMetric) []ReplayResult {
+	e := NewEvaluator(rules, organization, model)
+	results := make([]ReplayResult, len(e.rules))
+	for i, r := range e.rules {
+		results[i] = ReplayResult{Rule: r, Fired: []Period{}}
+	}
+
+	deviations := make(map[string]map[time.Time]float64)
+	for _, m := range rollups {
+		if m.DeviationPercentage == nil {
+			continue
+		}
+		if deviations[m.TimeWindow] == nil {
+			deviations[m.TimeWindow] = make(map[time.Time]float64)
+		}
+		deviations[m.TimeWindow][m.Timestamp.UTC()] = *m.DeviationPercentage
+	}
+
+	// firing holds whether each rule fired at the previous snapshot
+	firing := make([]bool, len(e.rules))
+	for _, snap := range snapshots {
+		sample := Sample{Time: snap.Timestamp, Metrics: collector.SnapshotInfo(snap), Deviation: make(map[string]float64)}
+		for window, byBucket := range deviations {
+			if start, err := collector.BucketStart(window, snap.Timestamp); err == nil {
+				if v, ok := byBucket[start.UTC()]; ok {
+					sample.Deviation[window] = v
+				}
+			}
+		}
+
+		fired := make([]bool, len(e.rules))
+		e.evaluate(sample, func(i int, c Condition) {
+			fired[i] = true
+			res := &results[i]
+			if !firing[i] {
+				res.Fired = append(res.Fired, Period{Start: snap.Timestamp, Peak: c.Value})
+			}
+			p := &res.Fired[len(res.Fired)-1]
+			p.End = snap.Timestamp
+			if further(res.Rule, c.Value, p.Peak) {
+				p.Peak = c.Value
+			}
+		})
+		for i, r := range e.rules {
+			results[i].Polls++
+			if _, ok := sample.Value(r.Metric); !ok {
+				results[i].Unknown++
+			}
+		}
+		firing = fired
+	}
+
+	return results
+}
+
+// further reports whether v is further past the rule's threshold than peak
+func further(r Rule, v, peak float64) bool {
+	switch r.Comparison {
+	case "<", "<=":
+		return v < peak
+	default:
+		return v > peak
+	}
+}
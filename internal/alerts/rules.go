@@ -0,0 +1,240 @@
+package alerts
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/viper"
+)
+
+// Comparisons a rule can apply
+var comparisons = []string{">", ">=", "<", "<=", "==", "!="}
+
+// Rule raises an alert when a metric passes a threshold for a number of
+// consecutive polls
+type Rule struct {
+	Name       string  `json:"name"`
+	Metric     string  `json:"metric"`
+	Comparison string  `json:"comparison"`
+	Threshold  float64 `json:"threshold"`
+	// For is the number of consecutive polls the comparison must hold
+	For          int      `json:"for"`
+	Severity     string   `json:"severity"`
+	Organization string   `json:"organization,omitempty"` // empty matches every organization
+	Model        string   `json:"model,omitempty"`        // empty matches every model
+	Notifiers    []string `json:"notifiers,omitempty"`    // empty notifies every notifier
+}
+
+// Applies reports whether the rule covers the organization and model
+func (r Rule) Applies(organization, model string) bool {
+	return (r.Organization == "" || r.Organization == organization) &&
+		(r.Model == "" || r.Model == model)
+}
+
+// Matches reports whether the value passes the rule's comparison
+func (r Rule) Matches(v float64) bool {
+	switch r.Comparison {
+	case ">":
+		return v > r.Threshold
+	case ">=":
+		return v >= r.Threshold
+	case "<":
+		return v < r.Threshold
+	case "<=":
+		return v <= r.Threshold
+	case "==":
+		return v == r.Threshold
+	case "!=":
+		return v != r.Threshold
+	default:
+		return false
+	}
+}
+
+// String describes the condition, e.g. "percent_tokens_day >= 90 for 3 polls"
+func (r Rule) String() string {
+	s := fmt.Sprintf("%s %s %s", r.Metric, r.Comparison, strconv.FormatFloat(r.Threshold, 'f', -1, 64))
+	if r.For > 1 {
+		s += fmt.Sprintf(" for %d polls", r.For)
+	}
+	return s
+}
+
+// condition returns the alert raised by the rule at the given value
+func (r Rule) condition(v float64) Condition {
+	return Condition{
+		Type:      "rule",
+		Metric:    r.Metric,
+		Severity:  r.Severity,
+		Value:     v,
+		Threshold: r.Threshold,
+		Message:   fmt.Sprintf("Rule %s: %s is %s (%s)", r.Name, r.Metric, strconv.FormatFloat(v, 'f', 2, 64), r),
+		Notifiers: r.Notifiers,
+	}
+}
+
+// ruleEntry is the configuration form of a rule
+type ruleEntry struct {
+	Name         string   `mapstructure:"name"`
+	Metric       string   `mapstructure:"metric"`
+	Comparison   string   `mapstructure:"comparison"`
+	Threshold    *float64 `mapstructure:"threshold"`
+	For          int      `mapstructure:"for"`
+	Severity     string   `mapstructure:"severity"`
+	Organization string   `mapstructure:"organization"`
+	Model        string   `mapstructure:"model"`
+	Notifiers    []string `mapstructure:"notifiers"`
+}
+
+// LoadRules reads the alert rules from the configuration, checking notifier
+// targets against the configured notifiers:
+//
+//	alerts:
+//	  rules:
+//	    - name: tokens-day-critical
+//	      metric: percent_tokens_day
+//	      comparison: ">="
+//	      threshold: 90
+//	      for: 3
+//	      severity: critical
+//	      model: qwen-3-coder-480b
+//	      notifiers: [team-chat]
+func LoadRules(notifiers []string) ([]Rule, error) {
+	var entries []ruleEntry
+	if err := viper.UnmarshalKey("alerts.rules", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts.rules: %w", err)
+	}
+
+	rules := make([]Rule, 0, len(entries))
+	names := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		if e.Metric == "" {
+			return nil, fmt.Errorf("alerts.rules[%d]: metric is required", i)
+		}
+		if !IsMetric(e.Metric) {
+			return nil, fmt.Errorf("alerts.rules[%d]: unknown metric %q, expected a rate limit field such as usage_tokens_day or remaining_requests_minute, "+
+				"or a derived metric such as percent_tokens_day, burn_rate_tokens_day, exhausts_in_tokens_day or deviation_hour", i, e.Metric)
+		}
+		if !slices.Contains(comparisons, e.Comparison) {
+			return nil, fmt.Errorf("alerts.rules[%d]: invalid comparison %q, expected one of %v", i, e.Comparison, comparisons)
+		}
+		if e.Threshold == nil {
+			return nil, fmt.Errorf("alerts.rules[%d]: threshold is required", i)
+		}
+		if e.For < 0 {
+			return nil, fmt.Errorf("alerts.rules[%d]: for must be a positive number of polls, got %d", i, e.For)
+		}
+		rule := Rule{
+			Name:         e.Name,
+			Metric:       e.Metric,
+			Comparison:   e.Comparison,
+			Threshold:    *e.Threshold,
+			For:          max(e.For, 1),
+			Severity:     e.Severity,
+			Organization: e.Organization,
+			Model:        e.Model,
+			Notifiers:    e.Notifiers,
+		}
+		if rule.Severity == "" {
+			rule.Severity = notify.SeverityWarning
+		}
+		if notify.SeverityRank(rule.Severity) == 0 {
+			return nil, fmt.Errorf("alerts.rules[%d]: unknown severity %q, expected info, warning or critical", i, rule.Severity)
+		}
+		if rule.Name == "" {
+			rule.Name = rule.String()
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("alerts.rules[%d]: duplicate name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+		for _, n := range rule.Notifiers {
+			if !slices.Contains(notifiers, n) {
+				return nil, fmt.Errorf("alerts.rules[%d]: unknown notifier %q", i, n)
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Evaluator checks rules against the polls of one organization and model
+type Evaluator struct {
+	rules  []Rule
+	streak []int
+}
+
+// NewEvaluator creates an evaluator for the rules covering the organization
+// and model
+func NewEvaluator(rules []Rule, organization, model string) *Evaluator {
+	e := &Evaluator{}
+	for _, r := range rules {
+		if r.Applies(organization, model) {
+			e.rules = append(e.rules, r)
+		}
+	}
+	e.streak = make([]int, len(e.rules))
+	return e
+}
+
+// Rules returns the rules covering the organization and model
+func (e *Evaluator) Rules() []Rule {
+	return e.rules
+}
+
+// Empty reports whether no rule covers the organization and model
+func (e *Evaluator) Empty() bool {
+	return e == nil || len(e.rules) == 0
+}
+
+// NeedsDeviation reports whether a rule reads a deviation metric, which
+// needs the usage history
+func (e *Evaluator) NeedsDeviation() bool {
+	if e == nil {
+		return false
+	}
+	return slices.ContainsFunc(e.rules, func(r Rule) bool {
+		_, ok := deviationWindow(r.Metric)
+		return ok
+	})
+}
+
+// Observe evaluates the rules against the sample and returns the conditions
+// of the rules that held for their number of polls. An unknown metric breaks
+// the streak.
+func (e *Evaluator) Observe(s Sample) []Condition {
+	if e == nil {
+		return nil
+	}
+	var conditions []Condition
+	e.evaluate(s, func(_ int, c Condition) {
+		conditions = append(conditions, c)
+	})
+	return conditions
+}
+
+// evaluate advances the streaks and calls fire with the index and condition
+// of every rule that fires
+func (e *Evaluator) evaluate(s Sample, fire func(i int, c Condition)) {
+	for i, r := range e.rules {
+		v, ok := s.Value(r.Metric)
+		if !ok || !r.Matches(v) {
+			e.streak[i] = 0
+			continue
+		}
+		e.streak[i]++
+		if e.streak[i] >= r.For {
+			fire(i, r.condition(v))
+		}
+	}
+}
+
+// deviationWindow returns the rollup window of a deviation metric
+func deviationWindow(metric string) (string, bool) {
+	window, ok := strings.CutPrefix(metric, MetricDeviation+"_")
+	return window, ok && IsMetric(metric)
+}
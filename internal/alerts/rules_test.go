@@ -0,0 +1,193 @@
+package alerts
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/viper"
+)
+
+func TestSampleValue(t *testing.T) {
+	// 12:00 UTC, halfway through the day window
+	now := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	s := Sample{
+		Time: now,
+		Metrics: &cerebras.RateLimitInfo{
+			LimitTokensDay:     cerebras.LimitFromInt(1000000),
+			RemainingTokensDay: 400000,
+			LimitRequestsDay:   cerebras.LimitUnlimited,
+			UsageRequestsDay:   42,
+		},
+		Deviation: map[string]float64{collector.RollupHour: 35},
+	}
+
+	tests := []struct {
+		metric   string
+		expected float64
+		known    bool
+	}{
+		{"limit_tokens_day", 1000000, true},
+		{"usage_tokens_day", 600000, true},
+		{"remaining_tokens_day", 400000, true},
+		{"percent_tokens_day", 60, true},
+		{"burn_rate_tokens_day", 600000.0 / 720, true},
+		// 400k left at 833 per minute runs out in 480 minutes, before the reset
+		{"exhausts_in_tokens_day", 480, true},
+		{"deviation_hour", 35, true},
+		{"usage_requests_day", 42, true},
+		{"percent_requests_day", 0, false},
+		{"limit_requests_day", 0, false},
+		{"reset_tokens_day", 0, false},
+		{"deviation_day", 0, false},
+		{"usage_tokens_hour", 0, false},
+		{"tokens_day", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			v, ok := s.Value(tt.metric)
+			if ok != tt.known {
+				t.Fatalf("Expected known to be %v, got %v (value %v)", tt.known, ok, v)
+			}
+			if ok && math.Abs(v-tt.expected) > 1e-6 {
+				t.Errorf("Expected %v, got %v", tt.expected, v)
+			}
+		})
+	}
+}
+
+func TestEvaluatorFor(t *testing.T) {
+	rules := []Rule{
+		{Name: "hot", Metric: "percent_tokens_day", Comparison: ">=", Threshold: 90, For: 3, Severity: notify.SeverityCritical, Notifiers: []string{"pager"}},
+		{Name: "other-model", Metric: "percent_tokens_day", Comparison: ">=", Threshold: 0, For: 1, Model: "other"},
+	}
+	e := NewEvaluator(rules, "org", "model")
+	if len(e.Rules()) != 1 {
+		t.Fatalf("Expected the rule of another model to be skipped, got %+v", e.Rules())
+	}
+
+	sample := func(used int64) Sample {
+		return Sample{Time: time.Now(), Metrics: &cerebras.RateLimitInfo{LimitTokensDay: cerebras.LimitFromInt(100), UsageTokensDay: used}}
+	}
+	steps := []struct {
+		used  int64
+		fires bool
+	}{
+		{95, false},
+		{96, false},
+		{97, true},
+		{98, true},
+		{50, false}, // breaks the streak
+		{95, false},
+	}
+	for i, step := range steps {
+		conditions := e.Observe(sample(step.used))
+		if (len(conditions) == 1) != step.fires {
+			t.Fatalf("Step %d: expected firing %v, got %+v", i, step.fires, conditions)
+		}
+		if step.fires {
+			c := conditions[0]
+			if c.Severity != notify.SeverityCritical || c.Value != float64(step.used) || c.Notifiers[0] != "pager" {
+				t.Errorf("Step %d: unexpected condition %+v", i, c)
+			}
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	rule := func(fields map[string]any) map[string]any {
+		r := map[string]any{"metric": "percent_tokens_day", "comparison": ">=", "threshold": 90}
+		for k, v := range fields {
+			r[k] = v
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		rules   []any
+		wantErr string
+	}{
+		{name: "valid", rules: []any{rule(map[string]any{"for": 3, "severity": "critical", "notifiers": []string{"chat"}}), rule(nil)}},
+		{name: "missing metric", rules: []any{rule(map[string]any{"metric": ""})}, wantErr: "metric is required"},
+		{name: "unknown metric", rules: []any{rule(map[string]any{"metric": "tokens_percent"})}, wantErr: `unknown metric "tokens_percent"`},
+		{name: "invalid comparison", rules: []any{rule(map[string]any{"comparison": "=>"})}, wantErr: `invalid comparison "=>"`},
+		{name: "missing threshold", rules: []any{map[string]any{"metric": "percent_tokens_day", "comparison": ">"}}, wantErr: "threshold is required"},
+		{name: "negative for", rules: []any{rule(map[string]any{"for": -1})}, wantErr: "for must be a positive number of polls"},
+		{name: "unknown severity", rules: []any{rule(map[string]any{"severity": "high"})}, wantErr: `unknown severity "high"`},
+		{name: "unknown notifier", rules: []any{rule(map[string]any{"notifiers": []string{"pager"}})}, wantErr: `unknown notifier "pager"`},
+		{name: "duplicate name", rules: []any{rule(map[string]any{"name": "a"}), rule(map[string]any{"name": "a"})}, wantErr: `alerts.rules[1]: duplicate name "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("alerts.rules", tt.rules)
+
+			rules, err := LoadRules([]string{"chat"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rules) != 2 || rules[0].For != 3 || rules[1].For != 1 {
+				t.Fatalf("Unexpected rules: %+v", rules)
+			}
+			if rules[1].Name != "percent_tokens_day >= 90" || rules[1].Severity != notify.SeverityWarning {
+				t.Errorf("Expected a default name and severity, got %+v", rules[1])
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2025, 8, 4, 10, 0, 0, 0, time.UTC)
+	limit := int64(1000)
+	snapshot := func(minute int, used int64) db.UsageSnapshot {
+		return db.UsageSnapshot{Timestamp: start.Add(time.Duration(minute) * time.Minute), TokensUsedDay: &used, TokensLimitDay: &limit}
+	}
+	snapshots := []db.UsageSnapshot{
+		snapshot(0, 800), snapshot(1, 900), snapshot(2, 950), snapshot(3, 920),
+		snapshot(4, 100), snapshot(5, 910),
+	}
+	deviation := 150.0
+	rollups := []db.UsageMetric{{Timestamp: start, TimeWindow: collector.RollupHour, DeviationPercentage: &deviation}}
+	rules := []Rule{
+		{Name: "high", Metric: "percent_tokens_day", Comparison: ">", Threshold: 85, For: 1},
+		{Name: "spike", Metric: "deviation_hour", Comparison: ">=", Threshold: 100, For: 1},
+		{Name: "hourly", Metric: "percent_tokens_hour", Comparison: ">", Threshold: 0, For: 1},
+	}
+
+	results := Replay(rules, "org", "model", snapshots, rollups)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	high := results[0]
+	if len(high.Fired) != 2 {
+		t.Fatalf("Expected 2 firing periods, got %+v", high.Fired)
+	}
+	if p := high.Fired[0]; !p.Start.Equal(start.Add(time.Minute)) || !p.End.Equal(start.Add(3*time.Minute)) || p.Peak != 95 {
+		t.Errorf("Unexpected first period %+v", p)
+	}
+	if p := high.Fired[1]; !p.Start.Equal(start.Add(5*time.Minute)) || p.Peak != 91 {
+		t.Errorf("Unexpected second period %+v", p)
+	}
+	if spike := results[1]; len(spike.Fired) != 1 || spike.Fired[0].Peak != 150 {
+		t.Errorf("Expected the deviation rule to fire once at 150, got %+v", spike.Fired)
+	}
+	if hourly := results[2]; hourly.Unknown != len(snapshots) || len(hourly.Fired) != 0 {
+		t.Errorf("Expected the hour window to be unknown in history, got %+v", hourly)
+	}
+}
@@ -0,0 +1,97 @@
+package billing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultWarnPercent is the share of a budget at which a warning is raised
+const DefaultWarnPercent = 80.0
+
+// Budget severities, matching the alerts table
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Budgets holds the user-defined spending limits. Zero disables a budget.
+type Budgets struct {
+	Daily       float64
+	Monthly     float64
+	WarnPercent float64
+}
+
+// BudgetAlert describes a budget that is close to or over its limit
+type BudgetAlert struct {
+	Period   string  `json:"period"` // "daily" or "monthly"
+	Spent    float64 `json:"spent"`
+	Budget   float64 `json:"budget"`
+	Percent  float64 `json:"percent"`
+	Severity string  `json:"severity"`
+}
+
+// Message returns a human-readable description of the alert
+func (a BudgetAlert) Message(currency string) string {
+	period := a.Period
+	if period != "" {
+		period = strings.ToUpper(period[:1]) + period[1:]
+	}
+	if a.Severity == SeverityCritical {
+		return fmt.Sprintf("%s budget exceeded: %s of %s", period, FormatAmount(a.Spent, currency), FormatAmount(a.Budget, currency))
+	}
+	return fmt.Sprintf("%s budget at %.0f%%: %s of %s", period, a.Percent, FormatAmount(a.Spent, currency), FormatAmount(a.Budget, currency))
+}
+
+// LoadBudgets reads the budgets section from the configuration:
+//
+//	budgets:
+//	  daily: 5
+//	  monthly: 100
+//	  warn-percent: 80
+func LoadBudgets() (Budgets, error) {
+	b := Budgets{
+		Daily:       viper.GetFloat64("budgets.daily"),
+		Monthly:     viper.GetFloat64("budgets.monthly"),
+		WarnPercent: DefaultWarnPercent,
+	}
+	if viper.IsSet("budgets.warn-percent") {
+		b.WarnPercent = viper.GetFloat64("budgets.warn-percent")
+	}
+
+	if b.Daily < 0 || b.Monthly < 0 {
+		return Budgets{}, fmt.Errorf("budgets must not be negative")
+	}
+	if b.WarnPercent <= 0 || b.WarnPercent > 100 {
+		return Budgets{}, fmt.Errorf("budgets.warn-percent must be between 0 and 100, got %v", b.WarnPercent)
+	}
+
+	return b, nil
+}
+
+// Enabled reports whether any budget is configured
+func (b Budgets) Enabled() bool {
+	return b.Daily > 0 || b.Monthly > 0
+}
+
+// Evaluate returns an alert for every budget at or above the warning threshold
+func (b Budgets) Evaluate(spend Spend) []BudgetAlert {
+	var alerts []BudgetAlert
+	check := func(period string, spent, budget float64) {
+		if budget <= 0 {
+			return
+		}
+		percent := spent / budget * 100
+		switch {
+		case percent >= 100:
+			alerts = append(alerts, BudgetAlert{Period: period, Spent: spent, Budget: budget, Percent: percent, Severity: SeverityCritical})
+		case percent >= b.WarnPercent:
+			alerts = append(alerts, BudgetAlert{Period: period, Spent: spent, Budget: budget, Percent: percent, Severity: SeverityWarning})
+		}
+	}
+
+	check("daily", spend.Day, b.Daily)
+	check("monthly", spend.Month, b.Monthly)
+	return alerts
+}
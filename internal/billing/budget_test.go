@@ -0,0 +1,49 @@
+package billing
+
+import (
+	"testing"
+)
+
+func TestBudgetsEvaluate(t *testing.T) {
+	budgets := Budgets{Daily: 10, Monthly: 100, WarnPercent: 80}
+
+	tests := []struct {
+		name     string
+		spend    Spend
+		expected map[string]string
+	}{
+		{
+			name:     "under warning threshold",
+			spend:    Spend{Day: 5, Month: 50},
+			expected: map[string]string{},
+		},
+		{
+			name:     "daily warning",
+			spend:    Spend{Day: 8, Month: 50},
+			expected: map[string]string{"daily": SeverityWarning},
+		},
+		{
+			name:     "daily exceeded and monthly warning",
+			spend:    Spend{Day: 12, Month: 90},
+			expected: map[string]string{"daily": SeverityCritical, "monthly": SeverityWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := budgets.Evaluate(tt.spend)
+			if len(alerts) != len(tt.expected) {
+				t.Fatalf("Expected %d alerts, got %d: %+v", len(tt.expected), len(alerts), alerts)
+			}
+			for _, a := range alerts {
+				if tt.expected[a.Period] != a.Severity {
+					t.Errorf("Expected %s severity %s, got %s", a.Period, tt.expected[a.Period], a.Severity)
+				}
+			}
+		})
+	}
+
+	if alerts := (Budgets{WarnPercent: 80}).Evaluate(Spend{Day: 1000}); len(alerts) != 0 {
+		t.Errorf("Expected no alerts without budgets, got %+v", alerts)
+	}
+}
@@ -0,0 +1,176 @@
+package billing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
+)
+
+// Spend summarizes the estimated cost of the current day, week and month
+type Spend struct {
+	Currency string  `json:"currency"`
+	Day      float64 `json:"day"`
+	Week     float64 `json:"week"`
+	Month    float64 `json:"month"`
+}
+
+// Ledger computes spend from stored usage snapshots and proxy-recorded requests
+type Ledger struct {
+	queries *db.Queries
+	prices  *PriceTable
+}
+
+// NewLedger creates a ledger that prices stored usage with the given table
+func NewLedger(queries *db.Queries, prices *PriceTable) *Ledger {
+	return &Ledger{queries: queries, prices: prices}
+}
+
+// Prices returns the price table used by the ledger
+func (l *Ledger) Prices() *PriceTable {
+	return l.prices
+}
+
+// Spend returns the cost of the day, week (starting Monday) and month
+// containing now in the region (cerebras.AllRegions for every region). Days
+// follow the reset rule, so they match the provider's daily token counter.
+func (l *Ledger) Spend(ctx context.Context, organization, model, region string, rule resets.Rule, now time.Time) (Spend, error) {
+	dayStart := rule.CurrentDay(now)
+	day := dayStart.In(rule.Location)
+	weekday := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	weekStart := dayStart.AddDate(0, 0, -weekday)
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, rule.Location).Add(rule.DayStart)
+
+	spend := Spend{Currency: l.prices.Currency}
+	var err error
+	if spend.Day, err = l.Cost(ctx, organization, model, region, dayStart, now); err != nil {
+		return Spend{}, err
+	}
+	if spend.Week, err = l.Cost(ctx, organization, model, region, weekStart, now); err != nil {
+		return Spend{}, err
+	}
+	if spend.Month, err = l.Cost(ctx, organization, model, region, monthStart, now); err != nil {
+		return Spend{}, err
+	}
+
+	return spend, nil
+}
+
+// Cost returns the cost of the usage of the region recorded in [from, to)
+func (l *Ledger) Cost(ctx context.Context, organization, model, region string, from, to time.Time) (float64, error) {
+	from, to = from.UTC(), to.UTC()
+
+	var base *db.UsageSnapshot
+	prev, err := l.queries.GetLatestUsageSnapshotBefore(ctx, db.GetLatestUsageSnapshotBeforeParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		Timestamp:      from,
+	})
+	if err == nil {
+		base = &prev
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	snapshots, err := l.queries.GetUsageSnapshotsBetween(ctx, db.GetUsageSnapshotsBetweenParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		StartTime:      from,
+		EndTime:        to,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Proxied requests do not say which region served them, so their split
+	// only applies to organization-wide usage
+	var requests []db.RequestUsage
+	if region == cerebras.AllRegions {
+		requests, err = l.queries.GetRequestUsageBetween(ctx, db.GetRequestUsageBetweenParams{
+			OrganizationID: organization,
+			ModelName:      model,
+			StartTime:      from,
+			EndTime:        to,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return l.prices.cost(model, base, snapshots, requests), nil
+}
+
+// cost prices the proxy-recorded requests with their exact prompt/completion
+// split and the remaining snapshot-derived tokens at the blended price
+func (t *PriceTable) cost(model string, base *db.UsageSnapshot, snapshots []db.UsageSnapshot, requests []db.RequestUsage) float64 {
+	var splitCost float64
+	var splitTokens int64
+	for _, r := range requests {
+		price, ok := t.Lookup(model, r.Timestamp)
+		if !ok {
+			continue
+		}
+		splitCost += (float64(r.PromptTokens)*price.Input + float64(r.CompletionTokens)*price.Output) / 1e6
+		splitTokens += r.PromptTokens + r.CompletionTokens
+	}
+
+	var snapshotCost float64
+	var snapshotTokens int64
+	for _, d := range DailyTokenDeltas(base, snapshots) {
+		price, ok := t.Lookup(model, d.At)
+		if !ok {
+			continue
+		}
+		snapshotCost += float64(d.Tokens) * price.Blended(t.PromptRatio) / 1e6
+		snapshotTokens += d.Tokens
+	}
+
+	// Proxy traffic is a subset of what the daily counter saw; only the
+	// remainder needs the blended estimate
+	if snapshotTokens <= splitTokens {
+		return splitCost
+	}
+	unsplit := float64(snapshotTokens-splitTokens) / float64(snapshotTokens)
+	return splitCost + snapshotCost*unsplit
+}
+
+// TokenDelta is the number of tokens consumed between two snapshots
+type TokenDelta struct {
+	At     time.Time
+	Tokens int64
+}
+
+// DailyTokenDeltas converts the cumulative daily token counter of consecutive
+// snapshots into per-interval consumption. A counter that drops is treated as
+// a day reset, in which case the new value is the consumption since the reset.
+// Without a base snapshot the first counter value is counted in full.
+func DailyTokenDeltas(base *db.UsageSnapshot, snapshots []db.UsageSnapshot) []TokenDelta {
+	deltas := make([]TokenDelta, 0, len(snapshots))
+
+	var prev *int64
+	if base != nil {
+		prev = base.TokensUsedDay
+	}
+	for _, s := range snapshots {
+		if s.TokensUsedDay == nil {
+			continue
+		}
+		cur := *s.TokensUsedDay
+		delta := cur
+		if prev != nil && cur >= *prev {
+			delta = cur - *prev
+		}
+		if delta > 0 {
+			deltas = append(deltas, TokenDelta{At: s.Timestamp, Tokens: delta})
+		}
+		prev = s.TokensUsedDay
+	}
+
+	return deltas
+}
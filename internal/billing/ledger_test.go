@@ -0,0 +1,128 @@
+package billing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+func snapshotAt(at time.Time, tokensUsedDay int64) db.UsageSnapshot {
+	return db.UsageSnapshot{Timestamp: at, TokensUsedDay: &tokensUsedDay}
+}
+
+func TestDailyTokenDeltas(t *testing.T) {
+	start := time.Date(2025, 8, 4, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		base      *db.UsageSnapshot
+		snapshots []db.UsageSnapshot
+		expected  []int64
+	}{
+		{
+			name: "counter growing without base",
+			snapshots: []db.UsageSnapshot{
+				snapshotAt(start, 1000),
+				snapshotAt(start.Add(time.Minute), 1500),
+				snapshotAt(start.Add(2*time.Minute), 1500),
+			},
+			expected: []int64{1000, 500},
+		},
+		{
+			name: "base snapshot is subtracted",
+			base: func() *db.UsageSnapshot { s := snapshotAt(start.Add(-time.Minute), 800); return &s }(),
+			snapshots: []db.UsageSnapshot{
+				snapshotAt(start, 1000),
+			},
+			expected: []int64{200},
+		},
+		{
+			name: "counter drop is a day reset",
+			snapshots: []db.UsageSnapshot{
+				snapshotAt(start, 9000),
+				snapshotAt(start.Add(3*time.Hour), 300),
+			},
+			expected: []int64{9000, 300},
+		},
+		{
+			name: "snapshots without daily counter are skipped",
+			snapshots: []db.UsageSnapshot{
+				snapshotAt(start, 100),
+				{Timestamp: start.Add(time.Minute)},
+				snapshotAt(start.Add(2*time.Minute), 250),
+			},
+			expected: []int64{100, 150},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas := DailyTokenDeltas(tt.base, tt.snapshots)
+			if len(deltas) != len(tt.expected) {
+				t.Fatalf("Expected %d deltas, got %d: %+v", len(tt.expected), len(deltas), deltas)
+			}
+			for i, d := range deltas {
+				if d.Tokens != tt.expected[i] {
+					t.Errorf("Expected delta %d to be %d, got %d", i, tt.expected[i], d.Tokens)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceTableCost(t *testing.T) {
+	at := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	model := "qwen-3-coder-480b"
+	table := NewPriceTable("USD", 0.5, []Price{{Model: model, Input: 1, Output: 3}})
+
+	snapshots := []db.UsageSnapshot{
+		snapshotAt(at, 1_000_000),
+		snapshotAt(at.Add(time.Hour), 2_000_000),
+	}
+
+	tests := []struct {
+		name     string
+		requests []db.RequestUsage
+		expected float64
+	}{
+		{
+			name: "snapshots only use the blended price",
+			// 2M tokens at (1*0.5 + 3*0.5) = 2 per million
+			expected: 4,
+		},
+		{
+			name: "proxy split replaces part of the blended estimate",
+			requests: []db.RequestUsage{
+				{Timestamp: at, PromptTokens: 500_000, CompletionTokens: 500_000},
+			},
+			// 0.5*1 + 0.5*3 = 2 for the split, plus 1M unsplit tokens at 2
+			expected: 4,
+		},
+		{
+			name: "proxy split with prompt-heavy traffic",
+			requests: []db.RequestUsage{
+				{Timestamp: at, PromptTokens: 1_000_000},
+			},
+			// 1M prompt tokens at 1, plus 1M unsplit tokens at 2
+			expected: 3,
+		},
+		{
+			name: "proxy covers all tokens",
+			requests: []db.RequestUsage{
+				{Timestamp: at, PromptTokens: 2_000_000, CompletionTokens: 1_000_000},
+			},
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := table.cost(model, nil, snapshots, tt.requests)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Expected cost %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
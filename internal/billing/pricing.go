@@ -0,0 +1,167 @@
+package billing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultPromptRatio is the share of tokens billed at the input price when no
+// prompt/completion split was recorded for them
+const DefaultPromptRatio = 0.8
+
+// WildcardModel matches any model without a more specific price entry
+const WildcardModel = "*"
+
+// Price is the per-million-token price of a model from a given date onward
+type Price struct {
+	Model         string
+	Input         float64
+	Output        float64
+	EffectiveFrom time.Time
+}
+
+// Blended returns the per-million-token price for tokens whose split is
+// unknown, assuming promptRatio of them were input tokens
+func (p Price) Blended(promptRatio float64) float64 {
+	return p.Input*promptRatio + p.Output*(1-promptRatio)
+}
+
+// PriceTable holds the configured prices and resolves them by model and date
+type PriceTable struct {
+	Currency    string
+	PromptRatio float64
+	prices      []Price
+}
+
+// NewPriceTable creates a price table from the given entries
+func NewPriceTable(currency string, promptRatio float64, prices []Price) *PriceTable {
+	sorted := make([]Price, len(prices))
+	copy(sorted, prices)
+	// Newest first so Lookup can stop at the first effective entry
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveFrom.After(sorted[j].EffectiveFrom)
+	})
+
+	return &PriceTable{
+		Currency:    currency,
+		PromptRatio: promptRatio,
+		prices:      sorted,
+	}
+}
+
+// Empty reports whether no prices are configured
+func (t *PriceTable) Empty() bool {
+	return t == nil || len(t.prices) == 0
+}
+
+// Lookup returns the price of the model in effect at the given time. Exact
+// model matches take precedence over the wildcard entry.
+func (t *PriceTable) Lookup(model string, at time.Time) (Price, bool) {
+	if t == nil {
+		return Price{}, false
+	}
+
+	var fallback *Price
+	for i := range t.prices {
+		p := &t.prices[i]
+		if p.EffectiveFrom.After(at) {
+			continue
+		}
+		if p.Model == model {
+			return *p, true
+		}
+		if p.Model == WildcardModel && fallback == nil {
+			fallback = p
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Price{}, false
+}
+
+// priceEntry mirrors a pricing.models entry in settings.yaml
+type priceEntry struct {
+	Model  string  `mapstructure:"model"`
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+	// YAML decodes unquoted dates as timestamps, quoted ones as strings
+	EffectiveFrom interface{} `mapstructure:"effective-from"`
+}
+
+// LoadPriceTable reads the pricing section from the configuration:
+//
+//	pricing:
+//	  currency: USD
+//	  prompt-ratio: 0.8
+//	  models:
+//	    - model: qwen-3-coder-480b
+//	      input: 2.00        # per million input tokens
+//	      output: 2.00       # per million output tokens
+//	      effective-from: 2025-08-01
+func LoadPriceTable() (*PriceTable, error) {
+	currency := viper.GetString("pricing.currency")
+	if currency == "" {
+		currency = "USD"
+	}
+
+	promptRatio := DefaultPromptRatio
+	if viper.IsSet("pricing.prompt-ratio") {
+		promptRatio = viper.GetFloat64("pricing.prompt-ratio")
+		if promptRatio < 0 || promptRatio > 1 {
+			return nil, fmt.Errorf("pricing.prompt-ratio must be between 0 and 1, got %v", promptRatio)
+		}
+	}
+
+	var entries []priceEntry
+	if err := viper.UnmarshalKey("pricing.models", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing.models: %w", err)
+	}
+
+	prices := make([]Price, 0, len(entries))
+	for i, e := range entries {
+		if e.Model == "" {
+			return nil, fmt.Errorf("pricing.models[%d]: model is required", i)
+		}
+		if e.Input < 0 || e.Output < 0 {
+			return nil, fmt.Errorf("pricing.models[%d]: prices must not be negative", i)
+		}
+
+		price := Price{Model: e.Model, Input: e.Input, Output: e.Output}
+		switch v := e.EffectiveFrom.(type) {
+		case nil:
+			// No effective date: the price applies to all usage
+		case time.Time:
+			price.EffectiveFrom = v
+		case string:
+			from, err := time.ParseInLocation("2006-01-02", v, time.UTC)
+			if err != nil {
+				return nil, fmt.Errorf("pricing.models[%d]: invalid effective-from %q, expected YYYY-MM-DD", i, v)
+			}
+			price.EffectiveFrom = from
+		default:
+			return nil, fmt.Errorf("pricing.models[%d]: invalid effective-from %v, expected YYYY-MM-DD", i, v)
+		}
+		prices = append(prices, price)
+	}
+
+	return NewPriceTable(currency, promptRatio, prices), nil
+}
+
+// FormatAmount renders a monetary amount in the table's currency
+func FormatAmount(amount float64, currency string) string {
+	switch currency {
+	case "", "USD":
+		return fmt.Sprintf("$%.2f", amount)
+	case "EUR":
+		return fmt.Sprintf("€%.2f", amount)
+	case "GBP":
+		return fmt.Sprintf("£%.2f", amount)
+	default:
+		return fmt.Sprintf("%.2f %s", amount, currency)
+	}
+}
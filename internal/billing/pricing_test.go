@@ -0,0 +1,203 @@
+package billing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestPriceTableLookup(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatalf("Failed to parse date %s: %v", s, err)
+		}
+		return d
+	}
+
+	table := NewPriceTable("USD", 0.8, []Price{
+		{Model: "qwen-3-coder-480b", Input: 2, Output: 2, EffectiveFrom: date("2025-01-01")},
+		{Model: "qwen-3-coder-480b", Input: 1, Output: 3, EffectiveFrom: date("2025-08-01")},
+		{Model: WildcardModel, Input: 0.5, Output: 0.5},
+	})
+
+	tests := []struct {
+		name          string
+		model         string
+		at            time.Time
+		expectedFound bool
+		expectedInput float64
+	}{
+		{
+			name:          "older price before change",
+			model:         "qwen-3-coder-480b",
+			at:            date("2025-07-31"),
+			expectedFound: true,
+			expectedInput: 2,
+		},
+		{
+			name:          "newer price on effective date",
+			model:         "qwen-3-coder-480b",
+			at:            date("2025-08-01"),
+			expectedFound: true,
+			expectedInput: 1,
+		},
+		{
+			name:          "wildcard before first model price",
+			model:         "qwen-3-coder-480b",
+			at:            date("2024-12-31"),
+			expectedFound: true,
+			expectedInput: 0.5,
+		},
+		{
+			name:          "wildcard for unknown model",
+			model:         "llama-4-scout",
+			at:            date("2025-08-01"),
+			expectedFound: true,
+			expectedInput: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, found := table.Lookup(tt.model, tt.at)
+			if found != tt.expectedFound {
+				t.Fatalf("Expected found %v, got %v", tt.expectedFound, found)
+			}
+			if price.Input != tt.expectedInput {
+				t.Errorf("Expected input price %v, got %v", tt.expectedInput, price.Input)
+			}
+		})
+	}
+
+	empty := NewPriceTable("USD", 0.8, nil)
+	if _, found := empty.Lookup("qwen-3-coder-480b", date("2025-08-01")); found {
+		t.Error("Expected no price from an empty table")
+	}
+}
+
+func TestPriceBlended(t *testing.T) {
+	price := Price{Input: 1, Output: 3}
+	if got := price.Blended(0.75); got != 1.5 {
+		t.Errorf("Expected blended price 1.5, got %v", got)
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	defer viper.Reset()
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+pricing:
+  currency: EUR
+  prompt-ratio: 0.5
+  models:
+    - model: qwen-3-coder-480b
+      input: 2.5
+      output: 4
+      effective-from: 2025-08-01
+`))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	table, err := LoadPriceTable()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if table.Currency != "EUR" {
+		t.Errorf("Expected currency EUR, got %s", table.Currency)
+	}
+	if table.PromptRatio != 0.5 {
+		t.Errorf("Expected prompt ratio 0.5, got %v", table.PromptRatio)
+	}
+
+	price, found := table.Lookup("qwen-3-coder-480b", time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC))
+	if !found {
+		t.Fatal("Expected price to be found")
+	}
+	if price.Input != 2.5 || price.Output != 4 {
+		t.Errorf("Expected prices 2.5/4, got %v/%v", price.Input, price.Output)
+	}
+	if _, found := table.Lookup("qwen-3-coder-480b", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)); found {
+		t.Error("Expected no price before the effective date")
+	}
+}
+
+func TestLoadPriceTableInvalid(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name     string
+		config   string
+		errorMsg string
+	}{
+		{
+			name: "missing model",
+			config: `
+pricing:
+  models:
+    - input: 1
+      output: 1
+`,
+			errorMsg: "model is required",
+		},
+		{
+			name: "bad effective date",
+			config: `
+pricing:
+  models:
+    - model: qwen-3-coder-480b
+      effective-from: August 1st
+`,
+			errorMsg: "invalid effective-from",
+		},
+		{
+			name: "prompt ratio out of range",
+			config: `
+pricing:
+  prompt-ratio: 1.5
+`,
+			errorMsg: "prompt-ratio",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(strings.NewReader(tt.config)); err != nil {
+				t.Fatalf("Failed to read config: %v", err)
+			}
+
+			_, err := LoadPriceTable()
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error to contain '%s', got '%v'", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		expected string
+	}{
+		{amount: 1.234, currency: "USD", expected: "$1.23"},
+		{amount: 10, currency: "", expected: "$10.00"},
+		{amount: 2.5, currency: "EUR", expected: "€2.50"},
+		{amount: 3, currency: "BRL", expected: "3.00 BRL"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, got)
+		}
+	}
+}
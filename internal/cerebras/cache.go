@@ -0,0 +1,131 @@
+package cerebras
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
+)
+
+// metricsCache coalesces concurrent GetMetrics calls and serves repeated
+// reads within a short TTL, so the dashboard, collector and other consumers
+// in one process share a single fetch per refresh
+type metricsCache struct {
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cachedMetrics
+}
+
+// cachedMetrics is a successful fetch and when it completed
+type cachedMetrics struct {
+	info *RateLimitInfo
+	at   time.Time
+}
+
+// CacheTTL returns how long fetched metrics are reused: cache.ttl seconds
+// when set, otherwise half the refresh rate. Zero disables the cache.
+func CacheTTL() time.Duration {
+	if viper.IsSet("cache.ttl") {
+		secs := viper.GetFloat64("cache.ttl")
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	refresh := viper.GetInt("refresh-rate")
+	if refresh < 1 {
+		refresh = 10
+	}
+	return time.Duration(refresh) * time.Second / 2
+}
+
+// GetMetrics fetches usage metrics from Cerebras servers. Concurrent calls
+// for the same organization and model share one fetch, and results younger
+// than the cache TTL are reused. In-flight requests, including waits between
+// retries, are abandoned when ctx is cancelled. Callers get their own copy.
+func (c *Client) GetMetrics(ctx context.Context, organization string) (*RateLimitInfo, error) {
+	return c.GetModelMetrics(ctx, organization, viper.GetString("model"))
+}
+
+// GetModelMetrics is GetMetrics for the given model instead of the configured
+// one. Models other than the configured one need session token auth.
+func (c *Client) GetModelMetrics(ctx context.Context, organization, model string) (*RateLimitInfo, error) {
+	key := organization + "\x00" + model
+
+	if info, ok := c.cache.get(key, c.cacheTTL, time.Now()); ok {
+		return info, nil
+	}
+
+	info, err := c.cache.do(ctx, key, func(ctx context.Context) (*RateLimitInfo, error) {
+		return c.fetchMetrics(ctx, organization, model)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.cacheTTL > 0 {
+		c.cache.put(key, info, time.Now())
+	}
+	return copyMetrics(info), nil
+}
+
+// get returns a copy of a cached result younger than ttl
+func (m *metricsCache) get(key string, ttl time.Duration, now time.Time) (*RateLimitInfo, bool) {
+	if ttl <= 0 {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || now.Sub(entry.at) >= ttl {
+		return nil, false
+	}
+	return copyMetrics(entry.info), true
+}
+
+// put stores a result
+func (m *metricsCache) put(key string, info *RateLimitInfo, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]cachedMetrics)
+	}
+	m.entries[key] = cachedMetrics{info: copyMetrics(info), at: now}
+}
+
+// do runs fetch once for all concurrent callers of key. The shared fetch uses
+// the first caller's context; a caller that is still live when the shared
+// fetch was cancelled by another caller fetches again on its own.
+func (m *metricsCache) do(ctx context.Context, key string, fetch func(context.Context) (*RateLimitInfo, error)) (*RateLimitInfo, error) {
+	ch := m.group.DoChan(key, func() (interface{}, error) {
+		return fetch(ctx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			if ctx.Err() == nil && (errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)) {
+				return fetch(ctx)
+			}
+			return nil, res.Err
+		}
+		return res.Val.(*RateLimitInfo), nil
+	}
+}
+
+// copyMetrics returns a copy, sharing nothing but the regions' own Regions,
+// which are always empty
+func copyMetrics(info *RateLimitInfo) *RateLimitInfo {
+	if info == nil {
+		return nil
+	}
+	c := *info
+	c.Regions = slices.Clone(info.Regions)
+	return &c
+}
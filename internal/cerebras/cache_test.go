@@ -0,0 +1,156 @@
+package cerebras
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// sessionServer answers the GraphQL quota and usage queries and the REST
+// completion probe, counting every request
+func sessionServer(t *testing.T, handle func(r *http.Request)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if handle != nil {
+			handle(r)
+		}
+		if r.URL.Path == "/v1/chat/completions" {
+			w.Header().Set("X-Ratelimit-Reset-Requests-Day", "3600")
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "ListOrganizationUsageQuotas") {
+			_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsageQuotas":[{"modelId":"qwen-3-coder-480b","requestsPerDay":"100"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsage":[{"modelId":"qwen-3-coder-480b","rpd":"40"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestGetMetricsFetchesConcurrently(t *testing.T) {
+	// Every request waits until all three are in flight, so serial fetching
+	// would time out
+	var wg sync.WaitGroup
+	wg.Add(3)
+	all := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(all)
+	}()
+	server, _ := sessionServer(t, func(r *http.Request) {
+		wg.Done()
+		select {
+		case <-all:
+		case <-time.After(2 * time.Second):
+			t.Error("Requests were not sent concurrently")
+		}
+	})
+
+	client := &Client{
+		httpClient:   &http.Client{},
+		apiKey:       "key",
+		sessionToken: "session",
+		baseURL:      server.URL,
+		graphqlURL:   server.URL,
+	}
+	viper.Set("model", "qwen-3-coder-480b")
+	viper.Set("probe.strategy", ProbeCompletion)
+	defer viper.Set("probe.strategy", "")
+
+	info, err := client.GetMetrics(context.Background(), "org1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.UsageRequestsDay != 40 || info.RemainingRequestsDay != 60 || info.ResetRequestsDay != 3600 {
+		t.Errorf("Expected merged GraphQL and REST metrics, got %+v", info)
+	}
+}
+
+func TestGetMetricsCoalescesAndCaches(t *testing.T) {
+	server, requests := sessionServer(t, func(r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	client := &Client{
+		httpClient:   &http.Client{},
+		sessionToken: "session",
+		graphqlURL:   server.URL,
+		cacheTTL:     time.Minute,
+	}
+	viper.Set("model", "qwen-3-coder-480b")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetMetrics(context.Background(), "org1"); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Callers get their own copy, so mutations do not leak into the cache
+	info, err := client.GetMetrics(context.Background(), "org1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info.UsageRequestsDay = 99
+	again, _ := client.GetMetrics(context.Background(), "org1")
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected one quotas and one usage request, got %d requests", got)
+	}
+	if again.UsageRequestsDay != 40 {
+		t.Errorf("Expected cached usage 40, got %d", again.UsageRequestsDay)
+	}
+}
+
+func TestGetMetricsWithoutCache(t *testing.T) {
+	server, requests := sessionServer(t, nil)
+	client := &Client{
+		httpClient:   &http.Client{},
+		sessionToken: "session",
+		graphqlURL:   server.URL,
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetMetrics(context.Background(), "org1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("Expected every call to fetch without a TTL, got %d requests", got)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	defer viper.Set("cache.ttl", nil)
+	defer viper.Set("refresh-rate", nil)
+
+	viper.Set("refresh-rate", 20)
+	if got := CacheTTL(); got != 10*time.Second {
+		t.Errorf("Expected half the refresh rate, got %v", got)
+	}
+	viper.Set("cache.ttl", 2.5)
+	if got := CacheTTL(); got != 2500*time.Millisecond {
+		t.Errorf("Expected 2.5s, got %v", got)
+	}
+	viper.Set("cache.ttl", 0)
+	if got := CacheTTL(); got != 0 {
+		t.Errorf("Expected the cache to be disabled, got %v", got)
+	}
+}
@@ -0,0 +1,115 @@
+package cerebras
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+)
+
+// capturedHeadersFile stores the rate limit headers of the latest real
+// inference response, written by the proxy and read by the REST probe
+const capturedHeadersFile = "captured-headers.json"
+
+// CapturedHeaders are rate limit headers seen on a real API response
+type CapturedHeaders struct {
+	CapturedAt time.Time         `json:"captured_at"`
+	Model      string            `json:"model"`
+	Headers    map[string]string `json:"headers"`
+}
+
+// CapturedHeadersPath returns the location of the captured headers file
+func CapturedHeadersPath() (string, error) {
+	dataDir, err := config.GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, capturedHeadersFile), nil
+}
+
+// SaveCapturedHeaders stores the rate limit headers of a response so pollers
+// can reuse them instead of probing the API
+func SaveCapturedHeaders(model string, header http.Header, at time.Time) error {
+	path, err := CapturedHeadersPath()
+	if err != nil {
+		return err
+	}
+
+	captured := CapturedHeaders{CapturedAt: at.UTC(), Model: model, Headers: make(map[string]string)}
+	for name, values := range header {
+		if len(values) > 0 && strings.HasPrefix(strings.ToLower(name), rateLimitHeaderPrefix) {
+			captured.Headers[http.CanonicalHeaderKey(name)] = values[0]
+		}
+	}
+
+	data, err := json.Marshal(captured)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadCapturedHeaders returns the captured headers for the model when they
+// are younger than maxAge
+func LoadCapturedHeaders(model string, maxAge time.Duration, now time.Time) (*CapturedHeaders, bool) {
+	path, err := CapturedHeadersPath()
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var captured CapturedHeaders
+	if err := json.Unmarshal(data, &captured); err != nil {
+		return nil, false
+	}
+	if captured.Model != model || now.Sub(captured.CapturedAt) > maxAge {
+		return nil, false
+	}
+
+	return &captured, true
+}
+
+// Info parses the captured headers, aging the reset countdowns by the time
+// elapsed since they were captured
+func (h *CapturedHeaders) Info(now time.Time) RateLimitHeaders {
+	header := http.Header{}
+	for name, value := range h.Headers {
+		header.Set(name, value)
+	}
+
+	parsed := ParseRateLimitHeaders(header)
+	ageResets(parsed.Info, now.Sub(h.CapturedAt))
+	return parsed
+}
+
+// ageResets subtracts elapsed time from every known reset countdown
+func ageResets(info *RateLimitInfo, elapsed time.Duration) {
+	secs := int64(elapsed / time.Second)
+	for _, window := range Windows {
+		reset := info.Field(FieldReset, window)
+		if *reset <= 0 {
+			continue
+		}
+		*reset -= secs
+		if *reset < 1 {
+			*reset = 1
+		}
+	}
+}
@@ -1,13 +1,13 @@
 package cerebras
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/transport"
 	"github.com/spf13/viper"
 )
 
@@ -18,14 +18,25 @@ type Client struct {
 	sessionToken string
 	baseURL      string
 	graphqlURL   string
+	// region limits GraphQL quotas to one region, AllRegions for every one
+	region string
+
+	// probe keeps REST probe results between polls
+	probe probeState
+
+	// cache coalesces and reuses GetMetrics results for cacheTTL
+	cache    metricsCache
+	cacheTTL time.Duration
 }
 
 // NewClient creates a new Cerebras API client
 func NewClient() *Client {
 	client := &Client{
-		httpClient: &http.Client{},
+		httpClient: transport.NewClient(transport.LoadConfig()),
 		baseURL:    "https://api.cerebras.ai",
-		graphqlURL: "https://cloud.cerebras.ai/api/graphql",
+		graphqlURL: graphql.DefaultURL,
+		cacheTTL:   CacheTTL(),
+		region:     viper.GetString("region"),
 	}
 
 	// Check for API key in environment variable first
@@ -62,6 +73,21 @@ func (c *Client) APIKey() string {
 	return c.apiKey
 }
 
+// Region returns the region metrics are limited to, AllRegions when they
+// cover every region
+func (c *Client) Region() string {
+	return c.region
+}
+
+// DataSource reports which source GetMetrics prefers for the organization:
+// "session" for GraphQL or "api_key" for REST headers
+func (c *Client) DataSource(organization string) string {
+	if c.sessionToken != "" && organization != "" {
+		return "session"
+	}
+	return "api_key"
+}
+
 // getAuthHeaders returns the appropriate headers for authentication
 func (c *Client) getAuthHeaders() map[string]string {
 	headers := make(map[string]string)
@@ -76,131 +102,11 @@ func (c *Client) getAuthHeaders() map[string]string {
 	return headers
 }
 
-// getGraphQLAuthHeaders returns headers specifically for GraphQL requests
-func (c *Client) getGraphQLAuthHeaders() map[string]string {
-	headers := make(map[string]string)
-
-	// GraphQL requests require session token authentication
-	if c.sessionToken != "" {
-		headers["Cookie"] = fmt.Sprintf("authjs.session-token=%s", c.sessionToken)
-	}
-
-	return headers
-}
-
-// MakeGraphQLRequest makes a GraphQL request to the Cerebras API
-func (c *Client) MakeGraphQLRequest(query string, variables map[string]interface{}) ([]byte, error) {
-	return c.MakeGraphQLRequestWithDebug(query, variables, false)
-}
-
-// MakeGraphQLRequestWithDebug makes a GraphQL request to the Cerebras API with optional debug output
-func (c *Client) MakeGraphQLRequestWithDebug(query string, variables map[string]interface{}, debug bool) ([]byte, error) {
-	return c.MakeGraphQLRequestWithOperationName("", query, variables, debug)
-}
-
-// MakeGraphQLRequestWithOperationName makes a GraphQL request with an operation name
-func (c *Client) MakeGraphQLRequestWithOperationName(operationName, query string, variables map[string]interface{}, debug bool) ([]byte, error) {
-	if c.sessionToken == "" {
-		return nil, fmt.Errorf("GraphQL requests require session token authentication")
-	}
-
-	url := c.graphqlURL
-
-	// Create request body
-	requestBody := map[string]interface{}{
-		"query":     query,
-		"variables": variables,
-	}
-
-	if operationName != "" {
-		requestBody["operationName"] = operationName
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, err
-	}
-
-	if debug {
-		fmt.Printf("Debug: Request URL: %s\n", url)
-		fmt.Printf("Debug: Request Body: %s\n", string(jsonBody))
-	}
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		return nil, err
-	}
-
-	// Add authentication headers (GraphQL requests require session token)
-	headers := c.getGraphQLAuthHeaders()
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	// Add content type header
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add additional headers to match the curl request
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:141.0) Gecko/20100101 Firefox/141.0")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Origin", "https://cloud.cerebras.ai")
-	req.Header.Set("Referer", "https://cloud.cerebras.ai/platform")
-	req.Header.Set("Sec-GPC", "1")
-	req.Header.Set("Alt-Used", "cloud.cerebras.ai")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Sec-Fetch-Dest", "empty")
-	req.Header.Set("Sec-Fetch-Mode", "cors")
-	req.Header.Set("Sec-Fetch-Site", "same-origin")
-	req.Header.Set("Priority", "u=4")
-	req.Header.Set("TE", "trailers")
-
-	if debug {
-		fmt.Printf("Debug: Request Headers:\n")
-		for name, values := range req.Header {
-			for _, value := range values {
-				// Don't print sensitive cookie values in full
-				if name == "Cookie" {
-					fmt.Printf("  %s: [REDACTED]\n", name)
-				} else {
-					fmt.Printf("  %s: %s\n", name, value)
-				}
-			}
-		}
-	}
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if debug {
-		fmt.Printf("Debug: Response Status: %s\n", resp.Status)
-		fmt.Printf("Debug: Response Headers:\n")
-		for name, values := range resp.Header {
-			for _, value := range values {
-				fmt.Printf("  %s: %s\n", name, value)
-			}
-		}
-	}
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if debug {
-		fmt.Printf("Debug: Response Body: %s\n", string(body))
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GraphQL request failed with status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	return body, nil
+// GraphQL returns a GraphQL client sharing this client's session token and
+// HTTP client
+func (c *Client) GraphQL() *graphql.Client {
+	return graphql.NewClient(c.sessionToken).
+		WithHTTPClient(c.httpClient).
+		WithURL(c.graphqlURL).
+		WithDebug(viper.GetBool("debug"))
 }
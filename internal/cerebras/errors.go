@@ -0,0 +1,45 @@
+package cerebras
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
+)
+
+// Error classes returned by the client, matched with errors.Is. GraphQL and
+// REST failures share the same sentinels.
+var (
+	// ErrUnauthorized means no credentials are configured or they were rejected
+	ErrUnauthorized = graphql.ErrUnauthorized
+	// ErrRateLimited means the API answered HTTP 429 without usable headers
+	ErrRateLimited = graphql.ErrRateLimited
+	// ErrNoOrganization means session token auth was used without an organization
+	ErrNoOrganization = errors.New("no organization configured")
+	// ErrNetwork means the API could not be reached
+	ErrNetwork = graphql.ErrNetwork
+	// ErrSchema means a response did not have the expected shape
+	ErrSchema = graphql.ErrSchemaMismatch
+	// ErrModelNotFound means the organization has no quota for the model
+	ErrModelNotFound = errors.New("model not found")
+)
+
+// ModelNotFoundError means the organization has no quota for the requested
+// model. It matches ErrModelNotFound.
+type ModelNotFoundError struct {
+	Model     string
+	Available []string
+}
+
+func (e *ModelNotFoundError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("model %q is not in the organization's quotas", e.Model)
+	}
+	return fmt.Sprintf("model %q is not in the organization's quotas, available: %s", e.Model, strings.Join(e.Available, ", "))
+}
+
+// Is matches ErrModelNotFound
+func (e *ModelNotFoundError) Is(target error) bool {
+	return target == ErrModelNotFound
+}
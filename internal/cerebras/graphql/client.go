@@ -1,27 +1,74 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/transport"
 )
 
+// DefaultURL is the GraphQL endpoint of the Cerebras cloud console
+const DefaultURL = "https://cloud.cerebras.ai/api/graphql"
+
+// browserHeaders are sent with every request; the endpoint serves the web
+// console and rejects requests that do not look like they come from it
+var browserHeaders = map[string]string{
+	"User-Agent":      "Mozilla/5.0 (X11; Linux x86_64; rv:141.0) Gecko/20100101 Firefox/141.0",
+	"Accept":          "application/json",
+	"Accept-Language": "en-US,en;q=0.5",
+	"Origin":          "https://cloud.cerebras.ai",
+	"Referer":         "https://cloud.cerebras.ai/platform",
+	"Sec-GPC":         "1",
+	"Alt-Used":        "cloud.cerebras.ai",
+	"Connection":      "keep-alive",
+	"Sec-Fetch-Dest":  "empty",
+	"Sec-Fetch-Mode":  "cors",
+	"Sec-Fetch-Site":  "same-origin",
+	"Priority":        "u=4",
+	"TE":              "trailers",
+}
+
 // Client represents a GraphQL client for Cerebras API
 type Client struct {
 	httpClient   *http.Client
 	sessionToken string
 	url          string
+	debug        bool
 }
 
 // NewClient creates a new GraphQL client
 func NewClient(sessionToken string) *Client {
 	return &Client{
-		httpClient:   &http.Client{},
+		httpClient:   transport.NewClient(transport.DefaultConfig()),
 		sessionToken: sessionToken,
-		url:          "https://cloud.cerebras.ai/api/graphql",
+		url:          DefaultURL,
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	if httpClient != nil {
+		c.httpClient = httpClient
 	}
+	return c
+}
+
+// WithURL overrides the GraphQL endpoint
+func (c *Client) WithURL(url string) *Client {
+	if url != "" {
+		c.url = url
+	}
+	return c
+}
+
+// WithDebug enables printing requests and responses to stdout
+func (c *Client) WithDebug(debug bool) *Client {
+	c.debug = debug
+	return c
 }
 
 // HasAuth checks if the client has session token authentication configured
@@ -46,21 +93,18 @@ func (c *Client) getAuthHeaders() map[string]string {
 	return headers
 }
 
-// MakeRequest makes a GraphQL request to the Cerebras API
-func (c *Client) MakeRequest(query string, variables map[string]interface{}) ([]byte, error) {
-	return c.MakeRequestWithOperationName("", query, variables)
-}
-
 // ValidateAuth checks if the client has proper authentication for GraphQL requests
 func (c *Client) ValidateAuth() error {
 	if c.sessionToken == "" {
-		return fmt.Errorf("GraphQL requests require session token authentication")
+		return errMissingSessionToken{}
 	}
 	return nil
 }
 
-// MakeRequestWithOperationName makes a GraphQL request with an operation name
-func (c *Client) MakeRequestWithOperationName(operationName, query string, variables map[string]interface{}) ([]byte, error) {
+// do sends a GraphQL request that is cancelled with ctx and returns the raw
+// response body. HTTP-level failures are typed here; the errors envelope is
+// left to Query.
+func (c *Client) do(ctx context.Context, operationName, query string, variables map[string]interface{}) ([]byte, error) {
 	if err := c.ValidateAuth(); err != nil {
 		return nil, err
 	}
@@ -80,7 +124,12 @@ func (c *Client) MakeRequestWithOperationName(operationName, query string, varia
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.url, strings.NewReader(string(jsonBody)))
+	if c.debug {
+		fmt.Printf("Debug: Request URL: %s\n", c.url)
+		fmt.Printf("Debug: Request Body: %s\n", string(jsonBody))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, strings.NewReader(string(jsonBody)))
 	if err != nil {
 		return nil, err
 	}
@@ -93,26 +142,31 @@ func (c *Client) MakeRequestWithOperationName(operationName, query string, varia
 
 	// Add content type header
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range browserHeaders {
+		req.Header.Set(key, value)
+	}
 
-	// Add additional headers to match the curl request
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:141.0) Gecko/20100101 Firefox/141.0")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Origin", "https://cloud.cerebras.ai")
-	req.Header.Set("Referer", "https://cloud.cerebras.ai/platform")
-	req.Header.Set("Sec-GPC", "1")
-	req.Header.Set("Alt-Used", "cloud.cerebras.ai")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Sec-Fetch-Dest", "empty")
-	req.Header.Set("Sec-Fetch-Mode", "cors")
-	req.Header.Set("Sec-Fetch-Site", "same-origin")
-	req.Header.Set("Priority", "u=4")
-	req.Header.Set("TE", "trailers")
+	if c.debug {
+		fmt.Printf("Debug: Request Headers:\n")
+		for name, values := range req.Header {
+			for _, value := range values {
+				// Don't print sensitive cookie values in full
+				if name == "Cookie" {
+					fmt.Printf("  %s: [REDACTED]\n", name)
+				} else {
+					fmt.Printf("  %s: %s\n", name, value)
+				}
+			}
+		}
+	}
 
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -124,9 +178,96 @@ func (c *Client) MakeRequestWithOperationName(operationName, query string, varia
 		return nil, err
 	}
 
+	if c.debug {
+		fmt.Printf("Debug: Response Status: %s\n", resp.Status)
+		fmt.Printf("Debug: Response Body: %s\n", string(body))
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, &UnauthorizedError{Operation: operationLabel(operationName), StatusCode: resp.StatusCode}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%s: %w", operationLabel(operationName), ErrRateLimited)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("GraphQL request failed with status code: %d, body: %s", resp.StatusCode, string(body))
 	}
 
 	return body, nil
 }
+
+// Query runs an operation and decodes the field of its data into out. Errors
+// in the response are returned as UnauthorizedError, ResponseError or, when
+// data came back too, PartialDataError with out still populated.
+func (c *Client) Query(ctx context.Context, operationName, query string, variables map[string]interface{}, field string, out interface{}) error {
+	body, err := c.do(ctx, operationName, query, variables)
+	if err != nil {
+		return err
+	}
+
+	operation := operationLabel(operationName)
+	var envelope struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors Errors                     `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return &SchemaMismatchError{Operation: operation, Field: "envelope", Err: err}
+	}
+
+	for _, e := range envelope.Errors {
+		if e.isAuth() {
+			return &UnauthorizedError{Operation: operation, StatusCode: http.StatusOK, Message: e.Message}
+		}
+	}
+
+	raw, ok := envelope.Data[field]
+	if !ok || string(raw) == "null" {
+		if len(envelope.Errors) > 0 {
+			return &ResponseError{Operation: operation, Errors: envelope.Errors}
+		}
+		return &SchemaMismatchError{Operation: operation, Field: field}
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return &SchemaMismatchError{Operation: operation, Field: field, Err: err}
+	}
+
+	if len(envelope.Errors) > 0 {
+		return &PartialDataError{Operation: operation, Errors: envelope.Errors}
+	}
+	return nil
+}
+
+// ListOrganizations returns the organizations the session belongs to
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	var orgs []Organization
+	err := c.Query(ctx, "ListMyOrganizations", ListOrganizationsQuery, map[string]interface{}{}, "ListMyOrganizations", &orgs)
+	return orgs, err
+}
+
+// ListOrganizationUsageQuotas returns the per-model quotas of an organization
+// in one region, or in every region when regionID is empty
+func (c *Client) ListOrganizationUsageQuotas(ctx context.Context, organizationID, regionID string) ([]UsageQuota, error) {
+	var quotas []UsageQuota
+	variables := map[string]interface{}{"organizationId": organizationID}
+	if regionID != "" {
+		variables["regionId"] = regionID
+	}
+	err := c.Query(ctx, "ListOrganizationUsageQuotas", ListOrganizationUsageQuotasQuery, variables, "ListOrganizationUsageQuotas", &quotas)
+	return quotas, err
+}
+
+// ListOrganizationUsage returns the per-model usage of an organization
+func (c *Client) ListOrganizationUsage(ctx context.Context, organizationID string) ([]OrganizationUsage, error) {
+	var usage []OrganizationUsage
+	variables := map[string]interface{}{"organizationId": organizationID}
+	err := c.Query(ctx, "ListOrganizationUsage", ListOrganizationUsageQuery, variables, "ListOrganizationUsage", &usage)
+	return usage, err
+}
+
+// operationLabel names an operation in error messages
+func operationLabel(operationName string) string {
+	if operationName == "" {
+		return "GraphQL request"
+	}
+	return operationName
+}
@@ -1,8 +1,9 @@
 package graphql
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -174,7 +175,7 @@ func TestClientInitialization(t *testing.T) {
 	}
 }
 
-func TestMakeRequestWithOperationName(t *testing.T) {
+func TestQueryRequest(t *testing.T) {
 	// Test data
 	testQuery := `query TestQuery { test }`
 	testVariables := map[string]interface{}{"key": "value"}
@@ -244,23 +245,24 @@ func TestMakeRequestWithOperationName(t *testing.T) {
 	}
 
 	// Execute request
-	response, err := client.MakeRequestWithOperationName(testOperationName, testQuery, testVariables)
-	if err != nil {
-		t.Fatalf("MakeRequestWithOperationName failed: %v", err)
+	var result string
+	if err := client.Query(context.Background(), testOperationName, testQuery, testVariables, "test", &result); err != nil {
+		t.Fatalf("Query failed: %v", err)
 	}
 
 	// Verify response
-	if !bytes.Equal(response, testResponseBody) {
-		t.Errorf("Expected response body '%s', got '%s'", string(testResponseBody), string(response))
+	if result != "result" {
+		t.Errorf("Expected field 'result', got '%s'", result)
 	}
 }
 
-func TestMakeRequestWithOperationNameUnauthorized(t *testing.T) {
+func TestQueryWithoutSessionToken(t *testing.T) {
 	// Create client without session token
 	client := NewClient("")
 
 	// Execute request
-	_, err := client.MakeRequestWithOperationName("TestQuery", `query TestQuery { test }`, map[string]interface{}{})
+	var result string
+	err := client.Query(context.Background(), "TestQuery", `query TestQuery { test }`, map[string]interface{}{}, "test", &result)
 	if err == nil {
 		t.Fatal("Expected error for unauthorized request, got nil")
 	}
@@ -271,7 +273,7 @@ func TestMakeRequestWithOperationNameUnauthorized(t *testing.T) {
 	}
 }
 
-func TestMakeRequestWithOperationNameHTTPError(t *testing.T) {
+func TestQueryHTTPError(t *testing.T) {
 	// Create test server that returns error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -286,7 +288,8 @@ func TestMakeRequestWithOperationNameHTTPError(t *testing.T) {
 	}
 
 	// Execute request
-	_, err := client.MakeRequestWithOperationName("TestQuery", `query TestQuery { test }`, map[string]interface{}{})
+	var result string
+	err := client.Query(context.Background(), "TestQuery", `query TestQuery { test }`, map[string]interface{}{}, "test", &result)
 	if err == nil {
 		t.Fatal("Expected error for HTTP error, got nil")
 	}
@@ -381,53 +384,152 @@ func TestListOrganizations(t *testing.T) {
 	client.url = server.URL
 
 	// Execute request
-	responseBody, err := client.MakeRequestWithOperationName("ListMyOrganizations", ListOrganizationsQuery, map[string]interface{}{})
+	orgs, err := client.ListOrganizations(context.Background())
 	if err != nil {
 		t.Fatalf("ListOrganizations failed: %v", err)
 	}
 
-	// Verify response structure
-	var response struct {
-		Data struct {
-			ListMyOrganizations []map[string]interface{} `json:"ListMyOrganizations"`
-		} `json:"data"`
+	if len(orgs) != 2 {
+		t.Fatalf("Expected 2 organizations, got %d", len(orgs))
 	}
 
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		t.Fatalf("Failed to unmarshal response: %v", err)
+	// Verify first organization data
+	if orgs[0].ID != "org1" {
+		t.Errorf("Expected first org ID 'org1', got '%s'", orgs[0].ID)
 	}
-
-	if len(response.Data.ListMyOrganizations) != 2 {
-		t.Errorf("Expected 2 organizations, got %d", len(response.Data.ListMyOrganizations))
+	if orgs[0].Name != "Test Organization 1" {
+		t.Errorf("Expected first org name 'Test Organization 1', got '%s'", orgs[0].Name)
+	}
+	if orgs[0].OrganizationType != "personal" {
+		t.Errorf("Expected first org type 'personal', got '%s'", orgs[0].OrganizationType)
+	}
+	if orgs[0].State != "active" {
+		t.Errorf("Expected first org state 'active', got '%s'", orgs[0].State)
 	}
 
-	// Verify first organization data
-	org1 := response.Data.ListMyOrganizations[0]
-	if org1["id"] != "org1" {
-		t.Errorf("Expected first org ID 'org1', got '%s'", org1["id"])
+	// Verify second organization data
+	if orgs[1].ID != "org2" {
+		t.Errorf("Expected second org ID 'org2', got '%s'", orgs[1].ID)
 	}
-	if org1["name"] != "Test Organization 1" {
-		t.Errorf("Expected first org name 'Test Organization 1', got '%s'", org1["name"])
+	if orgs[1].Name != "Test Organization 2" {
+		t.Errorf("Expected second org name 'Test Organization 2', got '%s'", orgs[1].Name)
 	}
-	if org1["organizationType"] != "personal" {
-		t.Errorf("Expected first org type 'personal', got '%s'", org1["organizationType"])
+	if orgs[1].OrganizationType != "team" {
+		t.Errorf("Expected second org type 'team', got '%s'", orgs[1].OrganizationType)
 	}
-	if org1["state"] != "active" {
-		t.Errorf("Expected first org state 'active', got '%s'", org1["state"])
+	if orgs[1].State != "active" {
+		t.Errorf("Expected second org state 'active', got '%s'", orgs[1].State)
 	}
+}
 
-	// Verify second organization data
-	org2 := response.Data.ListMyOrganizations[1]
-	if org2["id"] != "org2" {
-		t.Errorf("Expected second org ID 'org2', got '%s'", org2["id"])
+// newTestServer returns a client whose requests are answered with status and body
+func newTestServer(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("test-session-token").WithURL(server.URL)
+}
+
+func TestListOrganizationUsageQuotasDecodes(t *testing.T) {
+	client := newTestServer(t, http.StatusOK, `{"data":{"ListOrganizationUsageQuotas":[
+		{"modelId":"qwen-3-coder-480b","requestsPerMinute":"50","tokensPerDay":"-1"}]}}`)
+
+	quotas, err := client.ListOrganizationUsageQuotas(context.Background(), "org1", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
 	}
-	if org2["name"] != "Test Organization 2" {
-		t.Errorf("Expected second org name 'Test Organization 2', got '%s'", org2["name"])
+	if len(quotas) != 1 || quotas[0].ModelId != "qwen-3-coder-480b" || quotas[0].RequestsPerMinute != "50" || quotas[0].TokensPerDay != "-1" {
+		t.Errorf("Unexpected quotas: %+v", quotas)
 	}
-	if org2["organizationType"] != "team" {
-		t.Errorf("Expected second org type 'team', got '%s'", org2["organizationType"])
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, err error, usage []OrganizationUsage)
+	}{
+		{
+			name:   "HTTP 401",
+			status: http.StatusUnauthorized,
+			body:   `{}`,
+			check: func(t *testing.T, err error, _ []OrganizationUsage) {
+				if !errors.Is(err, ErrUnauthorized) {
+					t.Errorf("Expected ErrUnauthorized, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "auth error with HTTP 200",
+			status: http.StatusOK,
+			body:   `{"data":null,"errors":[{"message":"Unauthorized","extensions":{"code":"UNAUTHENTICATED"}}]}`,
+			check: func(t *testing.T, err error, _ []OrganizationUsage) {
+				if !errors.Is(err, ErrUnauthorized) {
+					t.Errorf("Expected ErrUnauthorized, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "errors without data",
+			status: http.StatusOK,
+			body:   `{"data":{"ListOrganizationUsage":null},"errors":[{"message":"organization not found","path":["ListOrganizationUsage"]}]}`,
+			check: func(t *testing.T, err error, _ []OrganizationUsage) {
+				var respErr *ResponseError
+				if !errors.As(err, &respErr) || len(respErr.Errors) != 1 {
+					t.Fatalf("Expected ResponseError, got %v", err)
+				}
+				if respErr.Errors[0].Error() != "ListOrganizationUsage: organization not found" {
+					t.Errorf("Unexpected message %q", respErr.Errors[0].Error())
+				}
+			},
+		},
+		{
+			name:   "partial data",
+			status: http.StatusOK,
+			body:   `{"data":{"ListOrganizationUsage":[{"modelId":"m","rpm":"3"}]},"errors":[{"message":"region unavailable"}]}`,
+			check: func(t *testing.T, err error, usage []OrganizationUsage) {
+				var partial *PartialDataError
+				if !errors.As(err, &partial) {
+					t.Fatalf("Expected PartialDataError, got %v", err)
+				}
+				if len(usage) != 1 || usage[0].RPM != "3" {
+					t.Errorf("Expected partial data to be decoded, got %+v", usage)
+				}
+			},
+		},
+		{
+			name:   "missing field",
+			status: http.StatusOK,
+			body:   `{"data":{"SomethingElse":[]}}`,
+			check: func(t *testing.T, err error, _ []OrganizationUsage) {
+				var mismatch *SchemaMismatchError
+				if !errors.As(err, &mismatch) || mismatch.Field != "ListOrganizationUsage" {
+					t.Errorf("Expected SchemaMismatchError for ListOrganizationUsage, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "wrong shape",
+			status: http.StatusOK,
+			body:   `{"data":{"ListOrganizationUsage":{"rpm":1}}}`,
+			check: func(t *testing.T, err error, _ []OrganizationUsage) {
+				var mismatch *SchemaMismatchError
+				if !errors.As(err, &mismatch) || mismatch.Err == nil {
+					t.Errorf("Expected SchemaMismatchError with a decode error, got %v", err)
+				}
+			},
+		},
 	}
-	if org2["state"] != "active" {
-		t.Errorf("Expected second org state 'active', got '%s'", org2["state"])
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestServer(t, tt.status, tt.body)
+			usage, err := client.ListOrganizationUsage(context.Background(), "org1")
+			tt.check(t, err, usage)
+		})
 	}
 }
@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error classes, matched with errors.Is
+var (
+	// ErrUnauthorized matches every UnauthorizedError
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited is returned when the endpoint answers HTTP 429
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNetwork wraps transport failures such as DNS errors and timeouts
+	ErrNetwork = errors.New("network error")
+	// ErrSchemaMismatch matches every SchemaMismatchError
+	ErrSchemaMismatch = errors.New("unexpected response schema")
+)
+
+// Error is one entry of the errors array of a GraphQL response
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Error returns the message, prefixed with the path when there is one
+func (e Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	parts := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		parts[i] = fmt.Sprint(p)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(parts, "."), e.Message)
+}
+
+// Code returns the extensions.code of the error, if any
+func (e Error) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// isAuth reports whether the error says the session is missing or expired.
+// The API answers expired sessions with HTTP 200 and one of these errors.
+func (e Error) isAuth() bool {
+	switch strings.ToUpper(e.Code()) {
+	case "UNAUTHENTICATED", "UNAUTHORIZED", "FORBIDDEN":
+		return true
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "unauthorized") ||
+		strings.Contains(msg, "unauthenticated") ||
+		strings.Contains(msg, "not authenticated")
+}
+
+// Errors is the errors array of a GraphQL response
+type Errors []Error
+
+// Error joins the messages of every entry
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// UnauthorizedError is returned when the session token was rejected, either
+// with an HTTP status or with an authentication error in the response
+type UnauthorizedError struct {
+	Operation  string
+	StatusCode int
+	Message    string
+}
+
+func (e *UnauthorizedError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s unauthorized: %s (session token may be expired, run login again)", e.Operation, msg)
+}
+
+// Is makes errors.Is(err, ErrUnauthorized) true
+func (e *UnauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// errMissingSessionToken is returned before sending a request without a
+// session token
+type errMissingSessionToken struct{}
+
+func (errMissingSessionToken) Error() string {
+	return "GraphQL requests require session token authentication"
+}
+
+// Is makes errors.Is(err, ErrUnauthorized) true
+func (errMissingSessionToken) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// ResponseError is returned when the response carries errors and no data
+type ResponseError struct {
+	Operation string
+	Errors    Errors
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Operation, e.Errors.Error())
+}
+
+// PartialDataError is returned alongside decoded data when the response
+// carries both data and errors. The data may be incomplete but is usable.
+type PartialDataError struct {
+	Operation string
+	Errors    Errors
+}
+
+func (e *PartialDataError) Error() string {
+	return fmt.Sprintf("%s returned partial data: %s", e.Operation, e.Errors.Error())
+}
+
+// SchemaMismatchError is returned when the response cannot be decoded into
+// the expected shape, usually because the API schema changed
+type SchemaMismatchError struct {
+	Operation string
+	Field     string
+	Err       error
+}
+
+func (e *SchemaMismatchError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s: response has no %s field", e.Operation, e.Field)
+	}
+	return fmt.Sprintf("%s: unexpected %s in response: %v", e.Operation, e.Field, e.Err)
+}
+
+func (e *SchemaMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrSchemaMismatch) true
+func (e *SchemaMismatchError) Is(target error) bool {
+	return target == ErrSchemaMismatch
+}
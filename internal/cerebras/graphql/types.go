@@ -0,0 +1,40 @@
+package graphql
+
+// Organization is an organization returned by ListMyOrganizations
+type Organization struct {
+	ID               string `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	OrganizationType string `json:"organizationType,omitempty"`
+	State            string `json:"state,omitempty"`
+	Typename         string `json:"__typename,omitempty"`
+}
+
+// UsageQuota is a per-model quota returned by ListOrganizationUsageQuotas.
+// Quotas are decimal strings and "-1" means unlimited.
+type UsageQuota struct {
+	ModelId             string `json:"modelId,omitempty"`
+	RegionId            string `json:"regionId,omitempty"`
+	OrganizationId      string `json:"organizationId,omitempty"`
+	RequestsPerMinute   string `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute     string `json:"tokensPerMinute,omitempty"`
+	RequestsPerHour     string `json:"requestsPerHour,omitempty"`
+	TokensPerHour       string `json:"tokensPerHour,omitempty"`
+	RequestsPerDay      string `json:"requestsPerDay,omitempty"`
+	TokensPerDay        string `json:"tokensPerDay,omitempty"`
+	MaxSequenceLength   string `json:"maxSequenceLength,omitempty"`
+	MaxCompletionTokens string `json:"maxCompletionTokens,omitempty"`
+	Typename            string `json:"__typename,omitempty"`
+}
+
+// OrganizationUsage is the per-model usage returned by ListOrganizationUsage
+type OrganizationUsage struct {
+	ModelId  string `json:"modelId,omitempty"`  // Model identifier
+	RegionId string `json:"regionId,omitempty"` // Region identifier
+	RPM      string `json:"rpm,omitempty"`      // Requests Per Minute
+	TPM      string `json:"tpm,omitempty"`      // Tokens Per Minute
+	RPH      string `json:"rph,omitempty"`      // Requests Per Hour
+	TPH      string `json:"tph,omitempty"`      // Tokens Per Hour
+	RPD      string `json:"rpd,omitempty"`      // Requests Per Day
+	TPD      string `json:"tpd,omitempty"`      // Tokens Per Day
+	Typename string `json:"__typename,omitempty"`
+}
@@ -0,0 +1,99 @@
+package cerebras
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rateLimitHeaderPrefix starts every rate limit response header
+const rateLimitHeaderPrefix = "x-ratelimit-"
+
+// RateLimitHeaders is the result of parsing the rate limit headers of a
+// response from the inference API
+type RateLimitHeaders struct {
+	Info *RateLimitInfo
+	// Found reports whether any recognized rate limit header was present
+	Found bool
+	// Unknown holds rate limit headers that did not match a known window or
+	// whose value could not be parsed, keyed by canonical header name
+	Unknown map[string]string
+}
+
+// ParseRateLimitHeaders reads every X-Ratelimit-{Limit,Remaining,Reset}-
+// {Requests,Tokens}-{Minute,Hour,Day} header into a RateLimitInfo and
+// derives usage for windows that report both limit and remaining
+func ParseRateLimitHeaders(header http.Header) RateLimitHeaders {
+	parsed := RateLimitHeaders{
+		Info:    &RateLimitInfo{},
+		Unknown: make(map[string]string),
+	}
+	remainingSeen := make(map[string]bool)
+
+	for name, values := range header {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, rateLimitHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+
+		kind, window, _ := strings.Cut(strings.TrimPrefix(lower, rateLimitHeaderPrefix), "-")
+		value, err := parseHeaderValue(kind, values[0])
+		limit := parsed.Info.LimitField(window)
+		field := parsed.Info.Field(kind, window)
+		switch {
+		case err != nil || limit == nil:
+			parsed.Unknown[http.CanonicalHeaderKey(name)] = values[0]
+			continue
+		case kind == FieldLimit:
+			*limit = LimitFromInt(value)
+		case field != nil && kind != FieldUsage:
+			*field = value
+		default:
+			parsed.Unknown[http.CanonicalHeaderKey(name)] = values[0]
+			continue
+		}
+		parsed.Found = true
+		if kind == FieldRemaining {
+			remainingSeen[window] = true
+		}
+	}
+
+	for _, window := range Windows {
+		limit, ok := parsed.Info.LimitField(window).Value()
+		if !ok || !remainingSeen[window] {
+			continue
+		}
+		used := limit - *parsed.Info.Field(FieldRemaining, window)
+		if used < 0 {
+			used = 0
+		}
+		*parsed.Info.Field(FieldUsage, window) = used
+	}
+
+	return parsed
+}
+
+// UnknownNames returns the unknown header names in sorted order
+func (h RateLimitHeaders) UnknownNames() []string {
+	names := make([]string, 0, len(h.Unknown))
+	for name := range h.Unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseHeaderValue parses a header value. Resets are fractional seconds and
+// may carry an "s" suffix; limits and remaining counts are integers.
+func parseHeaderValue(kind, value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if kind == FieldReset {
+		secs, err := strconv.ParseFloat(strings.TrimSuffix(value, "s"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return int64(secs), nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,59 @@
+package cerebras
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Ratelimit-Limit-Requests-Minute", "30")
+	header.Set("X-Ratelimit-Remaining-Requests-Minute", "28")
+	header.Set("X-Ratelimit-Reset-Requests-Minute", "12.7s")
+	header.Set("X-Ratelimit-Limit-Tokens-Hour", "1000000")
+	header.Set("X-Ratelimit-Limit-Tokens-Day", "1000000")
+	header.Set("X-Ratelimit-Remaining-Tokens-Day", "999000")
+	header.Set("X-Ratelimit-Limit-Requests-Week", "100")
+	header.Set("X-Ratelimit-Remaining-Tokens-Minute", "lots")
+	header.Set("Content-Type", "application/json")
+
+	parsed := ParseRateLimitHeaders(header)
+	if !parsed.Found {
+		t.Fatal("Expected rate limit headers to be found")
+	}
+
+	info := parsed.Info
+	if info.LimitRequestsMinute != 30 || info.RemainingRequestsMinute != 28 || info.ResetRequestsMinute != 12 {
+		t.Errorf("Unexpected requests-minute window: %+v", info)
+	}
+	if info.UsageRequestsMinute != 2 {
+		t.Errorf("Expected derived UsageRequestsMinute 2, got %d", info.UsageRequestsMinute)
+	}
+	if info.LimitTokensHour != 1000000 || info.UsageTokensHour != 0 {
+		t.Errorf("Expected tokens-hour limit without derived usage, got %d/%d", info.UsageTokensHour, info.LimitTokensHour)
+	}
+	if info.UsageTokensDay != 1000 {
+		t.Errorf("Expected derived UsageTokensDay 1000, got %d", info.UsageTokensDay)
+	}
+
+	expectedUnknown := []string{"X-Ratelimit-Limit-Requests-Week", "X-Ratelimit-Remaining-Tokens-Minute"}
+	names := parsed.UnknownNames()
+	if len(names) != len(expectedUnknown) {
+		t.Fatalf("Expected unknown headers %v, got %v", expectedUnknown, names)
+	}
+	for i, name := range expectedUnknown {
+		if names[i] != name {
+			t.Errorf("Expected unknown header %s, got %s", name, names[i])
+		}
+	}
+}
+
+func TestParseRateLimitHeadersNone(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	parsed := ParseRateLimitHeaders(header)
+	if parsed.Found || len(parsed.Unknown) != 0 {
+		t.Errorf("Expected nothing parsed, got %+v", parsed)
+	}
+}
@@ -0,0 +1,152 @@
+package cerebras
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limit is a rate limit quota. Positive values are numeric limits; the zero
+// value means the limit is unknown and LimitUnlimited means there is none.
+type Limit int64
+
+const (
+	// LimitUnknown is a limit no source has reported
+	LimitUnknown Limit = 0
+	// LimitUnlimited is a window without a quota, reported by the API as -1
+	LimitUnlimited Limit = -1
+)
+
+// ParseLimit parses a quota as returned by the API: "-1" is unlimited, an
+// empty or unparseable value is unknown
+func ParseLimit(s string) Limit {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return LimitUnknown
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return LimitUnknown
+	}
+	return LimitFromInt(v)
+}
+
+// LimitFromInt converts a raw quota, mapping any negative value to unlimited
+func LimitFromInt(v int64) Limit {
+	if v < 0 {
+		return LimitUnlimited
+	}
+	return Limit(v)
+}
+
+// LimitFromDB converts a nullable database column, where NULL is unknown and
+// -1 is unlimited
+func LimitFromDB(v *int64) Limit {
+	if v == nil {
+		return LimitUnknown
+	}
+	return LimitFromInt(*v)
+}
+
+// IsUnlimited reports whether the window has no quota
+func (l Limit) IsUnlimited() bool {
+	return l == LimitUnlimited
+}
+
+// IsKnown reports whether the limit is numeric or unlimited
+func (l Limit) IsKnown() bool {
+	return l != LimitUnknown
+}
+
+// Value returns the numeric limit, or false when it is unknown or unlimited
+func (l Limit) Value() (int64, bool) {
+	if l <= 0 {
+		return 0, false
+	}
+	return int64(l), true
+}
+
+// Remaining returns limit - used, clamped at 0, or false when the limit is
+// not numeric
+func (l Limit) Remaining(used int64) (int64, bool) {
+	v, ok := l.Value()
+	if !ok {
+		return 0, false
+	}
+	if rem := v - used; rem > 0 {
+		return rem, true
+	}
+	return 0, true
+}
+
+// Percent returns the share of the limit used, or 0 when it is not numeric
+func (l Limit) Percent(used int64) float64 {
+	v, ok := l.Value()
+	if !ok {
+		return 0
+	}
+	return float64(used) / float64(v) * 100
+}
+
+// String renders the limit as a number, "∞" or "Unknown"
+func (l Limit) String() string {
+	switch {
+	case l.IsUnlimited():
+		return "∞"
+	case !l.IsKnown():
+		return "Unknown"
+	default:
+		return strconv.FormatInt(int64(l), 10)
+	}
+}
+
+// DB returns the nullable column value of the limit: NULL when unknown and
+// -1 when unlimited
+func (l Limit) DB() *int64 {
+	if !l.IsKnown() {
+		return nil
+	}
+	v := int64(l)
+	return &v
+}
+
+// MarshalJSON encodes numeric limits as numbers, unlimited as "unlimited"
+// and unknown as null
+func (l Limit) MarshalJSON() ([]byte, error) {
+	switch {
+	case l.IsUnlimited():
+		return []byte(`"unlimited"`), nil
+	case !l.IsKnown():
+		return []byte("null"), nil
+	default:
+		return []byte(strconv.FormatInt(int64(l), 10)), nil
+	}
+}
+
+// UnmarshalJSON accepts numbers, null, "unlimited" and numeric strings
+func (l *Limit) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*l = LimitUnknown
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "unlimited" {
+			*l = LimitUnlimited
+			return nil
+		}
+		*l = ParseLimit(s)
+		return nil
+	}
+
+	var v int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid limit %s", data)
+	}
+	*l = LimitFromInt(v)
+	return nil
+}
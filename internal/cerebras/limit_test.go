@@ -0,0 +1,83 @@
+package cerebras
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Limit
+		display  string
+	}{
+		{input: "28800", expected: 28800, display: "28800"},
+		{input: "-1", expected: LimitUnlimited, display: "∞"},
+		{input: "", expected: LimitUnknown, display: "Unknown"},
+		{input: "n/a", expected: LimitUnknown, display: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			limit := ParseLimit(tt.input)
+			if limit != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, limit)
+			}
+			if limit.String() != tt.display {
+				t.Errorf("Expected %q, got %q", tt.display, limit.String())
+			}
+		})
+	}
+}
+
+func TestLimitJSON(t *testing.T) {
+	info := RateLimitInfo{LimitRequestsDay: 100, LimitTokensDay: LimitUnlimited}
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var decoded RateLimitInfo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal %s: %v", data, err)
+	}
+	if decoded.LimitRequestsDay != 100 || !decoded.LimitTokensDay.IsUnlimited() || decoded.LimitTokensMinute.IsKnown() {
+		t.Errorf("Unexpected round trip from %s: %+v", data, decoded)
+	}
+}
+
+func TestUnlimitedWindow(t *testing.T) {
+	info := &RateLimitInfo{LimitTokensDay: LimitUnlimited, UsageTokensDay: 5000}
+
+	w, err := info.Window(WindowTokensDay)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if w.Used != 5000 || w.Percent() != 0 {
+		t.Errorf("Expected usage 5000 at 0%%, got %d at %.1f%%", w.Used, w.Percent())
+	}
+	if LimitUnlimited.DB() == nil || *LimitUnlimited.DB() != -1 || LimitUnknown.DB() != nil {
+		t.Error("Expected unlimited stored as -1 and unknown as NULL")
+	}
+	if v := int64(-1); LimitFromDB(&v) != LimitUnlimited || LimitFromDB(nil) != LimitUnknown {
+		t.Error("Expected database values to round trip")
+	}
+}
+
+func TestTightestWindow(t *testing.T) {
+	info := &RateLimitInfo{
+		LimitRequestsDay:  LimitFromInt(1000),
+		UsageRequestsDay:  100,
+		LimitTokensMinute: LimitFromInt(1000),
+		UsageTokensMinute: 600,
+		LimitTokensDay:    LimitUnlimited,
+		UsageTokensDay:    900000,
+	}
+	w, ok := info.Tightest()
+	if !ok || w.Name != WindowTokensMinute {
+		t.Errorf("Expected tokens-minute to be the tightest window, got %+v", w)
+	}
+	if _, ok := (&RateLimitInfo{LimitTokensDay: LimitUnlimited}).Tightest(); ok {
+		t.Error("Expected no tightest window without a numeric limit")
+	}
+}
@@ -1,277 +1,242 @@
 package cerebras
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
 	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
 )
 
-// GetMetrics fetches usage metrics from Cerebras servers
-func (c *Client) GetMetrics(organization string) (*RateLimitInfo, error) {
+// fetchMetrics fetches the usage metrics of a model from Cerebras servers.
+// With both a session token and an API key, GraphQL and the REST probe run
+// concurrently. REST headers describe the configured model only, so other
+// models need GraphQL.
+func (c *Client) fetchMetrics(ctx context.Context, organization, model string) (*RateLimitInfo, error) {
 	if !c.HasAuth() {
-		return nil, fmt.Errorf("no authentication method configured")
+		return nil, fmt.Errorf("%w: no authentication method configured", ErrUnauthorized)
 	}
+	useREST := c.apiKey != "" && (model == "" || model == restModel())
 
 	// Prefer GraphQL (session token + organization) for richer data
 	if c.sessionToken != "" && organization != "" {
-		gql, err := c.getMetricsWithSessionToken(organization)
-		if err == nil {
-			// If we also have an API key, try to enrich with REST (resets/remaining)
-			if c.apiKey != "" {
-				if rest, rerr := c.getMetricsWithAPIKey(); rerr == nil && rest != nil {
+		var (
+			g         errgroup.Group
+			gql, rest *RateLimitInfo
+			gerr      error
+			rerr      error
+		)
+		// Neither side cancels the other: REST is the fallback when GraphQL fails
+		g.Go(func() error {
+			gql, gerr = c.getMetricsWithSessionToken(ctx, organization, model)
+			return nil
+		})
+		if useREST {
+			g.Go(func() error {
+				rest, rerr = c.getMetricsWithAPIKey(ctx)
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		if gerr == nil {
+			// If we also have an API key, enrich with REST (resets/remaining)
+			if rerr == nil && rest != nil {
+				for _, window := range Windows {
 					// Use REST resets when GraphQL lacks them
-					if gql.ResetRequestsDay == 0 && rest.ResetRequestsDay > 0 {
-						gql.ResetRequestsDay = rest.ResetRequestsDay
+					// REST quotas fill limits GraphQL does not report
+					if limit := gql.LimitField(window); !limit.IsKnown() {
+						*limit = *rest.LimitField(window)
 					}
-					if gql.ResetTokensMinute == 0 && rest.ResetTokensMinute > 0 {
-						gql.ResetTokensMinute = rest.ResetTokensMinute
+					if reset := gql.Field(FieldReset, window); *reset == 0 {
+						*reset = *rest.Field(FieldReset, window)
 					}
 					// If GraphQL didn't compute remainings, take REST values
-					if gql.LimitRequestsDay > 0 && gql.RemainingRequestsDay == 0 && rest.RemainingRequestsDay > 0 {
-						gql.RemainingRequestsDay = rest.RemainingRequestsDay
-					}
-					if gql.LimitTokensMinute > 0 && gql.RemainingTokensMinute == 0 && rest.RemainingTokensMinute > 0 {
-						gql.RemainingTokensMinute = rest.RemainingTokensMinute
+					remaining := gql.Field(FieldRemaining, window)
+					if *gql.LimitField(window) > 0 && *remaining == 0 && *rest.Field(FieldRemaining, window) > 0 {
+						*remaining = *rest.Field(FieldRemaining, window)
 					}
 				}
 			}
 			return gql, nil
 		}
-		// If GraphQL failed, fall through to REST
+		// If GraphQL failed, fall back to REST or surface why it failed. A
+		// missing model is reported rather than hidden behind REST numbers.
+		if !useREST || ctx.Err() != nil || errors.Is(gerr, ErrModelNotFound) {
+			return nil, gerr
+		}
+		return rest, rerr
 	}
 
 	// Fallback to REST headers when available
+	if useREST {
+		return c.getMetricsWithAPIKey(ctx)
+	}
 	if c.apiKey != "" {
-		return c.getMetricsWithAPIKey()
+		return nil, fmt.Errorf("%w: rate limit headers only cover the configured model %q, other models need session token authentication", ErrModelNotFound, restModel())
 	}
 
 	// As a last resort, if only session token is available but no organization provided
 	if c.sessionToken != "" {
-		return nil, fmt.Errorf("organization ID is required when using session token authentication")
+		return nil, fmt.Errorf("%w: organization ID is required when using session token authentication", ErrNoOrganization)
 	}
 
-	return nil, fmt.Errorf("no valid authentication method found")
+	return nil, fmt.Errorf("%w: no valid authentication method found", ErrUnauthorized)
 }
 
-// getMetricsWithSessionToken fetches metrics using GraphQL with session token auth
-func (c *Client) getMetricsWithSessionToken(organization string) (*RateLimitInfo, error) {
-	// Make GraphQL request to list organization usage quotas
-	// We will map the quota limits to our RateLimitInfo structure.
-	query := `query ListOrganizationUsageQuotas($organizationId: ID!, $modelId: ID, $regionId: ID) {
-  ListOrganizationUsageQuotas(
-    organizationId: $organizationId
-    modelId: $modelId
-    regionId: $regionId
-  ) {
-    modelId
-    regionId
-    organizationId
-    requestsPerMinute
-    tokensPerMinute
-    requestsPerHour
-    tokensPerHour
-    requestsPerDay
-    tokensPerDay
-    maxSequenceLength
-    maxCompletionTokens
-    __typename
-  }
-}`
-
-	variables := map[string]interface{}{
-		"organizationId": organization,
-	}
-
-	responseBody, err := c.MakeGraphQLRequestWithDebug(query, variables, viper.GetBool("debug"))
+// getMetricsWithSessionToken fetches the metrics of a model using GraphQL
+// with session token auth, summed across regions unless the client is
+// limited to one. Without a model it returns the first quota; a model the
+// organization has no quota for is a ModelNotFoundError.
+func (c *Client) getMetricsWithSessionToken(ctx context.Context, organization, model string) (*RateLimitInfo, error) {
+	models, err := c.ListModelMetrics(ctx, organization)
 	if err != nil {
 		return nil, err
 	}
+	models = MergeRegions(models)
 
-	// Parse the GraphQL response
-	var response struct {
-		Data struct {
-			ListOrganizationUsageQuotas []UsageQuota `json:"ListOrganizationUsageQuotas"`
-		} `json:"data"`
+	// If no quotas returned, provide empty metrics
+	if len(models) == 0 {
+		return &RateLimitInfo{}, nil
 	}
-
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	if model == "" {
+		return models[0], nil
 	}
+	available := make([]string, 0, len(models))
+	for _, info := range models {
+		if info.ModelId == model {
+			return info, nil
+		}
+		if !slices.Contains(available, info.ModelId) {
+			available = append(available, info.ModelId)
+		}
+	}
+	return nil, &ModelNotFoundError{Model: model, Available: available}
+}
 
-	// If no quotas returned, provide empty metrics
-	if len(response.Data.ListOrganizationUsageQuotas) == 0 {
-		return &RateLimitInfo{}, nil
+// ListModelMetrics fetches the metrics of every model and region the
+// organization has a quota for, in the order of the quotas, using GraphQL
+// with session token auth. Only the client's region is listed when it has
+// one. Quotas and usage are requested concurrently.
+func (c *Client) ListModelMetrics(ctx context.Context, organization string) ([]*RateLimitInfo, error) {
+	gql := c.GraphQL()
+
+	var (
+		g        errgroup.Group
+		quotas   []UsageQuota
+		usage    []OrganizationUsage
+		usageErr error
+	)
+	g.Go(func() error {
+		// Partial data still carries usable quotas; any other error, including
+		// an expired session answered with HTTP 200, is surfaced to the caller
+		var err error
+		quotas, err = gql.ListOrganizationUsageQuotas(ctx, organization, c.region)
+		var partial *graphql.PartialDataError
+		if err != nil && !(errors.As(err, &partial) && len(quotas) > 0) {
+			return err
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// Usage is best-effort; without it remaining equals the numeric limits
+		usage, usageErr = gql.ListOrganizationUsage(ctx, organization)
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
-	// Try to find quota for configured model, otherwise use the first one
-	model := viper.GetString("model")
-	selected := response.Data.ListOrganizationUsageQuotas[0]
-	if model != "" {
-		for _, q := range response.Data.ListOrganizationUsageQuotas {
-			if q.ModelId == model {
-				selected = q
-				break
-			}
+	var partial *graphql.PartialDataError
+	if usageErr != nil && !errors.As(usageErr, &partial) {
+		usage = nil
+	}
+	models := make([]*RateLimitInfo, 0, len(quotas))
+	for _, q := range quotas {
+		if c.region != AllRegions && q.RegionId != "" && q.RegionId != c.region {
+			continue
 		}
+		models = append(models, QuotaMetrics(q, usage))
 	}
+	return models, nil
+}
 
-	// Helper to parse string to int64; returns 0 on error or "-1" sentinel
+// QuotaMetrics converts a quota and the organization's usage into metrics
+func QuotaMetrics(quota UsageQuota, usage []OrganizationUsage) *RateLimitInfo {
+	// Helper to parse a usage count; returns 0 on error or the "-1" sentinel
 	parse := func(s string) int64 {
 		if s == "" {
 			return 0
 		}
 		v, err := strconv.ParseInt(s, 10, 64)
-		if err != nil {
-			return 0
-		}
-		if v < 0 { // treat -1 (unlimited) as 0 limit (unknown)
+		if err != nil || v < 0 {
 			return 0
 		}
 		return v
 	}
 
+	// Quotas distinguish unlimited ("-1") from unknown (empty or invalid)
 	limits := &RateLimitInfo{
-		LimitRequestsMinute:   parse(selected.RequestsPerMinute),
-		LimitRequestsHour:     parse(selected.RequestsPerHour),
-		LimitRequestsDay:      parse(selected.RequestsPerDay),
-		LimitTokensMinute:     parse(selected.TokensPerMinute),
-		LimitTokensHour:       parse(selected.TokensPerHour),
-		LimitTokensDay:        parse(selected.TokensPerDay),
-		ResetRequestsDay:      0,
-		ResetTokensMinute:     0,
-		ModelId:               selected.ModelId,
-		RegionId:              selected.RegionId,
-		MaxSequenceLength:     parse(selected.MaxSequenceLength),
-		MaxCompletionTokens:   parse(selected.MaxCompletionTokens),
+		LimitRequestsMinute: ParseLimit(quota.RequestsPerMinute),
+		LimitRequestsHour:   ParseLimit(quota.RequestsPerHour),
+		LimitRequestsDay:    ParseLimit(quota.RequestsPerDay),
+		LimitTokensMinute:   ParseLimit(quota.TokensPerMinute),
+		LimitTokensHour:     ParseLimit(quota.TokensPerHour),
+		LimitTokensDay:      ParseLimit(quota.TokensPerDay),
+		ModelId:             quota.ModelId,
+		RegionId:            quota.RegionId,
+		MaxSequenceLength:   parse(quota.MaxSequenceLength),
+		MaxCompletionTokens: parse(quota.MaxCompletionTokens),
 	}
 
-	// Additionally fetch current usage to compute "remaining" values so UI shows progress
-	usageQuery := `query ListOrganizationUsage($organizationId: ID!) {
-  ListOrganizationUsage(organizationId: $organizationId) {
-    modelId
-    regionId
-    rpm
-    tpm
-    rph
-    tph
-    rpd
-    tpd
-    __typename
-  }
-}`
-
-	usageBody, err := c.MakeGraphQLRequestWithDebug(usageQuery, map[string]interface{}{"organizationId": organization}, viper.GetBool("debug"))
-	if err == nil { // best-effort; if it fails, we still return limits
-		var usageResp struct {
-			Data struct {
-				ListOrganizationUsage []OrganizationUsage `json:"ListOrganizationUsage"`
-			} `json:"data"`
-		}
-		if err := json.Unmarshal(usageBody, &usageResp); err == nil {
-			// Match by model and (if available) region
-			var matched *OrganizationUsage
-			for i := range usageResp.Data.ListOrganizationUsage {
-				u := &usageResp.Data.ListOrganizationUsage[i]
-				if u.ModelId == selected.ModelId {
-					if selected.RegionId == "" || u.RegionId == selected.RegionId {
-						matched = u
-						break
-					}
-				}
+	// Match usage by model and (if available) region to compute "remaining"
+	// values so the UI shows progress
+	var matched *OrganizationUsage
+	for i := range usage {
+		u := &usage[i]
+		if u.ModelId == quota.ModelId {
+			if quota.RegionId == "" || u.RegionId == quota.RegionId {
+				matched = u
+				break
 			}
+		}
+	}
 
-			if matched != nil {
-				// Parse usage across minute/hour/day for requests and tokens
-				usedRPM := parse(matched.RPM)
-				usedTPM := parse(matched.TPM)
-				usedRPH := parse(matched.RPH)
-				usedTPH := parse(matched.TPH)
-				usedRPD := parse(matched.RPD)
-				usedTPD := parse(matched.TPD)
-
-				limits.UsageRequestsMinute = usedRPM
-				limits.UsageTokensMinute = usedTPM
-				limits.UsageRequestsHour = usedRPH
-				limits.UsageTokensHour = usedTPH
-				limits.UsageRequestsDay = usedRPD
-				limits.UsageTokensDay = usedTPD
-
-				// Compute remaining when limits are known
-				if limits.LimitTokensMinute > 0 {
-					rem := limits.LimitTokensMinute - usedTPM
-					if rem < 0 { rem = 0 }
-					limits.RemainingTokensMinute = rem
-				}
-				if limits.LimitTokensHour > 0 {
-					rem := limits.LimitTokensHour - usedTPH
-					if rem < 0 { rem = 0 }
-					limits.RemainingTokensHour = rem
-				}
-				if limits.LimitTokensDay > 0 {
-					rem := limits.LimitTokensDay - usedTPD
-					if rem < 0 { rem = 0 }
-					limits.RemainingTokensDay = rem
-				}
-				if limits.LimitRequestsMinute > 0 {
-					rem := limits.LimitRequestsMinute - usedRPM
-					if rem < 0 { rem = 0 }
-					limits.RemainingRequestsMinute = rem
-				}
-				if limits.LimitRequestsHour > 0 {
-					rem := limits.LimitRequestsHour - usedRPH
-					if rem < 0 { rem = 0 }
-					limits.RemainingRequestsHour = rem
-				}
-				if limits.LimitRequestsDay > 0 {
-					rem := limits.LimitRequestsDay - usedRPD
-					if rem < 0 { rem = 0 }
-					limits.RemainingRequestsDay = rem
-				}
-			} else {
-				// Fallback: if usage not found, set remaining equal to known limits
-				limits.RemainingRequestsMinute = limits.LimitRequestsMinute
-				limits.RemainingRequestsHour = limits.LimitRequestsHour
-				limits.RemainingRequestsDay = limits.LimitRequestsDay
-				limits.RemainingTokensMinute = limits.LimitTokensMinute
-				limits.RemainingTokensHour = limits.LimitTokensHour
-				limits.RemainingTokensDay = limits.LimitTokensDay
-			}
-		} else {
-			// Parsing usage failed; set remaining equal to known limits
-			limits.RemainingRequestsMinute = limits.LimitRequestsMinute
-			limits.RemainingRequestsHour = limits.LimitRequestsHour
-			limits.RemainingRequestsDay = limits.LimitRequestsDay
-			limits.RemainingTokensMinute = limits.LimitTokensMinute
-			limits.RemainingTokensHour = limits.LimitTokensHour
-			limits.RemainingTokensDay = limits.LimitTokensDay
+	if matched != nil {
+		// Parse usage across minute/hour/day for requests and tokens
+		limits.UsageRequestsMinute = parse(matched.RPM)
+		limits.UsageTokensMinute = parse(matched.TPM)
+		limits.UsageRequestsHour = parse(matched.RPH)
+		limits.UsageTokensHour = parse(matched.TPH)
+		limits.UsageRequestsDay = parse(matched.RPD)
+		limits.UsageTokensDay = parse(matched.TPD)
+	}
+
+	// Compute remaining when limits are numeric; unlimited windows have none
+	for _, window := range Windows {
+		if rem, ok := limits.LimitField(window).Remaining(*limits.Field(FieldUsage, window)); ok {
+			*limits.Field(FieldRemaining, window) = rem
 		}
-	} else {
-		// Usage request failed; set remaining equal to known limits
-		limits.RemainingRequestsMinute = limits.LimitRequestsMinute
-		limits.RemainingRequestsHour = limits.LimitRequestsHour
-		limits.RemainingRequestsDay = limits.LimitRequestsDay
-		limits.RemainingTokensMinute = limits.LimitTokensMinute
-		limits.RemainingTokensHour = limits.LimitTokensHour
-		limits.RemainingTokensDay = limits.LimitTokensDay
 	}
 
-	return limits, nil
+	return limits
 }
 
-// getMetricsWithAPIKey fetches metrics using REST API with API key auth
-func (c *Client) getMetricsWithAPIKey() (*RateLimitInfo, error) {
+// probeCompletion sends a minimal chat completion and reads the rate limit
+// headers of the response. It also returns the tokens the probe consumed.
+func (c *Client) probeCompletion(ctx context.Context) (*RateLimitInfo, int64, error) {
 	// Make a chat completion request to get rate limit headers
 	url := fmt.Sprintf("%s/v1/chat/completions", c.baseURL)
 
-	// Get model from viper config or use default
-	model := viper.GetString("model")
-	if model == "" {
-		model = "qwen-3-coder-480b"
-	}
+	model := restModel()
 
 	// Create a minimal request body that should work
 	body := fmt.Sprintf(`{
@@ -280,24 +245,68 @@ func (c *Client) getMetricsWithAPIKey() (*RateLimitInfo, error) {
 		"max_completion_tokens": 1
 	}`, model)
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
+	// Add content type header
+	req.Header.Set("Content-Type", "application/json")
+
+	rateLimits, status, respBody, err := c.doRateLimitRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The completion itself counts against the token windows
+	var completion struct {
+		Usage struct {
+			TotalTokens int64 `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	_ = json.Unmarshal(respBody, &completion)
+
+	// If we got rate limit headers, return the rateLimitInfo even if the request failed
+	if rateLimits.Found {
+		return rateLimits.Info, completion.Usage.TotalTokens, nil
+	}
+
+	// Otherwise, return an error
+	if err := statusError(status); err != nil {
+		return nil, 0, err
+	}
+
+	return rateLimits.Info, completion.Usage.TotalTokens, nil
+}
+
+// probeModels lists the models, which costs no inference, and reads any rate
+// limit headers of the response
+func (c *Client) probeModels(ctx context.Context) (RateLimitHeaders, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/models", c.baseURL), nil)
+	if err != nil {
+		return RateLimitHeaders{}, err
+	}
+
+	rateLimits, _, _, err := c.doRateLimitRequest(req)
+	return rateLimits, err
+}
+
+// doRateLimitRequest executes an authenticated REST request and parses the
+// rate limit headers of the response
+func (c *Client) doRateLimitRequest(req *http.Request) (RateLimitHeaders, int, []byte, error) {
 	// Add authentication headers
 	headers := c.getAuthHeaders()
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	// Add content type header
-	req.Header.Set("Content-Type", "application/json")
-
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return RateLimitHeaders{}, 0, nil, ctxErr
+		}
+		return RateLimitHeaders{}, 0, nil, fmt.Errorf("%w: %w", ErrNetwork, err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -309,7 +318,7 @@ func (c *Client) getMetricsWithAPIKey() (*RateLimitInfo, error) {
 
 	// Debug: print all headers if debug flag is set
 	if viper.GetBool("debug") {
-		fmt.Printf("Response Headers:\n")
+		fmt.Printf("Response Headers (%s %s):\n", req.Method, req.URL.Path)
 		for key, values := range resp.Header {
 			for _, value := range values {
 				fmt.Printf("  %s: %s\n", key, value)
@@ -318,64 +327,30 @@ func (c *Client) getMetricsWithAPIKey() (*RateLimitInfo, error) {
 	}
 
 	// Parse rate limit headers regardless of status code
-	rateLimitInfo := &RateLimitInfo{}
-	if limit := resp.Header.Get("X-Ratelimit-Limit-Requests-Day"); limit != "" {
-		if _, err := fmt.Sscanf(limit, "%d", &rateLimitInfo.LimitRequestsDay); err != nil {
-			rateLimitInfo.LimitRequestsDay = 0
+	rateLimits := ParseRateLimitHeaders(resp.Header)
+	if viper.GetBool("debug") && len(rateLimits.Unknown) > 0 {
+		fmt.Printf("Unrecognized rate limit headers:\n")
+		for _, name := range rateLimits.UnknownNames() {
+			fmt.Printf("  %s: %s\n", name, rateLimits.Unknown[name])
 		}
 	}
 
-	if limit := resp.Header.Get("X-Ratelimit-Limit-Tokens-Minute"); limit != "" {
-		if _, err := fmt.Sscanf(limit, "%d", &rateLimitInfo.LimitTokensMinute); err != nil {
-			rateLimitInfo.LimitTokensMinute = 0
-		}
-	}
-
-	if remaining := resp.Header.Get("X-Ratelimit-Remaining-Requests-Day"); remaining != "" {
-		if _, err := fmt.Sscanf(remaining, "%d", &rateLimitInfo.RemainingRequestsDay); err != nil {
-			rateLimitInfo.RemainingRequestsDay = 0
-		}
-	}
-
-	if remaining := resp.Header.Get("X-Ratelimit-Remaining-Tokens-Minute"); remaining != "" {
-		if _, err := fmt.Sscanf(remaining, "%d", &rateLimitInfo.RemainingTokensMinute); err != nil {
-			rateLimitInfo.RemainingTokensMinute = 0
-		}
-	}
-
-	if reset := resp.Header.Get("X-Ratelimit-Reset-Requests-Day"); reset != "" {
-		var val float64
-		if _, err := fmt.Sscanf(reset, "%f", &val); err == nil {
-			rateLimitInfo.ResetRequestsDay = int64(val)
-		}
-	}
+	// The body is only needed for small JSON responses; read errors leave it empty
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
 
-	if reset := resp.Header.Get("X-Ratelimit-Reset-Tokens-Minute"); reset != "" {
-		var val float64
-		if _, err := fmt.Sscanf(reset, "%f", &val); err == nil {
-			rateLimitInfo.ResetTokensMinute = int64(val)
-		}
-	}
-
-	// Derive usage from remaining when possible
-	if rateLimitInfo.LimitRequestsDay > 0 && rateLimitInfo.RemainingRequestsDay >= 0 {
-		rateLimitInfo.UsageRequestsDay = rateLimitInfo.LimitRequestsDay - rateLimitInfo.RemainingRequestsDay
-		if rateLimitInfo.UsageRequestsDay < 0 { rateLimitInfo.UsageRequestsDay = 0 }
-	}
-	if rateLimitInfo.LimitTokensMinute > 0 && rateLimitInfo.RemainingTokensMinute >= 0 {
-		rateLimitInfo.UsageTokensMinute = rateLimitInfo.LimitTokensMinute - rateLimitInfo.RemainingTokensMinute
-		if rateLimitInfo.UsageTokensMinute < 0 { rateLimitInfo.UsageTokensMinute = 0 }
-	}
-
-	// If we got rate limit headers, return the rateLimitInfo even if the request failed
-	if rateLimitInfo.LimitRequestsDay > 0 || rateLimitInfo.LimitTokensMinute > 0 || rateLimitInfo.RemainingRequestsDay > 0 || rateLimitInfo.RemainingTokensMinute > 0 {
-		return rateLimitInfo, nil
-	}
+	return rateLimits, resp.StatusCode, body, nil
+}
 
-	// Otherwise, return an error
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+// statusError classifies a failed REST status code
+func statusError(status int) error {
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: API key rejected with status code: %d", ErrUnauthorized, status)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: API request failed with status code: %d", ErrRateLimited, status)
+	default:
+		return fmt.Errorf("API request failed with status code: %d", status)
 	}
-
-	return rateLimitInfo, nil
 }
@@ -1,6 +1,9 @@
 package cerebras
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
 	"github.com/spf13/viper"
 )
 
@@ -153,7 +157,7 @@ func TestGetMetricsWithAPIKey(t *testing.T) {
 	// Set up viper config
 	viper.Set("model", "qwen-3-coder-480b")
 
-	rateLimitInfo, err := client.getMetricsWithAPIKey()
+	rateLimitInfo, err := client.getMetricsWithAPIKey(context.Background())
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -203,7 +207,7 @@ func TestGetMetricsWithAPIKeyNoHeaders(t *testing.T) {
 
 	viper.Set("model", "qwen-3-coder-480b")
 
-	rateLimitInfo, err := client.getMetricsWithAPIKey()
+	rateLimitInfo, err := client.getMetricsWithAPIKey(context.Background())
 	if err != nil {
 		t.Errorf("Expected no error when response is OK but no headers, got: %v", err)
 	}
@@ -229,7 +233,7 @@ func TestGetMetricsWithAPIKeyError(t *testing.T) {
 
 	viper.Set("model", "qwen-3-coder-480b")
 
-	_, err := client.getMetricsWithAPIKey()
+	_, err := client.getMetricsWithAPIKey(context.Background())
 	if err == nil {
 		t.Error("Expected error when API returns error, got nil")
 	}
@@ -309,7 +313,7 @@ func TestRateLimitHeaderParsing(t *testing.T) {
 
 			viper.Set("model", "qwen-3-coder-480b")
 
-			rateLimitInfo, err := client.getMetricsWithAPIKey()
+			rateLimitInfo, err := client.getMetricsWithAPIKey(context.Background())
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -390,11 +394,148 @@ func TestModelConfigurationInMetrics(t *testing.T) {
 			}
 
 			viper.Set("model", tt.configModel)
+			viper.Set("probe.strategy", ProbeCompletion)
+			defer viper.Set("probe.strategy", "")
 
-			_, err := client.getMetricsWithAPIKey()
+			_, err := client.getMetricsWithAPIKey(context.Background())
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
 		})
 	}
 }
+
+func TestGetMetricsWithSessionTokenUnauthorized(t *testing.T) {
+	// An expired session is answered with HTTP 200 and an errors array
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"Unauthorized"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient:   &http.Client{},
+		sessionToken: "expired",
+		graphqlURL:   server.URL,
+	}
+
+	info, err := client.GetMetrics(context.Background(), "org1")
+	if !errors.Is(err, graphql.ErrUnauthorized) {
+		t.Fatalf("Expected ErrUnauthorized, got info=%+v err=%v", info, err)
+	}
+}
+
+func TestGetMetricsWithSessionTokenUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "ListOrganizationUsageQuotas") {
+			_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsageQuotas":[{"modelId":"qwen-3-coder-480b","requestsPerMinute":"50","tokensPerDay":"-1"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsage":[{"modelId":"qwen-3-coder-480b","rpm":"20","tpd":"1000"}]}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient:   &http.Client{},
+		sessionToken: "session",
+		graphqlURL:   server.URL,
+	}
+	viper.Set("model", "qwen-3-coder-480b")
+
+	info, err := client.GetMetrics(context.Background(), "org1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.LimitRequestsMinute != 50 || info.UsageRequestsMinute != 20 || info.RemainingRequestsMinute != 30 {
+		t.Errorf("Unexpected requests/minute: %+v", info)
+	}
+	if !info.LimitTokensDay.IsUnlimited() || info.UsageTokensDay != 1000 {
+		t.Errorf("Expected unlimited tokens/day with usage 1000, got %+v", info)
+	}
+}
+
+func TestGetModelMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "ListOrganizationUsageQuotas") {
+			_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsageQuotas":[` +
+				`{"modelId":"qwen-3-coder-480b","requestsPerMinute":"50"},` +
+				`{"modelId":"llama-3.3-70b","requestsPerMinute":"30"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsage":[{"modelId":"llama-3.3-70b","rpm":"10"}]}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient:   &http.Client{},
+		sessionToken: "session",
+		graphqlURL:   server.URL,
+	}
+	ctx := context.Background()
+
+	models, err := client.ListModelMetrics(ctx, "org1")
+	if err != nil || len(models) != 2 || models[1].ModelId != "llama-3.3-70b" || models[1].RemainingRequestsMinute != 20 {
+		t.Fatalf("Unexpected models %+v, err %v", models, err)
+	}
+
+	info, err := client.GetModelMetrics(ctx, "org1", "llama-3.3-70b")
+	if err != nil || info.ModelId != "llama-3.3-70b" || info.UsageRequestsMinute != 10 {
+		t.Errorf("Expected the llama quota, got %+v, err %v", info, err)
+	}
+	if info, err := client.GetModelMetrics(ctx, "org1", ""); err != nil || info.ModelId != "qwen-3-coder-480b" {
+		t.Errorf("Expected the first quota without a model, got %+v, err %v", info, err)
+	}
+
+	_, err = client.GetModelMetrics(ctx, "org1", "gpt-oss-120b")
+	var notFound *ModelNotFoundError
+	if !errors.Is(err, ErrModelNotFound) || !errors.As(err, &notFound) || len(notFound.Available) != 2 {
+		t.Fatalf("Expected a ModelNotFoundError listing both models, got %v", err)
+	}
+	if want := `model "gpt-oss-120b" is not in the organization's quotas, available: qwen-3-coder-480b, llama-3.3-70b`; err.Error() != want {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestGetModelMetricsRegions(t *testing.T) {
+	var regionVar any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "ListOrganizationUsageQuotas") {
+			var req struct {
+				Variables map[string]any `json:"variables"`
+			}
+			_ = json.Unmarshal(body, &req)
+			regionVar = req.Variables["regionId"]
+			_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsageQuotas":[` +
+				`{"modelId":"qwen-3-coder-480b","regionId":"us-east","requestsPerDay":"1000"},` +
+				`{"modelId":"qwen-3-coder-480b","regionId":"eu-west","requestsPerDay":"500"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"ListOrganizationUsage":[` +
+			`{"modelId":"qwen-3-coder-480b","regionId":"eu-west","rpd":"50"},` +
+			`{"modelId":"qwen-3-coder-480b","regionId":"us-east","rpd":"100"}]}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: &http.Client{}, sessionToken: "session", graphqlURL: server.URL}
+	info, err := client.GetModelMetrics(context.Background(), "org1", "qwen-3-coder-480b")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if regionVar != nil || info.LimitRequestsDay != 1500 || info.UsageRequestsDay != 150 || len(info.Regions) != 2 {
+		t.Errorf("Expected every region summed, got %+v (regionId %v)", info, regionVar)
+	}
+	if west := info.Regions[1]; west.RegionId != "eu-west" || west.UsageRequestsDay != 50 {
+		t.Errorf("Expected eu-west usage matched by region, got %+v", west)
+	}
+
+	client = &Client{httpClient: &http.Client{}, sessionToken: "session", graphqlURL: server.URL, region: "eu-west"}
+	info, err = client.GetModelMetrics(context.Background(), "org1", "qwen-3-coder-480b")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if regionVar != "eu-west" || info.RegionId != "eu-west" || info.LimitRequestsDay != 500 || info.UsageRequestsDay != 50 || info.Regions != nil {
+		t.Errorf("Expected only eu-west, got %+v (regionId %v)", info, regionVar)
+	}
+}
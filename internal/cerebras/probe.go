@@ -0,0 +1,225 @@
+package cerebras
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Probe strategies for API-key polling, see getMetricsWithAPIKey
+const (
+	// ProbeAuto tries captured headers, then the models endpoint, then a
+	// chat completion, backing off completions while usage is flat
+	ProbeAuto = "auto"
+	// ProbeModels only reads headers from the models endpoint
+	ProbeModels = "models"
+	// ProbeCompletion sends a chat completion on every poll
+	ProbeCompletion = "completion"
+)
+
+// Probe defaults, overridable in the probe section of the configuration
+const (
+	DefaultProbeMaxBackoff  = 5 * time.Minute
+	DefaultCaptureMaxAge    = time.Minute
+	defaultProbeBaseBackoff = 10 * time.Second
+)
+
+// probeState remembers probe results between polls of one client
+type probeState struct {
+	mu sync.Mutex
+
+	// modelsChecked is set once the models endpoint was tried;
+	// modelsHeaders reports whether it returned rate limit headers
+	modelsChecked bool
+	modelsHeaders bool
+
+	last      *RateLimitInfo
+	lastAt    time.Time
+	nextProbe time.Time
+	flatPolls int
+
+	// costs are the completions sent by this client, oldest first
+	costs []probeCost
+}
+
+// probeCost is the consumption of one completion probe
+type probeCost struct {
+	at     time.Time
+	tokens int64
+}
+
+// ProbeStrategy returns the configured probe strategy
+func ProbeStrategy() (string, error) {
+	strategy := viper.GetString("probe.strategy")
+	switch strategy {
+	case "":
+		return ProbeAuto, nil
+	case ProbeAuto, ProbeModels, ProbeCompletion:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid probe.strategy %q: expected auto, models or completion", strategy)
+	}
+}
+
+// probeDuration reads a duration in seconds from the configuration
+func probeDuration(key string, fallback time.Duration) time.Duration {
+	if secs := viper.GetInt(key); secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return fallback
+}
+
+// restModel returns the model the REST probes read rate limits for: the
+// configured model, or the default one
+func restModel() string {
+	if model := viper.GetString("model"); model != "" {
+		return model
+	}
+	return "qwen-3-coder-480b"
+}
+
+// getMetricsWithAPIKey fetches metrics from REST rate limit headers using the
+// cheapest probe strategy that works
+func (c *Client) getMetricsWithAPIKey(ctx context.Context) (*RateLimitInfo, error) {
+	strategy, err := ProbeStrategy()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	model := restModel()
+
+	c.probe.mu.Lock()
+	defer c.probe.mu.Unlock()
+
+	if strategy == ProbeAuto {
+		// Headers from real traffic are free and reflect actual usage
+		if captured, ok := LoadCapturedHeaders(model, probeDuration("probe.capture-max-age", DefaultCaptureMaxAge), now); ok {
+			if parsed := captured.Info(now); parsed.Found {
+				c.remember(parsed.Info, now, false)
+				return parsed.Info, nil
+			}
+		}
+	}
+
+	if strategy == ProbeAuto || strategy == ProbeModels {
+		if !c.probe.modelsChecked || c.probe.modelsHeaders {
+			parsed, err := c.probeModels(ctx)
+			if err == nil {
+				c.probe.modelsChecked = true
+				c.probe.modelsHeaders = parsed.Found
+			}
+			if parsed.Found {
+				c.remember(parsed.Info, now, false)
+				return parsed.Info, nil
+			}
+		}
+		if strategy == ProbeModels {
+			return nil, fmt.Errorf("models endpoint returned no rate limit headers")
+		}
+	}
+
+	// Completions are billable, so reuse the last result while usage is flat
+	if strategy == ProbeAuto && c.probe.last != nil && now.Before(c.probe.nextProbe) {
+		info := *c.probe.last
+		ageResets(&info, now.Sub(c.probe.lastAt))
+		return &info, nil
+	}
+
+	info, tokens, err := c.probeCompletion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.probe.costs = append(c.probe.costs, probeCost{at: now, tokens: tokens})
+	c.subtractProbeCosts(info, now)
+	c.remember(info, now, strategy == ProbeAuto)
+
+	return info, nil
+}
+
+// remember stores the latest metrics and schedules the next completion probe,
+// doubling the interval while usage stays flat
+func (c *Client) remember(info *RateLimitInfo, now time.Time, backoff bool) {
+	flat := c.probe.last != nil && sameUsage(c.probe.last, info)
+	c.probe.last = info
+	c.probe.lastAt = now
+
+	if !backoff || !flat {
+		c.probe.flatPolls = 0
+		c.probe.nextProbe = now
+		return
+	}
+
+	c.probe.flatPolls++
+	base := probeDuration("refresh-rate", defaultProbeBaseBackoff)
+	delay := base << c.probe.flatPolls
+	if maxBackoff := probeDuration("probe.max-backoff", DefaultProbeMaxBackoff); delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	c.probe.nextProbe = now.Add(delay)
+}
+
+// subtractProbeCosts removes the requests and tokens spent on probes from the
+// reported usage so probing does not skew the statistics. Probes older than
+// the start of a window are no longer counted in it.
+func (c *Client) subtractProbeCosts(info *RateLimitInfo, now time.Time) {
+	oldest := now
+	for _, window := range Windows {
+		period, _ := WindowPeriod(window)
+		start := now.Add(-period)
+		if reset := *info.Field(FieldReset, window); reset > 0 {
+			start = now.Add(time.Duration(reset)*time.Second - period)
+		}
+		if start.Before(oldest) {
+			oldest = start
+		}
+
+		var requests, tokens int64
+		for _, cost := range c.probe.costs {
+			if !cost.at.Before(start) {
+				requests++
+				tokens += cost.tokens
+			}
+		}
+		spent := requests
+		if window == WindowTokensMinute || window == WindowTokensHour || window == WindowTokensDay {
+			spent = tokens
+		}
+		subtractUsage(info, window, spent)
+	}
+
+	// Forget probes that no longer fall in any window
+	kept := c.probe.costs[:0]
+	for _, cost := range c.probe.costs {
+		if !cost.at.Before(oldest) {
+			kept = append(kept, cost)
+		}
+	}
+	c.probe.costs = kept
+}
+
+// subtractUsage removes spent from the window's usage. Remaining is left as
+// reported since it is the capacity actually left.
+func subtractUsage(info *RateLimitInfo, window string, spent int64) {
+	usage := info.Field(FieldUsage, window)
+	if spent <= 0 || *usage <= 0 {
+		return
+	}
+	if spent > *usage {
+		spent = *usage
+	}
+	*usage -= spent
+}
+
+// sameUsage reports whether no window's usage changed between two results
+func sameUsage(a, b *RateLimitInfo) bool {
+	for _, window := range Windows {
+		if *a.Field(FieldUsage, window) != *b.Field(FieldUsage, window) || *a.LimitField(window) != *b.LimitField(window) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package cerebras
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func newProbeTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("model", "qwen-3-coder-480b")
+	viper.Set("probe.strategy", "")
+	t.Cleanup(func() { viper.Set("probe.strategy", "") })
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &Client{
+		httpClient: &http.Client{},
+		apiKey:     "test-api-key",
+		baseURL:    server.URL,
+	}
+}
+
+func TestProbePrefersModelsEndpoint(t *testing.T) {
+	var completions int32
+	client := newProbeTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/chat/completions" {
+			atomic.AddInt32(&completions, 1)
+		}
+		w.Header().Set("X-Ratelimit-Limit-Requests-Day", "1000")
+		w.Header().Set("X-Ratelimit-Remaining-Requests-Day", "900")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	info, err := client.getMetricsWithAPIKey(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.UsageRequestsDay != 100 {
+		t.Errorf("Expected UsageRequestsDay 100, got %d", info.UsageRequestsDay)
+	}
+	if completions != 0 {
+		t.Errorf("Expected no completion probes, got %d", completions)
+	}
+}
+
+func TestProbeCompletionSubtractsOwnUsage(t *testing.T) {
+	var remaining int64 = 900
+	client := newProbeTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		remaining--
+		w.Header().Set("X-Ratelimit-Limit-Requests-Day", "1000")
+		w.Header().Set("X-Ratelimit-Remaining-Requests-Day", itoa(remaining))
+		w.Header().Set("X-Ratelimit-Limit-Tokens-Minute", "1000")
+		w.Header().Set("X-Ratelimit-Remaining-Tokens-Minute", "988")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"usage": {"prompt_tokens": 11, "completion_tokens": 1, "total_tokens": 12}}`))
+	})
+
+	info, err := client.getMetricsWithAPIKey(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.UsageRequestsDay != 100 {
+		t.Errorf("Expected the probe request to be subtracted, got %d", info.UsageRequestsDay)
+	}
+	if info.UsageTokensMinute != 0 {
+		t.Errorf("Expected the probe tokens to be subtracted, got %d", info.UsageTokensMinute)
+	}
+	if info.RemainingRequestsDay != 899 {
+		t.Errorf("Expected remaining to stay as reported, got %d", info.RemainingRequestsDay)
+	}
+}
+
+func TestProbeBacksOffWhileUsageIsFlat(t *testing.T) {
+	var completions int32
+	client := newProbeTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		n := atomic.AddInt32(&completions, 1)
+		// Only the probes themselves consume requests
+		w.Header().Set("X-Ratelimit-Limit-Requests-Day", "1000")
+		w.Header().Set("X-Ratelimit-Remaining-Requests-Day", itoa(int64(900-n)))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.getMetricsWithAPIKey(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if client.probe.flatPolls != 1 || !client.probe.nextProbe.After(time.Now()) {
+		t.Fatalf("Expected backoff after flat usage, got %d flat polls", client.probe.flatPolls)
+	}
+
+	info, err := client.getMetricsWithAPIKey(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if completions != 2 {
+		t.Errorf("Expected the third poll to reuse the last result, got %d completions", completions)
+	}
+	if info.UsageRequestsDay != 100 {
+		t.Errorf("Expected cached usage 100, got %d", info.UsageRequestsDay)
+	}
+}
+
+func TestProbeReusesCapturedHeaders(t *testing.T) {
+	var requests int32
+	client := newProbeTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	header := http.Header{}
+	header.Set("X-Ratelimit-Limit-Tokens-Minute", "1000")
+	header.Set("X-Ratelimit-Remaining-Tokens-Minute", "400")
+	header.Set("X-Ratelimit-Reset-Tokens-Minute", "50")
+	header.Set("Content-Type", "application/json")
+	if err := SaveCapturedHeaders("qwen-3-coder-480b", header, time.Now().Add(-10*time.Second)); err != nil {
+		t.Fatalf("Failed to save captured headers: %v", err)
+	}
+
+	info, err := client.getMetricsWithAPIKey(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no API requests, got %d", requests)
+	}
+	if info.UsageTokensMinute != 600 {
+		t.Errorf("Expected UsageTokensMinute 600, got %d", info.UsageTokensMinute)
+	}
+	if info.ResetTokensMinute > 40 {
+		t.Errorf("Expected the reset countdown to be aged, got %d", info.ResetTokensMinute)
+	}
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
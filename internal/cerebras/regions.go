@@ -0,0 +1,78 @@
+package cerebras
+
+// AllRegions is the region of metrics aggregated across every region
+const AllRegions = ""
+
+// MergeRegions combines the per-region metrics of each model into one entry,
+// keeping the order in which models first appear. A model quoted in several
+// regions becomes an organization-wide entry (see AggregateRegions).
+func MergeRegions(infos []*RateLimitInfo) []*RateLimitInfo {
+	var (
+		order  []string
+		groups = make(map[string][]*RateLimitInfo)
+	)
+	for _, info := range infos {
+		if _, ok := groups[info.ModelId]; !ok {
+			order = append(order, info.ModelId)
+		}
+		groups[info.ModelId] = append(groups[info.ModelId], info)
+	}
+
+	merged := make([]*RateLimitInfo, 0, len(order))
+	for _, model := range order {
+		if group := groups[model]; len(group) == 1 {
+			merged = append(merged, group[0])
+		} else {
+			merged = append(merged, AggregateRegions(group))
+		}
+	}
+	return merged
+}
+
+// AggregateRegions sums the metrics of one model across regions. A window is
+// unlimited when any region is unlimited, otherwise unknown when any region
+// does not report it; resets are those of the region resetting first. The
+// regions are kept in Regions, and RegionId is AllRegions.
+func AggregateRegions(regions []*RateLimitInfo) *RateLimitInfo {
+	total := &RateLimitInfo{RegionId: AllRegions}
+	for i, region := range regions {
+		if i == 0 {
+			total.ModelId = region.ModelId
+		}
+		total.MaxSequenceLength = smallest(total.MaxSequenceLength, region.MaxSequenceLength)
+		total.MaxCompletionTokens = smallest(total.MaxCompletionTokens, region.MaxCompletionTokens)
+		total.Regions = append(total.Regions, *region)
+
+		for _, window := range Windows {
+			*total.LimitField(window) = addLimits(*total.LimitField(window), *region.LimitField(window), i == 0)
+			for _, kind := range []string{FieldUsage, FieldRemaining} {
+				*total.Field(kind, window) += *region.Field(kind, window)
+			}
+			reset := total.Field(FieldReset, window)
+			*reset = smallest(*reset, *region.Field(FieldReset, window))
+		}
+	}
+	return total
+}
+
+// smallest returns the smaller of two values, ignoring unknown (zero) ones
+func smallest(a, b int64) int64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// addLimits adds the limit of one more region to a running total
+func addLimits(total, region Limit, first bool) Limit {
+	switch {
+	case first:
+		return region
+	case total.IsUnlimited() || region.IsUnlimited():
+		return LimitUnlimited
+	case !total.IsKnown() || !region.IsKnown():
+		return LimitUnknown
+	default:
+		return total + region
+	}
+}
@@ -0,0 +1,49 @@
+package cerebras
+
+import "testing"
+
+func TestAggregateRegions(t *testing.T) {
+	east := &RateLimitInfo{
+		ModelId: "qwen-3-coder-480b", RegionId: "us-east",
+		LimitRequestsDay: 1000, UsageRequestsDay: 100, RemainingRequestsDay: 900,
+		LimitTokensMinute: 60000, LimitTokensDay: LimitUnlimited, LimitTokensHour: 5000,
+		ResetTokensMinute: 40, MaxSequenceLength: 65536,
+	}
+	west := &RateLimitInfo{
+		ModelId: "qwen-3-coder-480b", RegionId: "eu-west",
+		LimitRequestsDay: 500, UsageRequestsDay: 50, RemainingRequestsDay: 450,
+		LimitTokensMinute: 30000, LimitTokensDay: 2000000,
+		ResetTokensMinute: 20, MaxSequenceLength: 32768,
+	}
+
+	total := AggregateRegions([]*RateLimitInfo{east, west})
+	if total.RegionId != AllRegions || total.ModelId != "qwen-3-coder-480b" || len(total.Regions) != 2 {
+		t.Fatalf("Unexpected aggregate %+v", total)
+	}
+	if total.LimitRequestsDay != 1500 || total.UsageRequestsDay != 150 || total.RemainingRequestsDay != 1350 {
+		t.Errorf("Expected summed requests/day, got %+v", total)
+	}
+	if total.LimitTokensMinute != 90000 || !total.LimitTokensDay.IsUnlimited() || total.LimitTokensHour.IsKnown() {
+		t.Errorf("Expected summed, unlimited and unknown token limits, got %+v", total)
+	}
+	if total.ResetTokensMinute != 20 || total.MaxSequenceLength != 32768 {
+		t.Errorf("Expected the soonest reset and smallest sequence length, got %+v", total)
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	merged := MergeRegions([]*RateLimitInfo{
+		{ModelId: "qwen-3-coder-480b", RegionId: "us-east", LimitRequestsDay: 1000},
+		{ModelId: "llama-3.3-70b", RegionId: "us-east", LimitRequestsDay: 300},
+		{ModelId: "qwen-3-coder-480b", RegionId: "eu-west", LimitRequestsDay: 500},
+	})
+	if len(merged) != 2 || merged[0].ModelId != "qwen-3-coder-480b" || merged[1].ModelId != "llama-3.3-70b" {
+		t.Fatalf("Expected one entry per model in order, got %+v", merged)
+	}
+	if merged[0].LimitRequestsDay != 1500 || len(merged[0].Regions) != 2 {
+		t.Errorf("Expected qwen summed across regions, got %+v", merged[0])
+	}
+	if merged[1].RegionId != "us-east" || merged[1].Regions != nil {
+		t.Errorf("Expected llama kept as is, got %+v", merged[1])
+	}
+}
@@ -0,0 +1,239 @@
+// Package transport builds the HTTP client shared by the REST and GraphQL
+// clients: connect and read timeouts plus retries with jittered exponential
+// backoff.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Defaults, overridable in the http section of the configuration
+const (
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultReadTimeout    = 30 * time.Second
+	DefaultMaxRetries     = 3
+	DefaultBaseBackoff    = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+)
+
+// Config controls timeouts and retries
+type Config struct {
+	// ConnectTimeout bounds dialing and the TLS handshake
+	ConnectTimeout time.Duration
+	// ReadTimeout bounds the wait for response headers after the request
+	// was written
+	ReadTimeout time.Duration
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseBackoff is the delay before the first retry; it doubles per retry
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries, including Retry-After
+	MaxBackoff time.Duration
+	// Logf receives a line for every retry when set
+	Logf func(format string, args ...interface{})
+}
+
+// DefaultConfig returns the built-in timeouts and retry policy
+func DefaultConfig() Config {
+	return Config{
+		ConnectTimeout: DefaultConnectTimeout,
+		ReadTimeout:    DefaultReadTimeout,
+		MaxRetries:     DefaultMaxRetries,
+		BaseBackoff:    DefaultBaseBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// LoadConfig reads the http section of the configuration. Durations are in
+// seconds; retries are logged to stderr when debug is set.
+func LoadConfig() Config {
+	cfg := DefaultConfig()
+	seconds := func(key string, target *time.Duration) {
+		if viper.IsSet(key) {
+			if v := viper.GetFloat64(key); v > 0 {
+				*target = time.Duration(v * float64(time.Second))
+			}
+		}
+	}
+	seconds("http.connect-timeout", &cfg.ConnectTimeout)
+	seconds("http.read-timeout", &cfg.ReadTimeout)
+	seconds("http.max-backoff", &cfg.MaxBackoff)
+	if viper.IsSet("http.max-retries") {
+		if v := viper.GetInt("http.max-retries"); v >= 0 {
+			cfg.MaxRetries = v
+		}
+	}
+	if viper.GetBool("debug") {
+		cfg.Logf = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "Debug: "+format+"\n", args...)
+		}
+	}
+	return cfg
+}
+
+// NewClient returns an HTTP client with the configured timeouts and retries
+func NewClient(cfg Config) *http.Client {
+	base := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   cfg.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   cfg.ConnectTimeout,
+		ResponseHeaderTimeout: cfg.ReadTimeout,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+	return &http.Client{Transport: &Retry{Base: base, Config: cfg}}
+}
+
+// Retry is a RoundTripper that retries network errors and 5xx responses
+// with jittered exponential backoff, and 429 responses after Retry-After
+type Retry struct {
+	Base   http.RoundTripper
+	Config Config
+
+	// sleep waits between attempts; tests replace it
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RoundTrip sends the request, retrying while the policy allows
+func (t *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	sleep := t.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			// Requests are retried with a fresh copy of the body
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry %s %s: request body is not replayable", req.Method, req.URL)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := base.RoundTrip(req)
+		delay, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		reason := "network error: " + errString(err)
+		if resp != nil {
+			reason = resp.Status
+			_ = resp.Body.Close()
+		}
+		t.logf("retrying %s %s in %s after %s (retry %d/%d)", req.Method, req.URL.Path, delay.Round(time.Millisecond), reason, attempt+1, t.Config.MaxRetries)
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether an attempt is retried and how long to wait
+func (t *Retry) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.Config.MaxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if wait, ok := RetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			// Waits longer than the backoff cap are left to the caller
+			if wait > t.Config.MaxBackoff {
+				return 0, false
+			}
+			return wait, true
+		}
+		return t.backoff(attempt), true
+	case resp.StatusCode >= 500:
+		return t.backoff(attempt), true
+	default:
+		return 0, false
+	}
+}
+
+// backoff returns a jittered delay in [d/2, d) where d doubles per attempt
+// and is capped at MaxBackoff
+func (t *Retry) backoff(attempt int) time.Duration {
+	d := t.Config.BaseBackoff << attempt
+	if d <= 0 || d > t.Config.MaxBackoff {
+		d = t.Config.MaxBackoff
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func (t *Retry) logf(format string, args ...interface{}) {
+	if t.Config.Logf != nil {
+		t.Config.Logf(format, args...)
+	}
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func RetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
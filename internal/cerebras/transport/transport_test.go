@@ -0,0 +1,194 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetry returns a Retry that records its waits instead of sleeping
+func newTestRetry(waits *[]time.Duration) *Retry {
+	cfg := DefaultConfig()
+	cfg.BaseBackoff = 100 * time.Millisecond
+	cfg.MaxBackoff = 10 * time.Second
+	return &Retry{
+		Base:   http.DefaultTransport,
+		Config: cfg,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*waits = append(*waits, d)
+			return ctx.Err()
+		},
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"query":"q"}` {
+			t.Errorf("Expected the body to be replayed, got %q", body)
+		}
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	var logged []string
+	rt := newTestRetry(&waits)
+	rt.Config.Logf = func(format string, args ...interface{}) { logged = append(logged, format) }
+	client := &http.Client{Transport: rt}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"q"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || attempts.Load() != 3 {
+		t.Errorf("Expected success on the third attempt, got status %d after %d attempts", resp.StatusCode, attempts.Load())
+	}
+	if len(waits) != 2 || len(logged) != 2 {
+		t.Fatalf("Expected 2 logged waits, got %v and %d log lines", waits, len(logged))
+	}
+	// Jittered backoff stays within [d/2, d) of the doubling schedule
+	if waits[0] < 50*time.Millisecond || waits[0] >= 100*time.Millisecond ||
+		waits[1] < 100*time.Millisecond || waits[1] >= 200*time.Millisecond {
+		t.Errorf("Unexpected backoff schedule %v", waits)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRetry(&waits)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || attempts.Load() != int32(DefaultMaxRetries+1) {
+		t.Errorf("Expected the last 503 after %d attempts, got %d after %d", DefaultMaxRetries+1, resp.StatusCode, attempts.Load())
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRetry(&waits)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("Expected one 2s wait before success, got status %d and waits %v", resp.StatusCode, waits)
+	}
+}
+
+func TestRetrySkipsLongRetryAfterAndClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+	}{
+		{"retry-after beyond the cap", http.StatusTooManyRequests, "3600"},
+		{"client error", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			client := &http.Client{Transport: newTestRetry(&waits)}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.status || attempts.Load() != 1 {
+				t.Errorf("Expected a single attempt returning %d, got %d after %d", tt.status, resp.StatusCode, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := &Retry{Base: http.DefaultTransport, Config: DefaultConfig()}
+	rt.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := (&http.Client{Transport: rt}).Do(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 12:00:45 GMT", 45 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := RetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
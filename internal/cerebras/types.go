@@ -2,18 +2,20 @@ package cerebras
 
 import (
 	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
 )
 
 // RateLimitInfo represents comprehensive rate limit information
 type RateLimitInfo struct {
 	// Limits (prefer GraphQL quotas; fall back to REST headers)
-	LimitRequestsMinute int64 `json:"limit_requests_minute,omitempty"`
-	LimitRequestsHour   int64 `json:"limit_requests_hour,omitempty"`
-	LimitRequestsDay    int64 `json:"limit_requests_day,omitempty"`
+	LimitRequestsMinute Limit `json:"limit_requests_minute,omitempty"`
+	LimitRequestsHour   Limit `json:"limit_requests_hour,omitempty"`
+	LimitRequestsDay    Limit `json:"limit_requests_day,omitempty"`
 
-	LimitTokensMinute int64 `json:"limit_tokens_minute,omitempty"`
-	LimitTokensHour   int64 `json:"limit_tokens_hour,omitempty"`
-	LimitTokensDay    int64 `json:"limit_tokens_day,omitempty"`
+	LimitTokensMinute Limit `json:"limit_tokens_minute,omitempty"`
+	LimitTokensHour   Limit `json:"limit_tokens_hour,omitempty"`
+	LimitTokensDay    Limit `json:"limit_tokens_day,omitempty"`
 
 	// Usage (prefer GraphQL usage; else derive as limit - remaining when available)
 	UsageRequestsMinute int64 `json:"usage_requests_minute,omitempty"`
@@ -48,13 +50,9 @@ type RateLimitInfo struct {
 	MaxSequenceLength   int64  `json:"max_sequence_length,omitempty"`
 	MaxCompletionTokens int64  `json:"max_completion_tokens,omitempty"`
 
-	// Backward compatibility fields
-	LimitRequestsDayOld      int64 `json:"limit_requests_day,omitempty"`
-	LimitTokensMinuteOld     int64 `json:"limit_tokens_minute,omitempty"`
-	RemainingRequestsDayOld  int64 `json:"remaining_requests_day,omitempty"`
-	RemainingTokensMinuteOld int64 `json:"remaining_tokens_minute,omitempty"`
-	ResetRequestsDayOld      int64 `json:"reset_requests_day,omitempty"`
-	ResetTokensMinuteOld     int64 `json:"reset_tokens_minute,omitempty"`
+	// Regions holds the per-region metrics summed into these, when the model
+	// is quoted in several regions (see AggregateRegions)
+	Regions []RateLimitInfo `json:"regions,omitempty"`
 }
 
 // ToQuota converts RateLimitInfo to Quota
@@ -77,12 +75,14 @@ func (r *RateLimitInfo) ToQuota() *Quota {
 func (r *RateLimitInfo) ToUsageMetrics(orgID, modelName string) *UsageMetrics {
 	// Note: We're making assumptions about which limits correspond to which quotas
 	// This is a basic conversion and may not be accurate for all use cases
+	tokens, _ := r.Window(WindowTokensMinute)
+	requests, _ := r.Window(WindowRequestsDay)
 	return &UsageMetrics{
 		OrganizationID: orgID,
 		ModelName:      modelName,
-		TokensUsed:     r.LimitTokensMinute - r.RemainingTokensMinute,
+		TokensUsed:     tokens.Used,
 		TokensLimit:    r.LimitTokensMinute,
-		RequestsUsed:   r.LimitRequestsDay - r.RemainingRequestsDay,
+		RequestsUsed:   requests.Used,
 		RequestsLimit:  r.LimitRequestsDay,
 		Quotas:         []Quota{*r.ToQuota()},
 	}
@@ -90,59 +90,30 @@ func (r *RateLimitInfo) ToUsageMetrics(orgID, modelName string) *UsageMetrics {
 
 // Quota represents rate limit quota information
 type Quota struct {
-	Limit     int64  `json:"limit,omitempty"`
+	Limit     Limit  `json:"limit,omitempty"`
 	Remaining int64  `json:"remaining,omitempty"`
 	ResetTime string `json:"reset_time,omitempty"`
 }
 
 // UsageQuota represents usage quota information for an organization
-type UsageQuota struct {
-	ModelId             string `json:"modelId,omitempty"`
-	RegionId            string `json:"regionId,omitempty"`
-	OrganizationId      string `json:"organizationId,omitempty"`
-	RequestsPerMinute   string `json:"requestsPerMinute,omitempty"`
-	TokensPerMinute     string `json:"tokensPerMinute,omitempty"`
-	RequestsPerHour     string `json:"requestsPerHour,omitempty"`
-	TokensPerHour       string `json:"tokensPerHour,omitempty"`
-	RequestsPerDay      string `json:"requestsPerDay,omitempty"`
-	TokensPerDay        string `json:"tokensPerDay,omitempty"`
-	MaxSequenceLength   string `json:"maxSequenceLength,omitempty"`
-	MaxCompletionTokens string `json:"maxCompletionTokens,omitempty"`
-	Typename            string `json:"__typename,omitempty"`
-}
+type UsageQuota = graphql.UsageQuota
 
 // UsageMetrics represents the usage metrics for an organization
 type UsageMetrics struct {
 	OrganizationID string  `json:"organization_id,omitempty"`
 	ModelName      string  `json:"model_name,omitempty"`
 	TokensUsed     int64   `json:"tokens_used,omitempty"`
-	TokensLimit    int64   `json:"tokens_limit,omitempty"`
+	TokensLimit    Limit   `json:"tokens_limit,omitempty"`
 	RequestsUsed   int64   `json:"requests_used,omitempty"`
-	RequestsLimit  int64   `json:"requests_limit,omitempty"`
+	RequestsLimit  Limit   `json:"requests_limit,omitempty"`
 	Quotas         []Quota `json:"quotas,omitempty"`
 }
 
 // OrganizationUsage represents usage data for an organization
-type OrganizationUsage struct {
-	ModelId  string `json:"modelId,omitempty"`  // Model identifier
-	RegionId string `json:"regionId,omitempty"` // Region identifier
-	RPM      string `json:"rpm,omitempty"`      // Requests Per Minute
-	TPM      string `json:"tpm,omitempty"`      // Tokens Per Minute
-	RPH      string `json:"rph,omitempty"`      // Requests Per Hour
-	TPH      string `json:"tph,omitempty"`      // Tokens Per Hour
-	RPD      string `json:"rpd,omitempty"`      // Requests Per Day
-	TPD      string `json:"tpd,omitempty"`      // Tokens Per Day
-	Typename string `json:"__typename,omitempty"`
-}
+type OrganizationUsage = graphql.OrganizationUsage
 
 // Organization represents a Cerebras organization
-type Organization struct {
-	ID               string `json:"id,omitempty"`
-	Name             string `json:"name,omitempty"`
-	OrganizationType string `json:"organizationType,omitempty"`
-	State            string `json:"state,omitempty"`
-	Typename         string `json:"__typename,omitempty"`
-}
+type Organization = graphql.Organization
 
 // OrganizationsResponse represents the response from the organizations endpoint
 type OrganizationsResponse struct {
@@ -64,7 +64,7 @@ func TestRateLimitInfoToUsageMetrics(t *testing.T) {
 		t.Errorf("Expected ModelName %s, got %s", modelName, usageMetrics.ModelName)
 	}
 
-	expectedTokensUsed := rateLimit.LimitTokensMinute - rateLimit.RemainingTokensMinute
+	expectedTokensUsed := int64(rateLimit.LimitTokensMinute) - rateLimit.RemainingTokensMinute
 	if usageMetrics.TokensUsed != expectedTokensUsed {
 		t.Errorf("Expected TokensUsed %d, got %d", expectedTokensUsed, usageMetrics.TokensUsed)
 	}
@@ -73,7 +73,7 @@ func TestRateLimitInfoToUsageMetrics(t *testing.T) {
 		t.Errorf("Expected TokensLimit %d, got %d", rateLimit.LimitTokensMinute, usageMetrics.TokensLimit)
 	}
 
-	expectedRequestsUsed := rateLimit.LimitRequestsDay - rateLimit.RemainingRequestsDay
+	expectedRequestsUsed := int64(rateLimit.LimitRequestsDay) - rateLimit.RemainingRequestsDay
 	if usageMetrics.RequestsUsed != expectedRequestsUsed {
 		t.Errorf("Expected RequestsUsed %d, got %d", expectedRequestsUsed, usageMetrics.RequestsUsed)
 	}
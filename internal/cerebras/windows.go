@@ -0,0 +1,182 @@
+package cerebras
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rate limit window names, in display order
+const (
+	WindowRequestsMinute = "requests-minute"
+	WindowRequestsHour   = "requests-hour"
+	WindowRequestsDay    = "requests-day"
+	WindowTokensMinute   = "tokens-minute"
+	WindowTokensHour     = "tokens-hour"
+	WindowTokensDay      = "tokens-day"
+)
+
+// Windows lists every rate limit window tracked in RateLimitInfo
+var Windows = []string{
+	WindowRequestsMinute,
+	WindowRequestsHour,
+	WindowRequestsDay,
+	WindowTokensMinute,
+	WindowTokensHour,
+	WindowTokensDay,
+}
+
+// WindowUsage is the state of a single rate limit window
+type WindowUsage struct {
+	Name      string
+	Used      int64
+	Limit     Limit
+	Remaining int64
+	Reset     int64 // seconds until reset, 0 when unknown
+}
+
+// Percent returns the used share of the limit, or 0 when the limit is
+// unknown or unlimited
+func (w WindowUsage) Percent() float64 {
+	return w.Limit.Percent(w.Used)
+}
+
+// WindowPeriod returns the length of the named window
+func WindowPeriod(name string) (time.Duration, error) {
+	switch name {
+	case WindowRequestsMinute, WindowTokensMinute:
+		return time.Minute, nil
+	case WindowRequestsHour, WindowTokensHour:
+		return time.Hour, nil
+	case WindowRequestsDay, WindowTokensDay:
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown window %q", name)
+	}
+}
+
+// Rate limit field kinds, as used in the X-Ratelimit-{Kind}-{Window} headers
+const (
+	FieldLimit     = "limit"
+	FieldRemaining = "remaining"
+	FieldReset     = "reset"
+	FieldUsage     = "usage"
+)
+
+// LimitField returns a pointer to the limit of the named window, or nil when
+// the window is unknown
+func (r *RateLimitInfo) LimitField(window string) *Limit {
+	switch window {
+	case WindowRequestsMinute:
+		return &r.LimitRequestsMinute
+	case WindowRequestsHour:
+		return &r.LimitRequestsHour
+	case WindowRequestsDay:
+		return &r.LimitRequestsDay
+	case WindowTokensMinute:
+		return &r.LimitTokensMinute
+	case WindowTokensHour:
+		return &r.LimitTokensHour
+	case WindowTokensDay:
+		return &r.LimitTokensDay
+	default:
+		return nil
+	}
+}
+
+// Field returns a pointer to the count of the given kind (remaining, reset or
+// usage) for the named window, or nil when either is unknown. Limits are
+// typed, see LimitField.
+func (r *RateLimitInfo) Field(kind, window string) *int64 {
+	var fields [3]*int64 // remaining, reset, usage
+	switch window {
+	case WindowRequestsMinute:
+		fields = [3]*int64{&r.RemainingRequestsMinute, &r.ResetRequestsMinute, &r.UsageRequestsMinute}
+	case WindowRequestsHour:
+		fields = [3]*int64{&r.RemainingRequestsHour, &r.ResetRequestsHour, &r.UsageRequestsHour}
+	case WindowRequestsDay:
+		fields = [3]*int64{&r.RemainingRequestsDay, &r.ResetRequestsDay, &r.UsageRequestsDay}
+	case WindowTokensMinute:
+		fields = [3]*int64{&r.RemainingTokensMinute, &r.ResetTokensMinute, &r.UsageTokensMinute}
+	case WindowTokensHour:
+		fields = [3]*int64{&r.RemainingTokensHour, &r.ResetTokensHour, &r.UsageTokensHour}
+	case WindowTokensDay:
+		fields = [3]*int64{&r.RemainingTokensDay, &r.ResetTokensDay, &r.UsageTokensDay}
+	default:
+		return nil
+	}
+
+	switch kind {
+	case FieldRemaining:
+		return fields[0]
+	case FieldReset:
+		return fields[1]
+	case FieldUsage:
+		return fields[2]
+	default:
+		return nil
+	}
+}
+
+// Elapsed returns a copy of metrics fetched d ago, with reset countdowns
+// reduced by d so they show the time left as of now
+func (r *RateLimitInfo) Elapsed(d time.Duration) *RateLimitInfo {
+	c := *r
+	elapsed := int64(d / time.Second)
+	if elapsed <= 0 {
+		return &c
+	}
+	for _, window := range Windows {
+		if reset := c.Field(FieldReset, window); *reset > 0 {
+			*reset -= elapsed
+			if *reset < 0 {
+				*reset = 0
+			}
+		}
+	}
+	return &c
+}
+
+// Window returns the usage of the named window. Used prefers reported usage
+// and otherwise derives it as limit - remaining for numeric limits.
+func (r *RateLimitInfo) Window(name string) (WindowUsage, error) {
+	usage := r.Field(FieldUsage, name)
+	if usage == nil {
+		return WindowUsage{}, fmt.Errorf("unknown window %q", name)
+	}
+	w := WindowUsage{
+		Name:      name,
+		Used:      *usage,
+		Limit:     *r.LimitField(name),
+		Remaining: *r.Field(FieldRemaining, name),
+		Reset:     *r.Field(FieldReset, name),
+	}
+
+	if limit, ok := w.Limit.Value(); ok && w.Used <= 0 && w.Remaining >= 0 {
+		w.Used = limit - w.Remaining
+		if w.Used < 0 {
+			w.Used = 0
+		}
+	}
+
+	return w, nil
+}
+
+// Tightest returns the window with a numeric limit that is most used, or
+// false when no window has one
+func (r *RateLimitInfo) Tightest() (WindowUsage, bool) {
+	var tightest WindowUsage
+	found := false
+	for _, name := range Windows {
+		w, err := r.Window(name)
+		if err != nil {
+			continue
+		}
+		if _, ok := w.Limit.Value(); !ok {
+			continue
+		}
+		if !found || w.Percent() > tightest.Percent() {
+			tightest, found = w, true
+		}
+	}
+	return tightest, found
+}
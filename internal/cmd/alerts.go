@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/alerts"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var AlertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "List, snooze and test alerts",
+	Long: `Crossed soft limits, budgets and alert rules are tracked as alerts in the
+usage database. An alert stays open while its condition holds and is
+notified once when it fires, again when it escalates from warning to
+critical, and when it resolves. Snoozed alerts are not notified until the
+snooze ends; a running dashboard or daemon picks up snoozes set here on its
+next refresh. Rules under "alerts.rules" can be tried against the recorded
+history with "alerts test".`,
+}
+
+var listAlertsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open alerts",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		queries, closeDB, err := openAlerts()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		organization := alertsOrganization()
+		all, _ := cmd.Flags().GetBool("all")
+		var list []db.Alert
+		if all {
+			limit, _ := cmd.Flags().GetInt("limit")
+			if limit <= 0 {
+				return usageErrorf("invalid --limit %d: must be positive", limit)
+			}
+			list, err = queries.GetRecentAlerts(cmd.Context(), db.GetRecentAlertsParams{OrganizationID: organization, Limit: int64(limit)})
+		} else {
+			list, err = queries.GetUnacknowledgedAlerts(cmd.Context(), organization)
+		}
+		if err != nil {
+			return fmt.Errorf("reading alerts: %w", err)
+		}
+		if list == nil {
+			list = []db.Alert{}
+		}
+		if IsJSONOutput() {
+			return printJSON(cmd, list)
+		}
+
+		out := cmd.OutOrStdout()
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No open alerts.")
+			return nil
+		}
+		loc := config.GetLocation()
+		for _, a := range list {
+			status := a.Status
+			switch {
+			case a.Status == alerts.StatusSnoozed && a.SnoozedUntil != nil:
+				status = "snoozed until " + a.SnoozedUntil.In(loc).Format("2006-01-02 15:04")
+			case a.Status == alerts.StatusResolved && a.ResolvedAt != nil:
+				status = "resolved " + a.ResolvedAt.In(loc).Format("2006-01-02 15:04")
+			}
+			message := a.MetricName
+			if a.Message != nil {
+				message = *a.Message
+			}
+			fmt.Fprintf(out, "#%-5d %s  %-8s %-12s %s\n   %s\n", a.ID, a.Timestamp.In(loc).Format("2006-01-02 15:04"), a.Severity, a.AlertType, status, message)
+		}
+		return nil
+	},
+}
+
+var snoozeAlertsCmd = &cobra.Command{
+	Use:   "snooze [id...]",
+	Short: "Snooze open alerts",
+	Long: `Hold back the notifications of the given open alerts, or of every open alert
+of the organization, for a while. Escalations during the snooze are
+delivered when it ends if the alert is still open.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		duration, _ := cmd.Flags().GetDuration("for")
+		if duration <= 0 {
+			return usageErrorf("invalid --for %s: must be positive", duration)
+		}
+		until := time.Now().Add(duration).UTC().Truncate(time.Second)
+		return setSnooze(cmd, args, alerts.StatusSnoozed, &until)
+	},
+}
+
+var unsnoozeAlertsCmd = &cobra.Command{
+	Use:   "unsnooze [id...]",
+	Short: "Lift the snooze of alerts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSnooze(cmd, args, alerts.StatusActive, nil)
+	},
+}
+
+var testAlertsCmd = &cobra.Command{
+	Use:   "test [rule...]",
+	Short: "Dry-run alert rules against usage history",
+	Long: `Replay the alert rules under "alerts.rules" in settings.yaml, or the named
+ones, against the usage snapshots recorded for the organization and model,
+and report when they would have fired. Nothing is stored or notified.
+
+History records the tokens-minute, requests-day and tokens-day windows, so
+rules on other windows report their metric as unknown.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dispatcher, err := notify.Load()
+		if err != nil {
+			return usageErrorf("invalid notifiers configuration: %v", err)
+		}
+		rules, err := alerts.LoadRules(notifierNames(dispatcher))
+		if err != nil {
+			return usageErrorf("invalid alerts configuration: %v", err)
+		}
+		for _, name := range args {
+			if !slices.ContainsFunc(rules, func(r alerts.Rule) bool { return r.Name == name }) {
+				return usageErrorf("unknown rule %q", name)
+			}
+		}
+		if len(args) > 0 {
+			rules = slices.DeleteFunc(rules, func(r alerts.Rule) bool { return !slices.Contains(args, r.Name) })
+		}
+		if len(rules) == 0 {
+			return usageErrorf("no alert rules configured, add them under alerts.rules in %s", config.GetConfigPath())
+		}
+		since, _ := cmd.Flags().GetDuration("since")
+		if since <= 0 {
+			return usageErrorf("invalid --since %s: must be positive", since)
+		}
+
+		queries, closeDB, err := openAlerts()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		organization := alertsOrganization()
+		model := viper.GetString("model")
+		region := viper.GetString("region")
+		now := time.Now().UTC()
+		snapshots, err := queries.GetUsageSnapshotsBetween(cmd.Context(), db.GetUsageSnapshotsBetweenParams{
+			OrganizationID: organization,
+			ModelName:      model,
+			RegionID:       region,
+			StartTime:      now.Add(-since),
+			EndTime:        now,
+		})
+		if err != nil {
+			return fmt.Errorf("reading usage history: %w", err)
+		}
+		var rollups []db.UsageMetric
+		for _, window := range []string{collector.RollupHour, collector.RollupDay} {
+			period, _ := collector.RollupPeriod(window)
+			rows, err := queries.GetUsageMetrics(cmd.Context(), db.GetUsageMetricsParams{
+				OrganizationID: organization,
+				ModelName:      model,
+				RegionID:       region,
+				TimeWindow:     window,
+				Limit:          int64(since/period) + 1,
+			})
+			if err != nil {
+				return fmt.Errorf("reading usage rollups: %w", err)
+			}
+			rollups = append(rollups, rows...)
+		}
+
+		results := alerts.Replay(rules, organization, model, snapshots, rollups)
+		if IsJSONOutput() {
+			return printJSON(cmd, results)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Replayed %d snapshot(s) of %s (model: %s) over the last %s\n", len(snapshots), organization, model, since)
+		if len(results) < len(rules) {
+			fmt.Fprintf(out, "%d rule(s) do not cover this organization and model\n", len(rules)-len(results))
+		}
+		loc := config.GetLocation()
+		for _, res := range results {
+			if res.Rule.Name == res.Rule.String() {
+				fmt.Fprintf(out, "\n%s [%s]\n", res.Rule, res.Rule.Severity)
+			} else {
+				fmt.Fprintf(out, "\n%s [%s]: %s\n", res.Rule.Name, res.Rule.Severity, res.Rule)
+			}
+			switch {
+			case res.Polls > 0 && res.Unknown == res.Polls:
+				fmt.Fprintln(out, "  metric not available in the recorded history")
+				continue
+			case len(res.Fired) == 0:
+				fmt.Fprintln(out, "  never fired")
+			default:
+				fmt.Fprintf(out, "  fired %d time(s)\n", len(res.Fired))
+			}
+			for _, p := range res.Fired {
+				fmt.Fprintf(out, "  %s - %s  peak %s\n", p.Start.In(loc).Format("2006-01-02 15:04"), p.End.In(loc).Format("15:04"), strconv.FormatFloat(p.Peak, 'f', 2, 64))
+			}
+			if res.Unknown > 0 {
+				fmt.Fprintf(out, "  metric unknown in %d of %d snapshot(s)\n", res.Unknown, res.Polls)
+			}
+		}
+		return nil
+	},
+}
+
+// setSnooze updates the snooze of the alerts with the given IDs, or of every
+// open alert when none are given
+func setSnooze(cmd *cobra.Command, args []string, status string, until *time.Time) error {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id <= 0 {
+			return usageErrorf("invalid alert ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	queries, closeDB, err := openAlerts()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	organization := alertsOrganization()
+	var changed int64
+	if len(ids) == 0 {
+		changed, err = queries.SnoozeOpenAlerts(cmd.Context(), db.SnoozeOpenAlertsParams{
+			Status:         status,
+			SnoozedUntil:   until,
+			OrganizationID: organization,
+		})
+		if err != nil {
+			return fmt.Errorf("updating alerts: %w", err)
+		}
+	}
+	for _, id := range ids {
+		n, err := queries.SnoozeAlert(cmd.Context(), db.SnoozeAlertParams{
+			Status:         status,
+			SnoozedUntil:   until,
+			ID:             id,
+			OrganizationID: organization,
+		})
+		if err != nil {
+			return fmt.Errorf("updating alert %d: %w", id, err)
+		}
+		if n == 0 {
+			return usageErrorf("no open alert #%d for organization %s", id, organization)
+		}
+		changed += n
+	}
+
+	if IsJSONOutput() {
+		return printJSON(cmd, struct {
+			Changed      int64      `json:"changed"`
+			SnoozedUntil *time.Time `json:"snoozed_until"`
+		}{changed, until})
+	}
+	out := cmd.OutOrStdout()
+	switch {
+	case changed == 0:
+		fmt.Fprintln(out, "No open alerts.")
+	case until != nil:
+		fmt.Fprintf(out, "Snoozed %d alert(s) until %s\n", changed, until.In(config.GetLocation()).Format("2006-01-02 15:04"))
+	default:
+		fmt.Fprintf(out, "Lifted the snooze of %d alert(s)\n", changed)
+	}
+	return nil
+}
+
+// alertsOrganization returns the organization ID alerts are stored under
+func alertsOrganization() string {
+	if organization := viper.GetString("org-id"); organization != "" {
+		return organization
+	}
+	return collector.DefaultOrganization
+}
+
+// openAlerts opens the usage database holding the alerts
+func openAlerts() (*db.Queries, func(), error) {
+	conn, err := db.Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening database: %w", err)
+	}
+	return db.New(conn), func() {
+		_ = conn.Close()
+	}, nil
+}
+
+func init() {
+	listAlertsCmd.Flags().Bool("all", false, "Include resolved alerts")
+	listAlertsCmd.Flags().Int("limit", 50, "Number of alerts listed with --all")
+	snoozeAlertsCmd.Flags().Duration("for", time.Hour, "How long to snooze, e.g. 30m or 8h")
+	AlertsCmd.AddCommand(listAlertsCmd)
+	AlertsCmd.AddCommand(snoozeAlertsCmd)
+	AlertsCmd.AddCommand(unsnoozeAlertsCmd)
+	testAlertsCmd.Flags().Duration("since", 24*time.Hour, "How much history to replay")
+	AlertsCmd.AddCommand(testAlertsCmd)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/diskcache"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the metrics cache",
+	Long: `One-shot commands such as "usage get" share fetched metrics through a cache in
+the data directory, so frequent invocations (status bars, cron jobs) do not
+call the API every time.`,
+}
+
+var clearCacheCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached metrics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := diskcache.Open()
+		if err != nil {
+			return err
+		}
+		if err := store.Clear(); err != nil {
+			return fmt.Errorf("clearing cache: %w", err)
+		}
+		progressf(cmd, "Cache cleared.\n")
+		return nil
+	},
+}
+
+// refreshCacheCmd is started in the background by stale-while-revalidate
+var refreshCacheCmd = &cobra.Command{
+	Use:    "refresh [organization]",
+	Short:  "Fetch metrics and update the cache",
+	Hidden: true,
+	Args:   usageArgs(cobra.MaximumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		organization := viper.GetString("org-id")
+		if len(args) > 0 {
+			organization = args[0]
+		}
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+		store, err := diskcache.Open()
+		if err != nil {
+			return err
+		}
+		return store.Refresh(cmd.Context(), cacheKey(client, organization), func(ctx context.Context) (*cerebras.RateLimitInfo, error) {
+			return client.GetMetrics(ctx, organization)
+		})
+	},
+}
+
+// addCacheFlags adds the flags controlling the metrics cache to cmd
+func addCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("max-age", diskcache.DefaultMaxAge, "Serve cached metrics younger than this without calling the API")
+	cmd.Flags().Bool("no-cache", false, "Always fetch live metrics")
+	cmd.Flags().Bool("stale", false, "Serve expired metrics immediately and refresh the cache in the background")
+}
+
+// cacheKey identifies the metrics of the client, organization and region
+func cacheKey(client *cerebras.Client, organization string) diskcache.Key {
+	model := viper.GetString("model")
+	if model == "" {
+		model = "qwen-3-coder-480b"
+	}
+	return diskcache.Key{Organization: organization, Model: model, Region: client.Region(), AuthMode: client.DataSource(organization)}
+}
+
+// fetchMetricsCached returns the metrics of the organization from a running
+// daemon polling it, or through the on-disk cache, honoring --max-age,
+// --no-cache and --stale. The cache is skipped when it cannot be opened.
+func fetchMetricsCached(cmd *cobra.Command, client *cerebras.Client, organization string) (*diskcache.Result, error) {
+	fetch := func(ctx context.Context) (*cerebras.RateLimitInfo, error) {
+		return client.GetMetrics(ctx, organization)
+	}
+
+	opts := diskcache.LoadOptions()
+	if cmd.Flags().Changed("max-age") {
+		opts.MaxAge, _ = cmd.Flags().GetDuration("max-age")
+	}
+	opts.NoCache, _ = cmd.Flags().GetBool("no-cache")
+	if cmd.Flags().Changed("stale") {
+		opts.Stale, _ = cmd.Flags().GetBool("stale")
+	}
+	opts.Revalidate = func(key diskcache.Key) error {
+		return startCacheRefresh(cmd, client, key)
+	}
+
+	key := cacheKey(client, organization)
+	if !opts.NoCache {
+		if d, err := daemon.Dial(cmd.Context()); err == nil {
+			if snap, err := d.SnapshotFor(cmd.Context(), organization, key.Model, key.Region); err == nil {
+				entry := &diskcache.Entry{Key: key, FetchedAt: snap.FetchedAt, Metrics: snap.At(time.Now()).Metrics}
+				return &diskcache.Result{Entry: entry, Cached: true}, nil
+			}
+		}
+	}
+
+	store, err := diskcache.Open()
+	if err != nil {
+		info, err := fetch(cmd.Context())
+		if err != nil {
+			return nil, err
+		}
+		return &diskcache.Result{Entry: &diskcache.Entry{FetchedAt: time.Now().UTC(), Metrics: info}}, nil
+	}
+	return store.Fetch(cmd.Context(), key, opts, fetch)
+}
+
+// refreshForwardedFlags are passed on to "cache refresh" when set, so the
+// background fetch is configured like the command that started it
+var refreshForwardedFlags = []string{"timezone", "log-level", "log-file", "debug"}
+
+// startCacheRefresh runs "cache refresh" for the key in a detached process
+// that outlives this one. The credentials of the client are passed through
+// the environment rather than the command line, where other users could
+// read them.
+func startCacheRefresh(cmd *cobra.Command, client *cerebras.Client, key diskcache.Key) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"cache", "refresh", "--model", key.Model, "--region=" + key.Region}
+	for _, name := range refreshForwardedFlags {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			args = append(args, "--"+name+"="+f.Value.String())
+		}
+	}
+	if key.Organization != "" {
+		args = append(args, key.Organization)
+	}
+	refresh := exec.Command(exe, args...)
+	refresh.Env = os.Environ()
+	if token := client.SessionToken(); token != "" {
+		refresh.Env = append(refresh.Env, "CEREBRAS_SESSION_TOKEN="+token)
+	}
+	if apiKey := client.APIKey(); apiKey != "" {
+		refresh.Env = append(refresh.Env, "CEREBRAS_API_KEY="+apiKey)
+	}
+	if err := refresh.Start(); err != nil {
+		return err
+	}
+	return refresh.Process.Release()
+}
+
+func init() {
+	CacheCmd.AddCommand(clearCacheCmd)
+	CacheCmd.AddCommand(refreshCacheCmd)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Check states, which are also the exit codes of "check" as expected by
+// Nagios-compatible monitoring
+const (
+	CheckOK       = 0
+	CheckWarning  = 1
+	CheckCritical = 2
+	CheckUnknown  = 3
+)
+
+// checkStates names the check states, indexed by state
+var checkStates = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+var CheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check rate limit usage against thresholds",
+	Long: `Fetch the rate limits once and print a one-line status with performance
+data, for CI jobs and monitoring systems such as Nagios, Icinga or Zabbix.
+
+The exit code is the worst state of the checked windows:
+
+  0  OK        every window is below --warn
+  1  WARNING   a window is at or above --warn
+  2  CRITICAL  a window is at or above --crit
+  3  UNKNOWN   the rate limits could not be fetched, a window given with
+               --window has no reported limit, or the flags are invalid
+
+Unlimited windows are always OK. Without --window every window with a
+reported limit is checked.`,
+	Example: `  cerebras-monitor check --warn 75 --crit 90
+  cerebras-monitor check --window tokens-day --window requests-day`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		warn, _ := cmd.Flags().GetFloat64("warn")
+		crit, _ := cmd.Flags().GetFloat64("crit")
+		if warn <= 0 || crit <= 0 || warn > crit {
+			return checkUnknown(cmd, usageErrorf("invalid thresholds --warn %g --crit %g: expected 0 < warn <= crit", warn, crit))
+		}
+		windows, _ := cmd.Flags().GetStringSlice("window")
+		for _, w := range windows {
+			if !slices.Contains(cerebras.Windows, w) {
+				return checkUnknown(cmd, usageErrorf("unknown window %q, expected one of %s", w, strings.Join(cerebras.Windows, ", ")))
+			}
+		}
+
+		organization := viper.GetString("org-id")
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return checkUnknown(cmd, err)
+		}
+		if err := requireOrganization(client, organization); err != nil {
+			return checkUnknown(cmd, err)
+		}
+		metrics, err := client.GetMetrics(cmd.Context(), organization)
+		if err != nil {
+			return checkUnknown(cmd, fmt.Errorf("fetching metrics: %w", err))
+		}
+
+		result := evaluateCheck(metrics, windows, warn, crit)
+		if IsJSONOutput() {
+			if err := printJSON(cmd, result); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), result)
+		}
+		if result.Code == CheckOK {
+			return nil
+		}
+		return ExitStatus(result.Code)
+	},
+}
+
+// checkWindow is the checked state of one rate limit window
+type checkWindow struct {
+	Window  string         `json:"window"`
+	State   string         `json:"state"`
+	Used    int64          `json:"used"`
+	Limit   cerebras.Limit `json:"limit"`
+	Percent *float64       `json:"percent"`
+}
+
+// checkResult is the outcome of "check"
+type checkResult struct {
+	State    string        `json:"state"`
+	Code     int           `json:"exit_code"`
+	Warn     float64       `json:"warn"`
+	Crit     float64       `json:"crit"`
+	Message  string        `json:"message,omitempty"`
+	Windows  []checkWindow `json:"windows"`
+	perfdata []string
+}
+
+// evaluateCheck compares the windows, or every window with a known limit
+// when none are given, against the warning and critical percentages
+func evaluateCheck(metrics *cerebras.RateLimitInfo, windows []string, warn, crit float64) checkResult {
+	res := checkResult{Code: CheckOK, Warn: warn, Crit: crit, Windows: []checkWindow{}}
+	explicit := len(windows) > 0
+	if !explicit {
+		windows = cerebras.Windows
+	}
+
+	for _, name := range windows {
+		w, err := metrics.Window(name)
+		if err != nil || (!w.Limit.IsKnown() && !explicit) {
+			continue
+		}
+		cw := checkWindow{Window: name, Used: w.Used, Limit: w.Limit}
+		code := CheckOK
+		switch limit, ok := w.Limit.Value(); {
+		case ok:
+			percent := w.Percent()
+			cw.Percent = &percent
+			switch {
+			case percent >= crit:
+				code = CheckCritical
+			case percent >= warn:
+				code = CheckWarning
+			}
+			res.perfdata = append(res.perfdata,
+				fmt.Sprintf("'%s'=%.2f%%;%g;%g;0;100", name, percent, warn, crit),
+				fmt.Sprintf("'%s_used'=%d;;;0;%d", name, w.Used, limit))
+		case w.Limit.IsUnlimited():
+			res.perfdata = append(res.perfdata, fmt.Sprintf("'%s_used'=%d;;;0;", name, w.Used))
+		default:
+			code = CheckUnknown
+		}
+		cw.State = checkStates[code]
+		res.Windows = append(res.Windows, cw)
+		res.Code = worseCheck(res.Code, code)
+	}
+
+	if len(res.Windows) == 0 {
+		res.Code = CheckUnknown
+		res.Message = "no rate limit window reported a limit"
+	}
+	res.State = checkStates[res.Code]
+	return res
+}
+
+// worseCheck returns the more severe of two states. UNKNOWN ranks between
+// WARNING and CRITICAL: an unreadable window may hide a breach, but not a
+// confirmed one.
+func worseCheck(a, b int) int {
+	rank := []int{CheckOK: 0, CheckWarning: 1, CheckUnknown: 2, CheckCritical: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// String renders the result as a plugin output line, e.g.
+// "CEREBRAS WARNING - tokens-day 80.0% (800000/1000000) | 'tokens-day'=80.00%;75;90;0;100 ..."
+func (r checkResult) String() string {
+	parts := make([]string, 0, len(r.Windows))
+	for _, w := range r.Windows {
+		switch {
+		case w.Percent != nil:
+			parts = append(parts, fmt.Sprintf("%s %.1f%% (%d/%s)", w.Window, *w.Percent, w.Used, w.Limit))
+		case w.Limit.IsUnlimited():
+			parts = append(parts, fmt.Sprintf("%s unlimited (%d used)", w.Window, w.Used))
+		default:
+			parts = append(parts, fmt.Sprintf("%s limit unknown", w.Window))
+		}
+	}
+	if r.Message != "" {
+		parts = append(parts, r.Message)
+	}
+	line := fmt.Sprintf("CEREBRAS %s - %s", r.State, strings.Join(parts, ", "))
+	if len(r.perfdata) > 0 {
+		line += " | " + strings.Join(r.perfdata, " ")
+	}
+	return line
+}
+
+// checkUnknown reports an error as an UNKNOWN result
+func checkUnknown(cmd *cobra.Command, err error) error {
+	res := checkResult{State: checkStates[CheckUnknown], Code: CheckUnknown, Message: err.Error(), Windows: []checkWindow{}}
+	if IsJSONOutput() {
+		if err := printJSON(cmd, res); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), res)
+	}
+	return ExitStatus(CheckUnknown)
+}
+
+func init() {
+	CheckCmd.Flags().Float64("warn", 75, "Usage percentage of a window that is WARNING")
+	CheckCmd.Flags().Float64("crit", 90, "Usage percentage of a window that is CRITICAL")
+	CheckCmd.Flags().StringSlice("window", nil, "Window to check, repeatable (default every window with a reported limit)")
+	// Monitoring systems read invalid flags as UNKNOWN rather than CRITICAL
+	CheckCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return checkUnknown(cmd, FlagError(cmd, err))
+	})
+}
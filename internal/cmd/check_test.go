@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+func TestEvaluateCheck(t *testing.T) {
+	metrics := &cerebras.RateLimitInfo{
+		LimitTokensDay:        cerebras.LimitFromInt(1000000),
+		UsageTokensDay:        800000,
+		LimitTokensMinute:     cerebras.LimitFromInt(100000),
+		UsageTokensMinute:     95000,
+		LimitRequestsDay:      cerebras.LimitUnlimited,
+		UsageRequestsDay:      42,
+		RemainingTokensMinute: 5000,
+	}
+
+	tests := []struct {
+		name    string
+		windows []string
+		code    int
+		checked int
+	}{
+		{name: "every known window", code: CheckCritical, checked: 3},
+		{name: "warning window", windows: []string{cerebras.WindowTokensDay}, code: CheckWarning, checked: 1},
+		{name: "unlimited window", windows: []string{cerebras.WindowRequestsDay}, code: CheckOK, checked: 1},
+		{name: "unknown window", windows: []string{cerebras.WindowTokensDay, cerebras.WindowRequestsHour}, code: CheckUnknown, checked: 2},
+		{name: "critical beats unknown", windows: []string{cerebras.WindowTokensMinute, cerebras.WindowRequestsHour}, code: CheckCritical, checked: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := evaluateCheck(metrics, tt.windows, 75, 90)
+			if res.Code != tt.code || res.State != checkStates[tt.code] {
+				t.Errorf("Expected %s, got %s (%d)", checkStates[tt.code], res.State, res.Code)
+			}
+			if len(res.Windows) != tt.checked {
+				t.Errorf("Expected %d checked windows, got %+v", tt.checked, res.Windows)
+			}
+		})
+	}
+
+	if res := evaluateCheck(&cerebras.RateLimitInfo{}, nil, 75, 90); res.Code != CheckUnknown {
+		t.Errorf("Expected UNKNOWN without any reported limit, got %s", res.State)
+	}
+}
+
+func TestCheckResultString(t *testing.T) {
+	metrics := &cerebras.RateLimitInfo{
+		LimitTokensDay:   cerebras.LimitFromInt(1000000),
+		UsageTokensDay:   800000,
+		LimitRequestsDay: cerebras.LimitUnlimited,
+		UsageRequestsDay: 42,
+	}
+	res := evaluateCheck(metrics, []string{cerebras.WindowTokensDay, cerebras.WindowRequestsDay}, 75, 90)
+
+	want := "CEREBRAS WARNING - tokens-day 80.0% (800000/1000000), requests-day unlimited (42 used)" +
+		" | 'tokens-day'=80.00%;75;90;0;100 'tokens-day_used'=800000;;;0;1000000 'requests-day_used'=42;;;0;"
+	if got := res.String(); got != want {
+		t.Errorf("Unexpected output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	var out bytes.Buffer
+	cmd := CheckCmd
+	cmd.SetOut(&out)
+	defer cmd.SetOut(nil)
+
+	err := checkUnknown(cmd, usageErrorf("bad flag"))
+	if code := ExitCode(err); code != CheckUnknown {
+		t.Errorf("Expected exit code %d, got %d", CheckUnknown, code)
+	}
+	if !strings.HasPrefix(out.String(), "CEREBRAS UNKNOWN - invalid usage: bad flag") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+	var reported bytes.Buffer
+	ReportError(&reported, err)
+	if reported.Len() != 0 {
+		t.Errorf("Expected the status to be reported by the command only, got %q", reported.String())
+	}
+}
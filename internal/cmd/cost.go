@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var CostCmd = &cobra.Command{
+	Use:   "cost [organization]",
+	Short: "Show estimated spend and budgets",
+	Long: `Show the estimated spend for the current day, week and month, computed from
+the usage history recorded by the dashboard and priced with the pricing table
+from settings.yaml.`,
+	Args: usageArgs(cobra.MaximumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		organization := viper.GetString("org-id")
+		if len(args) > 0 {
+			organization = args[0]
+		}
+		if organization == "" {
+			organization = collector.DefaultOrganization
+		}
+
+		model := viper.GetString("model")
+		if model == "" {
+			model = "qwen-3-coder-480b"
+		}
+
+		prices, err := billing.LoadPriceTable()
+		if err != nil {
+			return usageErrorf("invalid pricing configuration: %v", err)
+		}
+		if prices.Empty() {
+			return usageErrorf("no pricing configured, add a pricing section to %s", config.GetConfigPath())
+		}
+		budgets, err := billing.LoadBudgets()
+		if err != nil {
+			return usageErrorf("invalid budgets configuration: %v", err)
+		}
+		rule, err := resets.LoadRule()
+		if err != nil {
+			return usageErrorf("invalid resets configuration: %v", err)
+		}
+
+		conn, err := db.Open()
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		region := viper.GetString("region")
+		ledger := billing.NewLedger(db.New(conn), prices)
+		spend, err := ledger.Spend(cmd.Context(), organization, model, region, rule, time.Now())
+		if err != nil {
+			return fmt.Errorf("computing spend: %w", err)
+		}
+		alerts := budgets.Evaluate(spend)
+		if IsJSONOutput() {
+			return printJSON(cmd, struct {
+				Organization string                `json:"organization"`
+				Model        string                `json:"model"`
+				Region       string                `json:"region,omitempty"`
+				Spend        billing.Spend         `json:"spend"`
+				Alerts       []billing.BudgetAlert `json:"alerts"`
+			}{organization, model, region, spend, alerts})
+		}
+
+		withBudget := func(amount, budget float64) string {
+			s := billing.FormatAmount(amount, spend.Currency)
+			if budget > 0 {
+				s += fmt.Sprintf(" / %s budget (%.0f%%)", billing.FormatAmount(budget, spend.Currency), amount/budget*100)
+			}
+			return s
+		}
+
+		if region != "" {
+			fmt.Printf("Estimated cost for organization %s (model: %s, region: %s):\n", organization, model, region)
+		} else {
+			fmt.Printf("Estimated cost for organization %s (model: %s):\n", organization, model)
+		}
+		fmt.Printf("  Today:      %s\n", withBudget(spend.Day, budgets.Daily))
+		fmt.Printf("  This week:  %s\n", billing.FormatAmount(spend.Week, spend.Currency))
+		fmt.Printf("  This month: %s\n", withBudget(spend.Month, budgets.Monthly))
+
+		for _, alert := range alerts {
+			fmt.Printf("\n%s %s\n", config.GetIcons().Warning, alert.Message(spend.Currency))
+		}
+		return nil
+	},
+}
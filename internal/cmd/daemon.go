@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var DaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the background poller and local API",
+	Long: `Run a long-lived process that owns the poll loop, history collector, alerts
+and cache, and serves the results as JSON over a Unix socket (and optionally
+TCP on loopback). The dashboard and "usage get" use a running daemon
+automatically and poll directly when none is running.
+
+Endpoints:
+  GET /v1/status   uptime, last poll and last error
+  GET /v1/metrics  latest snapshot
+  GET /v1/history  usage snapshots, ?since=1h (default 24h)
+  GET /v1/usage-metrics  hourly or daily rollups, ?window=hour&limit=48
+  GET /v1/alerts   unacknowledged alerts
+  GET /v1/events   server-sent events, one "snapshot" per poll`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		organization := viper.GetString("org-id")
+		model := viper.GetString("model")
+		if model == "" {
+			model = "qwen-3-coder-480b"
+		}
+		refreshRate := viper.GetInt("refresh-rate")
+		if refreshRate < 1 {
+			refreshRate = 10
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireOrganization(client, organization); err != nil {
+			return err
+		}
+
+		socket, err := daemon.SocketPath()
+		if err != nil {
+			return err
+		}
+		listeners := []net.Listener{}
+		unixListener, err := daemon.Listen(socket)
+		if err != nil {
+			return fmt.Errorf("listening on %s: %w", socket, err)
+		}
+		listeners = append(listeners, unixListener)
+		if addr := viper.GetString("daemon.listen"); addr != "" {
+			tcpListener, err := daemon.ListenTCP(addr)
+			if err != nil {
+				_ = unixListener.Close()
+				return usageErrorf("%v", err)
+			}
+			listeners = append(listeners, tcpListener)
+		}
+
+		server, _, closeMonitor, err := newPollServer(cmd.Context(), client, organization, model, time.Duration(refreshRate)*time.Second)
+		if err != nil {
+			_ = unixListener.Close()
+			return err
+		}
+		defer closeMonitor()
+
+		go server.Run(cmd.Context())
+		for _, l := range listeners {
+			progressf(cmd, "Listening on %s://%s\n", l.Addr().Network(), l.Addr())
+		}
+		return server.Serve(cmd.Context(), listeners...)
+	},
+}
+
+// forwardedFlags are passed on to the daemon by "daemon install" when set
+var forwardedFlags = []string{"org-id", "model", "region", "refresh-rate", "timezone", "debug", "socket", "listen"}
+
+var installDaemonCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a systemd user unit running the daemon",
+	Long: `Write ` + daemon.UnitName + ` to the systemd user unit directory
+(~/.config/systemd/user). The unit runs this binary with the organization,
+model, refresh rate and socket flags given to this command, and the CEREBRAS_*
+environment variables set now. It restarts the daemon after failures.
+
+No root is needed; with --now the unit is also enabled and started.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, _ := cmd.Flags().GetString("binary")
+		if exe == "" {
+			var err error
+			if exe, err = os.Executable(); err != nil {
+				return fmt.Errorf("locating the binary: %w", err)
+			}
+		}
+		exe, err := filepath.Abs(exe)
+		if err != nil {
+			return err
+		}
+
+		unit := daemon.Unit{Executable: exe, Args: []string{"daemon"}, Environment: map[string]string{}}
+		for _, name := range forwardedFlags {
+			if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+				unit.Args = append(unit.Args, "--"+name+"="+f.Value.String())
+			}
+		}
+		for _, kv := range os.Environ() {
+			if key, value, ok := strings.Cut(kv, "="); ok && value != "" && (strings.HasPrefix(key, "CEREBRAS_") || key == "XDG_CONFIG_HOME") {
+				unit.Environment[key] = value
+			}
+		}
+		env, _ := cmd.Flags().GetStringArray("env")
+		for _, kv := range env {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				return usageErrorf("invalid --env %q: expected KEY=VALUE", kv)
+			}
+			unit.Environment[key] = value
+		}
+
+		path, err := daemon.UnitPath()
+		if err != nil {
+			return err
+		}
+		if err := unit.Install(path); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+
+		now, _ := cmd.Flags().GetBool("now")
+		if now {
+			if err := systemctl("daemon-reload"); err != nil {
+				return err
+			}
+			if err := systemctl("enable", "--now", daemon.UnitName); err != nil {
+				return err
+			}
+		}
+
+		if IsJSONOutput() {
+			return printJSON(cmd, struct {
+				Path      string   `json:"path"`
+				ExecStart []string `json:"exec_start"`
+				Enabled   bool     `json:"enabled"`
+			}{path, append([]string{exe}, unit.Args...), now})
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", path)
+		if now {
+			fmt.Fprintf(cmd.OutOrStdout(), "Enabled and started %s\n", daemon.UnitName)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Start it with:\n  systemctl --user daemon-reload\n  systemctl --user enable --now %s\n", daemon.UnitName)
+		}
+		return nil
+	},
+}
+
+var uninstallDaemonCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop the daemon and remove its systemd user unit",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := daemon.UnitPath()
+		if err != nil {
+			return err
+		}
+		_, statErr := os.Stat(path)
+		installed := statErr == nil
+
+		// Stopping is best-effort: systemd may not be running at all
+		if installed {
+			_ = systemctl("disable", "--now", daemon.UnitName)
+		}
+		if err := daemon.Uninstall(path); err != nil {
+			return fmt.Errorf("removing %s: %w", path, err)
+		}
+		if installed {
+			_ = systemctl("daemon-reload")
+		}
+
+		if IsJSONOutput() {
+			return printJSON(cmd, struct {
+				Path    string `json:"path"`
+				Removed bool   `json:"removed"`
+			}{path, installed})
+		}
+		if installed {
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", path)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is not installed\n", daemon.UnitName)
+		}
+		return nil
+	},
+}
+
+var statusDaemonCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of the running daemon",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := daemon.Dial(cmd.Context())
+		if err != nil {
+			return err
+		}
+		status, err := client.Status(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("querying daemon: %w", err)
+		}
+		if IsJSONOutput() {
+			return printJSON(cmd, status)
+		}
+
+		now := time.Now()
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Daemon running (pid %d)\n", status.PID)
+		fmt.Fprintf(out, "  Uptime: %s\n", time.Duration(status.Uptime*float64(time.Second)).Round(time.Second))
+		if status.Organization != "" {
+			fmt.Fprintf(out, "  Organization: %s\n", status.Organization)
+		}
+		fmt.Fprintf(out, "  Model: %s\n", status.Model)
+		if status.Region != "" {
+			fmt.Fprintf(out, "  Region: %s\n", status.Region)
+		}
+		fmt.Fprintf(out, "  Listening: %s\n", strings.Join(status.Addresses, ", "))
+		fmt.Fprintf(out, "  Last poll: %s\n", formatSince(status.LastPoll, now))
+		fmt.Fprintf(out, "  Last successful poll: %s\n", formatSince(status.LastSuccess, now))
+		if status.LastError != "" {
+			fmt.Fprintf(out, "  Last error: %s\n", status.LastError)
+		} else {
+			fmt.Fprintf(out, "  Last error: none\n")
+		}
+		return nil
+	},
+}
+
+// formatSince formats a time as RFC 3339 and how long ago it was
+func formatSince(at *time.Time, now time.Time) string {
+	if at == nil {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s ago)", at.Local().Format(time.RFC3339), now.Sub(*at).Round(time.Second))
+}
+
+// systemctl runs "systemctl --user" with args
+func systemctl(args ...string) error {
+	path, err := exec.LookPath("systemctl")
+	if err != nil {
+		return fmt.Errorf("systemctl not found: %w", err)
+	}
+	out, err := exec.Command(path, append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl --user %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func init() {
+	DaemonCmd.PersistentFlags().String("socket", "", "Unix socket to serve on (default: daemon.sock in the data directory)")
+	DaemonCmd.PersistentFlags().String("listen", "", "Also serve on this loopback TCP address, e.g. 127.0.0.1:9465")
+	_ = viper.BindPFlag("daemon.socket", DaemonCmd.PersistentFlags().Lookup("socket"))
+	_ = viper.BindPFlag("daemon.listen", DaemonCmd.PersistentFlags().Lookup("listen"))
+
+	installDaemonCmd.Flags().String("binary", "", "Path of the binary to run (default: this binary)")
+	installDaemonCmd.Flags().StringArray("env", nil, "Extra environment variable for the daemon as KEY=VALUE (repeatable)")
+	installDaemonCmd.Flags().Bool("now", false, "Also enable and start the unit with systemctl")
+
+	DaemonCmd.AddCommand(installDaemonCmd)
+	DaemonCmd.AddCommand(uninstallDaemonCmd)
+	DaemonCmd.AddCommand(statusDaemonCmd)
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/tui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -13,8 +16,16 @@ import (
 var DashboardCmd = &cobra.Command{
 	Use:   "dashboard",
 	Short: "Open the TUI dashboard",
-	Long:  "Open a real-time dashboard with bubbletea TUI to monitor Cerebras AI usage",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Open a real-time dashboard with bubbletea TUI to monitor Cerebras AI usage.
+
+With session token authentication and a watch list (see "organizations
+--watch"), every watched organization is polled and the Overview tab shows
+the tightest window of each; press enter on one to open it.
+
+Press m to switch to another model the organization has a quota for, and o
+to switch to another organization; s in the organization list also saves
+it as the one to monitor.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get organization ID from configuration/viper
 		organization := viper.GetString("org-id")
 
@@ -31,25 +42,77 @@ var DashboardCmd = &cobra.Command{
 		}
 
 		// Create Cerebras client
-		client := cerebras.NewClient()
-		if !client.HasAuth() {
-			fmt.Println("Error: No authentication method configured. Please login first.")
-			return
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		// API keys are bound to one organization, so only session tokens watch several
+		var watched []string
+		if client.SessionToken() != "" {
+			watched = watchList(config.WatchedOrganizations(), organization)
+			if organization == "" && len(watched) > 0 {
+				organization = watched[0]
+			}
 		}
 
 		// For session token auth, organization is required
 		// Only require organization if we're using session token auth (not API key auth)
-		if client.SessionToken() != "" && client.APIKey() == "" && organization == "" {
-			fmt.Println("Error: organization ID must be set via --org-id flag or configuration when using session token authentication")
-			return
+		if err := requireOrganization(client, organization); err != nil {
+			return err
+		}
+
+		mon, closeMonitor, err := newMonitor(cmd.Context(), client, organization, modelName)
+		if err != nil {
+			return err
 		}
+		defer closeMonitor()
 
 		// Create and run the dashboard model
-		dashboardModel := tui.NewDashboardModel(client, organization, modelName, refreshRate)
+		dashboardModel := tui.NewDashboardModel(mon, refreshRate).
+			WithContext(cmd.Context())
+
+		if len(watched) > 1 {
+			monitors := make([]*monitor.Monitor, 0, len(watched))
+			for _, id := range watched {
+				if id == organization {
+					monitors = append(monitors, mon)
+					continue
+				}
+				other, closeOther, err := newMonitor(cmd.Context(), client, id, modelName)
+				if err != nil {
+					return err
+				}
+				defer closeOther()
+				monitors = append(monitors, other)
+			}
+			// Names are cosmetic; rows fall back to IDs when the list fails
+			names := make(map[string]string)
+			if orgs, err := client.GraphQL().ListOrganizations(cmd.Context()); err == nil {
+				for _, org := range orgs {
+					names[org.ID] = org.Name
+				}
+			}
+			dashboardModel = dashboardModel.WithOrganizations(monitors, names)
+		}
+
+		// A running daemon already polls; show its snapshots instead
+		if d, err := daemon.Dial(cmd.Context()); err == nil {
+			dashboardModel = dashboardModel.WithDaemon(d)
+		}
 		p := tea.NewProgram(dashboardModel, tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
-			fmt.Printf("Error running dashboard: %v\n", err)
-			return
+			return fmt.Errorf("running dashboard: %w", err)
 		}
+		return nil
 	},
 }
+
+// watchList returns the organizations to watch: the configured ones, with
+// the monitored organization first when it is not among them
+func watchList(watched []string, organization string) []string {
+	if len(watched) == 0 || organization == "" || slices.Contains(watched, organization) {
+		return watched
+	}
+	return append([]string{organization}, watched...)
+}
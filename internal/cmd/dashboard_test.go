@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWatchList(t *testing.T) {
+	tests := []struct {
+		name         string
+		watched      []string
+		organization string
+		want         []string
+	}{
+		{"no watch list", nil, "org_a", nil},
+		{"organization watched", []string{"org_a", "org_b"}, "org_b", []string{"org_a", "org_b"}},
+		{"organization not watched", []string{"org_a", "org_b"}, "org_c", []string{"org_c", "org_a", "org_b"}},
+		{"no organization", []string{"org_a", "org_b"}, "", []string{"org_a", "org_b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := watchList(tt.watched, tt.organization); !slices.Equal(got, tt.want) {
+				t.Errorf("watchList(%v, %q) = %v, want %v", tt.watched, tt.organization, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Exit codes returned by the binary, one per error class
+const (
+	ExitOK             = 0
+	ExitError          = 1 // unexpected failure
+	ExitUsage          = 2 // invalid arguments, flags or configuration
+	ExitUnauthorized   = 3 // no credentials, or the API rejected them
+	ExitNoOrganization = 4 // session token auth without an organization
+	ExitRateLimited    = 5 // the API answered HTTP 429
+	ExitNetwork        = 6 // the API could not be reached
+	ExitSchema         = 7 // the API answered with an unexpected shape
+)
+
+// ErrUsage marks invalid arguments, flags or configuration
+var ErrUsage = errors.New("invalid usage")
+
+// ExitStatus is returned by commands that already wrote their result and
+// only set the exit code, such as "check"
+type ExitStatus int
+
+func (s ExitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// Output formats accepted by --output
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// errorClass maps an error class to its name in JSON output and exit code
+type errorClass struct {
+	err  error
+	name string
+	code int
+}
+
+// errorClasses is checked in order; the first match wins
+var errorClasses = []errorClass{
+	{ErrUsage, "usage", ExitUsage},
+	{cerebras.ErrUnauthorized, "unauthorized", ExitUnauthorized},
+	{cerebras.ErrNoOrganization, "no_organization", ExitNoOrganization},
+	{cerebras.ErrModelNotFound, "model_not_found", ExitUsage},
+	{cerebras.ErrRateLimited, "rate_limited", ExitRateLimited},
+	{cerebras.ErrNetwork, "network", ExitNetwork},
+	{cerebras.ErrSchema, "schema", ExitSchema},
+}
+
+// classify returns the class name and exit code of an error
+func classify(err error) (string, int) {
+	var status ExitStatus
+	if errors.As(err, &status) {
+		return "status", int(status)
+	}
+	for _, class := range errorClasses {
+		if errors.Is(err, class.err) {
+			return class.name, class.code
+		}
+	}
+	return "error", ExitError
+}
+
+// ExitCode returns the process exit code for an error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	_, code := classify(err)
+	return code
+}
+
+// ReportError writes err to w, as a JSON object when --output json is set,
+// and returns the exit code for it
+func ReportError(w io.Writer, err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	name, code := classify(err)
+	if errors.As(err, new(ExitStatus)) {
+		return code
+	}
+	if IsJSONOutput() {
+		_ = json.NewEncoder(w).Encode(struct {
+			Error    string `json:"error"`
+			Code     string `json:"code"`
+			ExitCode int    `json:"exit_code"`
+		}{err.Error(), name, code})
+		return code
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+	return code
+}
+
+// usageErrorf returns an ErrUsage error with a formatted message
+func usageErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrUsage, fmt.Sprintf(format, args...))
+}
+
+// FlagError wraps cobra flag parsing errors as usage errors
+func FlagError(cmd *cobra.Command, err error) error {
+	return fmt.Errorf("%w: %w", ErrUsage, err)
+}
+
+// usageArgs wraps a positional argument validator so its errors are usage errors
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return fmt.Errorf("%w: %w", ErrUsage, err)
+		}
+		return nil
+	}
+}
+
+// ValidateOutput checks the --output flag
+func ValidateOutput() error {
+	switch viper.GetString("output") {
+	case "", OutputText, OutputJSON:
+		return nil
+	default:
+		return usageErrorf("invalid --output %q: expected text or json", viper.GetString("output"))
+	}
+}
+
+// IsJSONOutput reports whether --output json is set
+func IsJSONOutput() bool {
+	return viper.GetString("output") == OutputJSON
+}
+
+// printJSON writes v to the command's output as indented JSON
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// progressf prints a progress message unless the output is JSON
+func progressf(cmd *cobra.Command, format string, args ...interface{}) {
+	if !IsJSONOutput() {
+		fmt.Fprintf(cmd.OutOrStdout(), format, args...)
+	}
+}
+
+// newAuthenticatedClient creates a client and fails when no credentials are
+// configured
+func newAuthenticatedClient() (*cerebras.Client, error) {
+	client := cerebras.NewClient()
+	if !client.HasAuth() {
+		return nil, fmt.Errorf("%w: no authentication method configured, please login first", cerebras.ErrUnauthorized)
+	}
+	return client, nil
+}
+
+// requireOrganization fails when session token auth is used without an
+// organization; API key auth does not need one
+func requireOrganization(client *cerebras.Client, organization string) error {
+	if client.SessionToken() != "" && client.APIKey() == "" && organization == "" {
+		return fmt.Errorf("%w: organization must be provided either as an argument or via --org-id flag when using session token authentication", cerebras.ErrNoOrganization)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
+	"github.com/spf13/viper"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"usage", usageErrorf("bad flag"), ExitUsage},
+		{"unauthorized sentinel", fmt.Errorf("fetching: %w", cerebras.ErrUnauthorized), ExitUnauthorized},
+		{"graphql unauthorized", &graphql.UnauthorizedError{Operation: "ListMyOrganizations", StatusCode: 401}, ExitUnauthorized},
+		{"no organization", fmt.Errorf("%w: missing", cerebras.ErrNoOrganization), ExitNoOrganization},
+		{"rate limited", fmt.Errorf("%w: 429", cerebras.ErrRateLimited), ExitRateLimited},
+		{"network", fmt.Errorf("%w: %w", cerebras.ErrNetwork, errors.New("dial tcp")), ExitNetwork},
+		{"schema", &graphql.SchemaMismatchError{Operation: "ListOrganizationUsage", Field: "ListOrganizationUsage"}, ExitSchema},
+		{"model not found", fmt.Errorf("fetching metrics: %w", &cerebras.ModelNotFoundError{Model: "m"}), ExitUsage},
+		{"exit status", ExitStatus(CheckCritical), CheckCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportError(t *testing.T) {
+	defer viper.Set("output", "")
+	err := fmt.Errorf("fetching metrics: %w", cerebras.ErrRateLimited)
+
+	viper.Set("output", OutputText)
+	var text bytes.Buffer
+	if code := ReportError(&text, err); code != ExitRateLimited {
+		t.Errorf("Expected exit code %d, got %d", ExitRateLimited, code)
+	}
+	if text.String() != "Error: fetching metrics: rate limited\n" {
+		t.Errorf("Unexpected text output %q", text.String())
+	}
+
+	viper.Set("output", OutputJSON)
+	var out bytes.Buffer
+	ReportError(&out, err)
+	var decoded struct {
+		Error    string `json:"error"`
+		Code     string `json:"code"`
+		ExitCode int    `json:"exit_code"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", out.String(), err)
+	}
+	if decoded.Code != "rate_limited" || decoded.ExitCode != ExitRateLimited || decoded.Error != err.Error() {
+		t.Errorf("Unexpected JSON error %+v", decoded)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	defer viper.Set("output", "")
+
+	viper.Set("output", "yaml")
+	if err := ValidateOutput(); ExitCode(err) != ExitUsage {
+		t.Errorf("Expected usage error for yaml output, got %v", err)
+	}
+	viper.Set("output", OutputJSON)
+	if err := ValidateOutput(); err != nil {
+		t.Errorf("Expected json output to be valid, got %v", err)
+	}
+}
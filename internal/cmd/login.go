@@ -21,8 +21,8 @@ var loginWithCookieCmd = &cobra.Command{
 you'll need to manually copy the 'authjs.session-token' cookie value from your browser's 
 Developer Tools > Application > Cookies. This cookie is only used to fetch your usage data 
 from the Cerebras platform. The tool is open source and you can inspect the code yourself.`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cookieValue := args[0]
 		fmt.Printf("Logging in with cookie: %s\n", cookieValue)
 
@@ -33,18 +33,18 @@ from the Cerebras platform. The tool is open source and you can inspect the code
 			// If config file doesn't exist, create it
 			err = viper.SafeWriteConfig()
 			if err != nil {
-				fmt.Printf("Error saving configuration: %v\n", err)
-				return
+				return fmt.Errorf("saving configuration: %w", err)
 			}
 		}
 		fmt.Println("Session cookie saved successfully!")
+		return nil
 	},
 }
 
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from Cerebras platform",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("Logging out...")
 
 		// Clear the saved authentication from configuration
@@ -52,10 +52,10 @@ var logoutCmd = &cobra.Command{
 		viper.Set("api-key", "")
 		err := viper.WriteConfig()
 		if err != nil {
-			fmt.Printf("Error clearing configuration: %v\n", err)
-			return
+			return fmt.Errorf("clearing configuration: %w", err)
 		}
 		fmt.Println("Authentication cleared successfully!")
+		return nil
 	},
 }
 
@@ -72,8 +72,8 @@ Note that API key authentication has limitations:
 You can either specify the API key as an environment variable CEREBRAS_API_KEY or use this command
 to save it to your local database at ` + "`" + config.GetConfigPath() + "`" + `,
 which follows XDG conventions and uses YAML format.`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		apiKeyValue := args[0]
 		fmt.Printf("Logging in with API key: %s\n", apiKeyValue)
 
@@ -84,11 +84,11 @@ which follows XDG conventions and uses YAML format.`,
 			// If config file doesn't exist, create it
 			err = viper.SafeWriteConfig()
 			if err != nil {
-				fmt.Printf("Error saving configuration: %v\n", err)
-				return
+				return fmt.Errorf("saving configuration: %w", err)
 			}
 		}
 		fmt.Println("API key saved successfully!")
+		return nil
 	},
 }
 
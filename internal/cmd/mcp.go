@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/mcp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Version is the version reported to MCP clients, set by main
+var Version = "dev"
+
+var McpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve rate limits to coding agents over the Model Context Protocol",
+	Long: `Speak the Model Context Protocol (JSON-RPC) over stdin and stdout, so coding
+agents can check their quota before starting large jobs. Agents launch this
+command themselves; nothing but protocol messages is written to stdout.
+
+Tools:
+  get_rate_limits         usage, limit, remaining and reset of every window
+  predict_exhaustion      when each window runs out at the recent burn rate
+  list_models_and_quotas  limits and usage of every model of the organization
+  get_usage_history       recorded snapshots, or hourly or daily rollups
+
+Outputs are compact JSON. Session tokens and API keys both work; with an API
+key only the monitored model is listed, and usage history needs the usage
+database.`,
+	Example: `  # Register with an agent that reads an mcpServers configuration
+  {"mcpServers": {"cerebras": {"command": "cerebras-monitor", "args": ["mcp"]}}}`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		organization := viper.GetString("org-id")
+		model := viper.GetString("model")
+		if model == "" {
+			model = "qwen-3-coder-480b"
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireOrganization(client, organization); err != nil {
+			return err
+		}
+
+		backend := &mcp.Backend{
+			Client:              client,
+			Organization:        organization,
+			Model:               model,
+			HistoryOrganization: organization,
+			Region:              client.Region(),
+		}
+		if organization == "" {
+			backend.HistoryOrganization = collector.DefaultOrganization
+		}
+		// History is optional; the live tools work without the database
+		if conn, err := db.Open(); err == nil {
+			defer func() {
+				_ = conn.Close()
+			}()
+			backend.Queries = db.New(conn)
+		}
+
+		// Stdout carries the protocol alone; debug output goes to stderr
+		out := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() {
+			os.Stdout = out
+		}()
+
+		server := mcp.NewServer("cerebras-monitor", Version, backend.Tools()...)
+		return server.Serve(cmd.Context(), os.Stdin, out)
+	},
+}
@@ -24,12 +24,9 @@ Available commands:
   migrate     Execute pending migrations 
   status      Show current migration status
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no subcommand is called, show help
-		if err := cmd.Help(); err != nil {
-			fmt.Printf("Error displaying help: %v\n", err)
-			os.Exit(1)
-		}
+		return cmd.Help()
 	},
 }
 
@@ -39,25 +36,25 @@ var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show migration status",
 	Long:  "Show pending database migrations that need to be applied",
-	Run: func(cmd *cobra.Command, args []string) {
-		initDBMate()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := initDBMate(); err != nil {
+			return err
+		}
 
 		availableMigrations, err := dbm.FindMigrations()
 		if err != nil {
-			fmt.Printf("Error listing available migrations: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("listing available migrations: %w", err)
 		}
 
 		if len(availableMigrations) == 0 {
 			fmt.Println("Migration Status:\n\n! No migrations found")
-			return
+			return nil
 		}
 
 		// Get migrations status
 		pending, err := dbm.Status(false)
 		if err != nil {
-			fmt.Printf("Error getting migrations status: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("getting migrations status: %w", err)
 		}
 
 		fmt.Printf("📊 Migration Status: \n \\--> ")
@@ -71,6 +68,7 @@ var statusCmd = &cobra.Command{
 				fmt.Printf("  📎 %s\n", m.Version)
 			}
 		}
+		return nil
 	},
 }
 
@@ -78,36 +76,35 @@ var migrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Run database migrations",
 	Long:  "Apply all pending database migrations to update the schema",
-	Run: func(cmd *cobra.Command, args []string) {
-		initDBMate()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := initDBMate(); err != nil {
+			return err
+		}
 
 		// If --force is enabled, try to drop the database first
 		if force {
 			if err := dbm.Drop(); err != nil {
-				fmt.Printf("Error dropping database: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("dropping database: %w", err)
 			}
 			if err := dbm.Create(); err != nil {
-				fmt.Printf("Error recreating database: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("recreating database: %w", err)
 			}
 		}
 
 		if err := dbm.CreateAndMigrate(); err != nil {
-			fmt.Printf("Error executing migrations: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("executing migrations: %w", err)
 		}
 
 		fmt.Println("Migrations executed successfully!")
+		return nil
 	},
 }
 
-func initDBMate() {
+func initDBMate() error {
 	// Get database path - use XDG directory by default
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("Error getting user home directory: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("getting user home directory: %w", err)
 	}
 
 	dbPath := filepath.Join(homeDir, ".local", "share", "cerebras-code", "database.db")
@@ -126,6 +123,7 @@ func initDBMate() {
 	// Set schema file path
 	schemaDir := filepath.Dir(dbPath)
 	dbm.SchemaFile = filepath.Join(schemaDir, "schema.sql")
+	return nil
 }
 func init() {
 	MigrationsCmd.AddCommand(statusCmd)
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/alerts"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/diskcache"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
+)
+
+// newMonitor creates a monitor configured with pricing, budgets, soft limits,
+// alert rules, reset rules, the alert policy and notifiers. History is enabled when the usage database opens; the
+// returned function closes it.
+func newMonitor(ctx context.Context, client *cerebras.Client, organization, model string) (*monitor.Monitor, func(), error) {
+	prices, err := billing.LoadPriceTable()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid pricing configuration: %v", err)
+	}
+	budgets, err := billing.LoadBudgets()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid budgets configuration: %v", err)
+	}
+	softLimits, err := pacing.LoadSoftLimits()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid soft-limits configuration: %v", err)
+	}
+	resetRule, err := resets.LoadRule()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid resets configuration: %v", err)
+	}
+	estimator := resets.NewEstimator(resetRule)
+	policy, err := alerts.LoadPolicy()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid alerts configuration: %v", err)
+	}
+	dispatcher, err := notify.Load()
+	if err != nil {
+		return nil, nil, usageErrorf("invalid notifiers configuration: %v", err)
+	}
+	rules, err := alerts.LoadRules(notifierNames(dispatcher))
+	if err != nil {
+		return nil, nil, usageErrorf("invalid alerts configuration: %v", err)
+	}
+
+	mon := monitor.New(client, organization, model).
+		WithSoftLimits(softLimits).
+		WithRules(rules).
+		WithResetEstimator(estimator).
+		WithAlertPolicy(policy).
+		WithNotifications(dispatcher)
+
+	// History is optional; monitoring still works without the database
+	conn, err := db.Open()
+	if err != nil {
+		return mon, func() {}, nil
+	}
+	queries := db.New(conn)
+	_ = estimator.LearnFromHistory(ctx, queries, mon.HistoryOrganization(), model, mon.Region(), time.Now())
+	mon.WithHistory(queries, prices, budgets)
+	return mon, func() {
+		_ = conn.Close()
+	}, nil
+}
+
+// newPollServer creates a daemon server polling through a new monitor every
+// interval, with history and the disk cache when available. The returned
+// function closes the monitor.
+func newPollServer(ctx context.Context, client *cerebras.Client, organization, model string, interval time.Duration) (*daemon.Server, *monitor.Monitor, func(), error) {
+	mon, closeMonitor, err := newMonitor(ctx, client, organization, model)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	server := daemon.NewServer(mon, organization, model, mon.Region(), interval)
+	if queries := mon.Queries(); queries != nil {
+		server.WithHistory(queries, mon.HistoryOrganization())
+	}
+	if store, err := diskcache.Open(); err == nil {
+		server.WithCache(store)
+	}
+	return server, mon, closeMonitor, nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var NotifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage alert notifiers",
+	Long: `Soft limit, budget and rule alerts are delivered to the notifiers
+configured under "notifiers" in settings.yaml: JSON webhooks, Slack, Discord
+and Mattermost incoming webhooks, ntfy topics, SMTP email and shell commands.
+Each notifier can filter by severity and template its title and message.`,
+}
+
+var listNotifiersCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured notifiers",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dispatcher, err := notify.Load()
+		if err != nil {
+			return usageErrorf("invalid notifiers configuration: %v", err)
+		}
+		type notifierInfo struct {
+			Name        string `json:"name"`
+			Type        string `json:"type"`
+			MinSeverity string `json:"min_severity,omitempty"`
+		}
+		infos := []notifierInfo{}
+		for _, t := range dispatcher.Targets() {
+			infos = append(infos, notifierInfo{Name: t.Name, Type: notifierType(t.Notifier), MinSeverity: t.MinSeverity})
+		}
+		if IsJSONOutput() {
+			return printJSON(cmd, infos)
+		}
+		out := cmd.OutOrStdout()
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No notifiers configured.")
+			return nil
+		}
+		for _, info := range infos {
+			severity := info.MinSeverity
+			if severity == "" {
+				severity = "all"
+			}
+			fmt.Fprintf(out, "%-20s %-11s %s\n", info.Name, info.Type, severity)
+		}
+		return nil
+	},
+}
+
+var testNotifiersCmd = &cobra.Command{
+	Use:   "test [name...]",
+	Short: "Send a sample alert to notifiers",
+	Long: `Send a sample alert to every configured notifier, or to the named ones,
+ignoring their severity filters. Fails when any delivery fails.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dispatcher, err := notify.Load()
+		if err != nil {
+			return usageErrorf("invalid notifiers configuration: %v", err)
+		}
+		targets := dispatcher.Targets()
+		for _, name := range args {
+			if !slices.ContainsFunc(targets, func(t *notify.Target) bool { return t.Name == name }) {
+				return usageErrorf("unknown notifier %q", name)
+			}
+		}
+		if len(args) > 0 {
+			targets = slices.DeleteFunc(slices.Clone(targets), func(t *notify.Target) bool {
+				return !slices.Contains(args, t.Name)
+			})
+		}
+		if len(targets) == 0 {
+			return usageErrorf("no notifiers configured")
+		}
+
+		severity, _ := cmd.Flags().GetString("severity")
+		if notify.SeverityRank(severity) == 0 {
+			return usageErrorf("invalid --severity %q: expected info, warning or critical", severity)
+		}
+		event := notify.Event{
+			Time:         time.Now().UTC().Truncate(time.Second),
+			Organization: viper.GetString("org-id"),
+			Model:        viper.GetString("model"),
+			Type:         "test",
+			Severity:     severity,
+			Metric:       "tokens-day",
+			Value:        80,
+			Threshold:    75,
+			Message:      "Test alert from cerebras-monitor: 80.0% of tokens-day used",
+		}
+
+		type result struct {
+			Name  string `json:"name"`
+			OK    bool   `json:"ok"`
+			Error string `json:"error,omitempty"`
+		}
+		results := make([]result, len(targets))
+		var wg sync.WaitGroup
+		for i, t := range targets {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(cmd.Context(), notify.DefaultTimeout)
+				defer cancel()
+				results[i] = result{Name: t.Name, OK: true}
+				if err := t.Send(ctx, event); err != nil {
+					results[i] = result{Name: t.Name, Error: err.Error()}
+				}
+			}()
+		}
+		wg.Wait()
+
+		var failed []string
+		for _, r := range results {
+			if !r.OK {
+				failed = append(failed, r.Name)
+			}
+		}
+		if IsJSONOutput() {
+			if err := printJSON(cmd, results); err != nil {
+				return err
+			}
+		} else {
+			out := cmd.OutOrStdout()
+			for _, r := range results {
+				if r.OK {
+					fmt.Fprintf(out, "%s: sent\n", r.Name)
+				} else {
+					fmt.Fprintf(out, "%s: failed: %s\n", r.Name, r.Error)
+				}
+			}
+		}
+		if len(failed) > 0 {
+			return errors.New("delivery failed for " + strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+// notifierNames returns the names of the configured notifiers
+func notifierNames(d *notify.Dispatcher) []string {
+	var names []string
+	for _, t := range d.Targets() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// notifierType names the backend of a notifier
+func notifierType(n notify.Notifier) string {
+	switch n := n.(type) {
+	case *notify.Webhook:
+		return notify.TypeWebhook
+	case *notify.Chat:
+		return n.Flavor
+	case *notify.Ntfy:
+		return notify.TypeNtfy
+	case *notify.SMTP:
+		return notify.TypeSMTP
+	case *notify.Command:
+		return notify.TypeCommand
+	default:
+		return fmt.Sprintf("%T", n)
+	}
+}
+
+func init() {
+	testNotifiersCmd.Flags().String("severity", notify.SeverityWarning, "Severity of the sample alert: info, warning or critical")
+	NotifyCmd.AddCommand(listNotifiersCmd)
+	NotifyCmd.AddCommand(testNotifiersCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/proxy"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// proxyTarget is the API the proxy forwards to
+const proxyTarget = "https://api.cerebras.ai"
+
+var ProxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Forward API requests and record their token split",
+	Long: `Forward OpenAI-compatible requests to the Cerebras API and record the
+prompt and completion tokens of every response in the usage database.
+
+Point a coding agent at the proxy instead of https://api.cerebras.ai; requests
+and their API keys are forwarded unchanged. Cost reports then price proxied
+traffic with the exact input/output split and only the rest of the usage with
+the blended prompt-ratio estimate. The rate limit headers of proxied responses
+are kept for API-key monitoring, which then skips its own probes.`,
+	Example: `  cerebras-monitor proxy --listen 127.0.0.1:8787
+  OPENAI_BASE_URL=http://127.0.0.1:8787/v1 your-agent`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen := viper.GetString("proxy.listen")
+		loopback, err := daemon.IsLoopback(listen)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		if !loopback {
+			return usageErrorf("refusing to proxy on %s: the proxy forwards the API keys of its clients, bind to 127.0.0.1", listen)
+		}
+
+		organization := viper.GetString("org-id")
+		if organization == "" {
+			organization = collector.DefaultOrganization
+		}
+
+		conn, err := db.Open()
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		target, _ := url.Parse(proxyTarget)
+		listener, err := net.Listen("tcp", listen)
+		if err != nil {
+			return fmt.Errorf("listening on %s: %w", listen, err)
+		}
+		server := &http.Server{
+			Handler:           proxy.New(target, db.New(conn), organization),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-cmd.Context().Done()
+			_ = server.Close()
+		}()
+
+		progressf(cmd, "Proxying %s on http://%s\n", proxyTarget, listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	ProxyCmd.Flags().String("listen", "127.0.0.1:8787", "Address to accept API requests on")
+	_ = viper.BindPFlag("proxy.listen", ProxyCmd.Flags().Lookup("listen"))
+}
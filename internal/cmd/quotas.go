@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,8 +17,8 @@ var QuotasCmd = &cobra.Command{
 var getQuotasCmd = &cobra.Command{
 	Use:   "get [organizationID]",
 	Short: "Get quotas for an organization",
-	Args:  cobra.MaximumNArgs(1), // Make organizationID optional
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:  usageArgs(cobra.MaximumNArgs(1)), // Make organizationID optional
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// If organizationID is not provided, use the one from configuration
 		organizationID := ""
 		if len(args) > 0 {
@@ -28,14 +29,14 @@ var getQuotasCmd = &cobra.Command{
 		}
 
 		if organizationID == "" {
-			fmt.Println("Error: organization ID must be provided either as an argument or via --org-id flag")
-			return
+			return fmt.Errorf("%w: organization ID must be provided either as an argument or via --org-id flag", cerebras.ErrNoOrganization)
 		}
 
 		fmt.Printf("Getting quotas for organization %s...\n", organizationID)
 		// TODO: Implement quota retrieval logic
 		// This would make a request to the Cerebras GraphQL endpoint
 		// and extract rate limit information from response headers
+		return nil
 	},
 }
 
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/muesli/termenv"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/sshserver"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/tui"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/web"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+)
+
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the dashboard over the network",
+	Long: `Poll the selected organization and model once and serve the dashboard to
+remote viewers. All viewers share the poller; none of them can change the
+organization, model or credentials.
+
+With --http the dashboard is a web page showing the rate limit cards, hourly
+and daily usage charts and alerts, updated live over server-sent events. The
+daemon API is also available under /api/v1. Binding beyond loopback requires
+--token or --basic-auth. A token can be given as "Authorization: Bearer
+<token>" or by opening /?token=<token> once.
+
+With --ssh every SSH session gets the terminal dashboard. Clients log in with
+any user name and a key listed in --authorized-keys; the host key is generated
+on first start.`,
+	Example: `  cerebras-monitor serve --http 127.0.0.1:7788
+  cerebras-monitor serve --http :7788 --token "$(openssl rand -hex 16)"
+  cerebras-monitor serve --ssh :2222   # then: ssh -p 2222 monitor@host`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		httpAddr := viper.GetString("serve.http")
+		sshAddr := viper.GetString("serve.ssh")
+		if httpAddr == "" && sshAddr == "" {
+			return usageErrorf("nothing to serve: pass --http <address> or --ssh <address>")
+		}
+		auth, err := serveAuth()
+		if err != nil {
+			return err
+		}
+		if httpAddr != "" {
+			loopback, err := daemon.IsLoopback(httpAddr)
+			if err != nil {
+				return usageErrorf("%v", err)
+			}
+			if !loopback && !auth.Enabled() {
+				return usageErrorf("refusing to serve %s without authentication: pass --token or --basic-auth, or bind to 127.0.0.1", httpAddr)
+			}
+		}
+		var sshOpts sshserver.Options
+		if sshAddr != "" {
+			if sshOpts, err = sshServerOptions(); err != nil {
+				return err
+			}
+		}
+
+		organization := viper.GetString("org-id")
+		model := viper.GetString("model")
+		if model == "" {
+			model = "qwen-3-coder-480b"
+		}
+		refreshRate := viper.GetInt("refresh-rate")
+		if refreshRate < 1 {
+			refreshRate = 10
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireOrganization(client, organization); err != nil {
+			return err
+		}
+
+		server, mon, closeMonitor, err := newPollServer(cmd.Context(), client, organization, model, time.Duration(refreshRate)*time.Second)
+		if err != nil {
+			return err
+		}
+		defer closeMonitor()
+
+		// Listen on every address before serving, so a taken port fails the
+		// command instead of leaving half of it running
+		var httpListener, sshListener net.Listener
+		closeListeners := func() {
+			for _, l := range []net.Listener{httpListener, sshListener} {
+				if l != nil {
+					_ = l.Close()
+				}
+			}
+		}
+		if httpAddr != "" {
+			if httpListener, err = net.Listen("tcp", httpAddr); err != nil {
+				return fmt.Errorf("listening on %s: %w", httpAddr, err)
+			}
+		}
+		var sshServer *ssh.Server
+		if sshAddr != "" {
+			if sshListener, err = net.Listen("tcp", sshAddr); err != nil {
+				closeListeners()
+				return fmt.Errorf("listening on %s: %w", sshAddr, err)
+			}
+			sshServer, err = sshserver.New(sshOpts, func(sess ssh.Session) tea.Model {
+				return tui.NewDashboardModel(mon, refreshRate).
+					WithContext(sess.Context()).
+					WithSource(server).
+					ReadOnly()
+			})
+			if err != nil {
+				closeListeners()
+				return err
+			}
+		}
+
+		g, ctx := errgroup.WithContext(cmd.Context())
+		if httpListener != nil {
+			handler := web.NewHandler(server, mon.Budgets(), auth)
+			progressf(cmd, "Serving the web dashboard on http://%s\n", httpListener.Addr())
+			g.Go(func() error {
+				return handler.Serve(ctx, httpListener)
+			})
+		}
+		if sshServer != nil {
+			// Sessions render with the process-wide lipgloss renderer, which
+			// detects the host terminal (or none when detached), not the
+			// viewer's; 256 colors work in practically every SSH client
+			lipgloss.SetColorProfile(termenv.ANSI256)
+			lipgloss.SetHasDarkBackground(true)
+			progressf(cmd, "Serving the terminal dashboard on ssh://%s\n", sshListener.Addr())
+			g.Go(func() error {
+				return sshserver.Serve(ctx, sshServer, sshListener)
+			})
+		}
+
+		go server.Run(ctx)
+		return g.Wait()
+	},
+}
+
+// sshServerOptions reads the host key and authorized keys paths of the SSH
+// dashboard, defaulting to a key in the data directory and the
+// authorized_keys of the current user
+func sshServerOptions() (sshserver.Options, error) {
+	opts := sshserver.Options{
+		HostKeyPath:        viper.GetString("serve.host-key"),
+		AuthorizedKeysPath: viper.GetString("serve.authorized-keys"),
+	}
+	if opts.HostKeyPath == "" {
+		dataDir, err := config.GetDataDir()
+		if err != nil {
+			return opts, err
+		}
+		opts.HostKeyPath = filepath.Join(dataDir, "ssh_host_ed25519_key")
+	}
+	if opts.AuthorizedKeysPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return opts, err
+		}
+		opts.AuthorizedKeysPath = filepath.Join(home, ".ssh", "authorized_keys")
+	}
+	if _, err := os.Stat(opts.AuthorizedKeysPath); err != nil {
+		return opts, usageErrorf("--ssh needs an authorized_keys file: %v", err)
+	}
+	return opts, nil
+}
+
+// serveAuth reads the credentials protecting the served dashboard
+func serveAuth() (web.Auth, error) {
+	auth := web.Auth{Token: viper.GetString("serve.token")}
+	if basic := viper.GetString("serve.basic-auth"); basic != "" {
+		user, pass, ok := strings.Cut(basic, ":")
+		if !ok || user == "" || pass == "" {
+			return web.Auth{}, usageErrorf("invalid --basic-auth: expected user:password")
+		}
+		auth.Username, auth.Password = user, pass
+	}
+	return auth, nil
+}
+
+func init() {
+	ServeCmd.Flags().String("http", "", "Serve the web dashboard on this address, e.g. 127.0.0.1:7788")
+	ServeCmd.Flags().String("token", "", "Require this bearer token")
+	ServeCmd.Flags().String("basic-auth", "", "Require these basic auth credentials, as user:password")
+	ServeCmd.Flags().String("ssh", "", "Serve the terminal dashboard over SSH on this address, e.g. :2222")
+	ServeCmd.Flags().String("authorized-keys", "", "Public keys allowed over SSH (default: ~/.ssh/authorized_keys)")
+	ServeCmd.Flags().String("host-key", "", "SSH host key, generated when missing (default: ssh_host_ed25519_key in the data directory)")
+	_ = viper.BindPFlag("serve.http", ServeCmd.Flags().Lookup("http"))
+	_ = viper.BindPFlag("serve.token", ServeCmd.Flags().Lookup("token"))
+	_ = viper.BindPFlag("serve.basic-auth", ServeCmd.Flags().Lookup("basic-auth"))
+	_ = viper.BindPFlag("serve.ssh", ServeCmd.Flags().Lookup("ssh"))
+	_ = viper.BindPFlag("serve.authorized-keys", ServeCmd.Flags().Lookup("authorized-keys"))
+	_ = viper.BindPFlag("serve.host-key", ServeCmd.Flags().Lookup("host-key"))
+}
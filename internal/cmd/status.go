@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Status levels of a window, used as waybar classes
+const (
+	levelOK       = "ok"
+	levelWarning  = "warning"
+	levelCritical = "critical"
+	levelUnknown  = "unknown"
+)
+
+// statusTemplates are the --format presets rendered as text templates. The
+// nerdfont preset is the default one with Nerd Font icons.
+var statusTemplates = map[string]string{
+	"default": `{{.Icons.Token}} {{.TokensMinute.Pct}} tpm · {{.Icons.Request}} {{.RequestsDay.Pct}} rpd · {{.Icons.Time}} {{.TokensDay.ResetIn}}`,
+	"plain":   `tpm {{.TokensMinute.Pct}} | rpd {{.RequestsDay.Pct}} | tpd {{.TokensDay.Pct}} | reset {{.TokensDay.ResetIn}}`,
+	"tmux": `#[fg={{color .TokensMinute}}]{{.Icons.Token}} {{.TokensMinute.Pct}} tpm#[default] · ` +
+		`#[fg={{color .RequestsDay}}]{{.Icons.Request}} {{.RequestsDay.Pct}} rpd#[default] · {{.Icons.Time}} {{.TokensDay.ResetIn}}`,
+}
+
+// statusPresets lists every --format preset, including the JSON ones
+var statusPresets = []string{"default", "plain", "nerdfont", "tmux", "waybar", "i3blocks"}
+
+// statusColors maps levels to tmux color names and i3blocks colors
+var statusColors = map[string][2]string{
+	levelOK:       {"green", "#2ecc71"},
+	levelWarning:  {"yellow", "#f39c12"},
+	levelCritical: {"red", "#e74c3c"},
+	levelUnknown:  {"default", "#888888"},
+}
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print a one-line usage summary for status bars and prompts",
+	Long: `Print a single short line such as "🔑 62% tpm · 📡 14% rpd · ⏱️ 3h12m" for tmux,
+shell prompts, waybar or i3blocks.
+
+Metrics come from a running daemon or the metrics cache, so status bars can
+run it every few seconds: expired cache entries are served immediately and
+refreshed in the background, and the API is only called when nothing is
+cached yet.
+
+--format takes a preset or a Go template. Presets:
+
+  default   emoji icons, or Nerd Font icons with --icons nerdfont
+  plain     no icons
+  nerdfont  Nerd Font icons
+  tmux      colored with #[fg=…] by usage level
+  waybar    JSON with text, tooltip, class and percentage
+  i3blocks  JSON with full_text, short_text and color
+
+Templates see .RequestsMinute, .RequestsHour, .RequestsDay, .TokensMinute,
+.TokensHour and .TokensDay, each with .Used, .Limit, .Remaining, .Percent,
+.Pct ("62%", "∞" or "?"), .ResetIn ("3h12m") and .Level (ok, warning,
+critical or unknown), plus .Icons, .Level, .Percent, .Organization, .Model
+and .Age. {{color .TokensDay}} returns the tmux color of a window.`,
+	Example: `  cerebras-monitor status
+  cerebras-monitor status --format tmux         # status-right '#(cerebras-monitor status --format tmux)'
+  cerebras-monitor status --format waybar       # waybar custom module with "return-type": "json"
+  cerebras-monitor status --format '{{.TokensDay.Pct}} of the day'`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" {
+			format = viper.GetString("status.format")
+		}
+		if format == "" {
+			format = "default"
+		}
+		if format == "nerdfont" {
+			viper.Set("icons", "nerdfont")
+		}
+		var tmpl *template.Template
+		if !slices.Contains(statusPresets, format) {
+			if !strings.Contains(format, "{{") {
+				return usageErrorf("unknown --format %q: expected a template or one of %s", format, strings.Join(statusPresets, ", "))
+			}
+			t, err := parseStatusTemplate(format)
+			if err != nil {
+				return usageErrorf("invalid --format template: %v", err)
+			}
+			tmpl = t
+		}
+		warn, _ := cmd.Flags().GetFloat64("warn")
+		crit, _ := cmd.Flags().GetFloat64("crit")
+		if warn <= 0 || crit <= 0 || warn > crit {
+			return usageErrorf("invalid thresholds --warn %g --crit %g: expected 0 < warn <= crit", warn, crit)
+		}
+		rule, err := resets.LoadRule()
+		if err != nil {
+			return usageErrorf("invalid resets configuration: %v", err)
+		}
+
+		organization := viper.GetString("org-id")
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireOrganization(client, organization); err != nil {
+			return err
+		}
+		// A status bar prefers a slightly old line to blocking on the API
+		if !cmd.Flags().Changed("stale") {
+			_ = cmd.Flags().Set("stale", "true")
+		}
+		result, err := fetchMetricsCached(cmd, client, organization)
+		if err != nil {
+			return fmt.Errorf("fetching metrics: %w", err)
+		}
+
+		now := time.Now()
+		metrics := result.Entry.Metrics
+		resets.Fill(metrics, resets.NewEstimator(rule).Apply(metrics, now), now)
+		status := newStatusLine(metrics, organization, viper.GetString("model"), warn, crit)
+		status.Icons = config.GetIcons()
+		status.Age = result.Entry.Age(now).Round(time.Second)
+
+		if IsJSONOutput() {
+			return printJSON(cmd, status)
+		}
+		out := cmd.OutOrStdout()
+		if tmpl == nil {
+			line, err := status.Render(format)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, line)
+			return nil
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, status); err != nil {
+			return usageErrorf("invalid --format template: %v", err)
+		}
+		fmt.Fprintln(out, b.String())
+		return nil
+	},
+}
+
+// statusWindow is the state of one window in the status line
+type statusWindow struct {
+	Window       string         `json:"window"`
+	Used         int64          `json:"used"`
+	Limit        cerebras.Limit `json:"limit"`
+	Remaining    int64          `json:"remaining"`
+	Percent      float64        `json:"percent"`
+	ResetSeconds int64          `json:"reset_seconds"`
+	Level        string         `json:"level"`
+}
+
+// Pct renders the used share as "62%", "∞" for unlimited windows or "?"
+// when the limit is unknown
+func (w statusWindow) Pct() string {
+	switch {
+	case w.Limit.IsUnlimited():
+		return w.Limit.String()
+	case !w.Limit.IsKnown():
+		return "?"
+	default:
+		return fmt.Sprintf("%.0f%%", w.Percent)
+	}
+}
+
+// ResetIn renders the time until the window resets as "45s", "12m" or
+// "3h12m", or "?" when unknown
+func (w statusWindow) ResetIn() string {
+	switch s := w.ResetSeconds; {
+	case s <= 0:
+		return "?"
+	case s < 60:
+		return fmt.Sprintf("%ds", s)
+	case s < 3600:
+		return fmt.Sprintf("%dm", s/60)
+	default:
+		return fmt.Sprintf("%dh%dm", s/3600, (s%3600)/60)
+	}
+}
+
+// statusLine is the data behind the status line and its templates
+type statusLine struct {
+	Organization   string        `json:"organization"`
+	Model          string        `json:"model"`
+	Level          string        `json:"level"`
+	Percent        float64       `json:"percent"`
+	Age            time.Duration `json:"-"`
+	RequestsMinute statusWindow  `json:"requests_minute"`
+	RequestsHour   statusWindow  `json:"requests_hour"`
+	RequestsDay    statusWindow  `json:"requests_day"`
+	TokensMinute   statusWindow  `json:"tokens_minute"`
+	TokensHour     statusWindow  `json:"tokens_hour"`
+	TokensDay      statusWindow  `json:"tokens_day"`
+	Icons          config.Icons  `json:"-"`
+}
+
+// newStatusLine summarizes the metrics. Level and Percent are those of the
+// most used window with a numeric limit.
+func newStatusLine(metrics *cerebras.RateLimitInfo, organization, model string, warn, crit float64) statusLine {
+	s := statusLine{Organization: organization, Model: model, Level: levelUnknown}
+	for _, name := range cerebras.Windows {
+		w, _ := metrics.Window(name)
+		sw := statusWindow{Window: name, Used: w.Used, Limit: w.Limit, Remaining: w.Remaining, ResetSeconds: w.Reset, Level: levelUnknown}
+		if w.Limit.IsUnlimited() {
+			sw.Level = levelOK
+		}
+		if _, ok := w.Limit.Value(); ok {
+			sw.Percent = w.Percent()
+			switch {
+			case sw.Percent >= crit:
+				sw.Level = levelCritical
+			case sw.Percent >= warn:
+				sw.Level = levelWarning
+			default:
+				sw.Level = levelOK
+			}
+			if s.Level == levelUnknown || sw.Percent > s.Percent {
+				s.Level, s.Percent = sw.Level, sw.Percent
+			}
+		}
+		*s.window(name) = sw
+	}
+	return s
+}
+
+// window returns the field holding the named window
+func (s *statusLine) window(name string) *statusWindow {
+	switch name {
+	case cerebras.WindowRequestsMinute:
+		return &s.RequestsMinute
+	case cerebras.WindowRequestsHour:
+		return &s.RequestsHour
+	case cerebras.WindowRequestsDay:
+		return &s.RequestsDay
+	case cerebras.WindowTokensMinute:
+		return &s.TokensMinute
+	case cerebras.WindowTokensHour:
+		return &s.TokensHour
+	default:
+		return &s.TokensDay
+	}
+}
+
+// Render formats the status line with a preset
+func (s statusLine) Render(preset string) (string, error) {
+	switch preset {
+	case "waybar":
+		return s.renderJSON(struct {
+			Text       string `json:"text"`
+			Tooltip    string `json:"tooltip"`
+			Class      string `json:"class"`
+			Percentage int    `json:"percentage"`
+		}{s.text("default"), s.tooltip(), s.Level, int(s.Percent + 0.5)})
+	case "i3blocks":
+		return s.renderJSON(struct {
+			FullText  string `json:"full_text"`
+			ShortText string `json:"short_text"`
+			Color     string `json:"color"`
+		}{s.text("default"), fmt.Sprintf("%.0f%%", s.Percent), statusColors[s.Level][1]})
+	case "nerdfont":
+		return s.text("default"), nil
+	default:
+		return s.text(preset), nil
+	}
+}
+
+// text renders a template preset
+func (s statusLine) text(preset string) string {
+	var b strings.Builder
+	// The presets are tested and only read fields that always exist
+	_ = template.Must(parseStatusTemplate(statusTemplates[preset])).Execute(&b, s)
+	return b.String()
+}
+
+// tooltip lists every window with a known limit, one per line
+func (s statusLine) tooltip() string {
+	var lines []string
+	for _, name := range cerebras.Windows {
+		w := *s.window(name)
+		if !w.Limit.IsKnown() {
+			continue
+		}
+		line := fmt.Sprintf("%s: %d/%s (%s)", name, w.Used, w.Limit, w.Pct())
+		if w.ResetSeconds > 0 {
+			line += ", resets in " + w.ResetIn()
+		}
+		lines = append(lines, line)
+	}
+	if s.Age > 0 {
+		lines = append(lines, fmt.Sprintf("updated %s ago", s.Age))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s statusLine) renderJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseStatusTemplate parses a --format template
+func parseStatusTemplate(text string) (*template.Template, error) {
+	return template.New("status").Option("missingkey=error").Funcs(template.FuncMap{
+		"color": func(w statusWindow) string {
+			return statusColors[w.Level][0]
+		},
+	}).Parse(text)
+}
+
+func init() {
+	addCacheFlags(StatusCmd)
+	StatusCmd.Flags().StringP("format", "f", "", "Preset (default, plain, nerdfont, tmux, waybar, i3blocks) or Go template (default from status.format)")
+	StatusCmd.Flags().Float64("warn", 75, "Usage percentage of a window shown as warning")
+	StatusCmd.Flags().Float64("crit", 90, "Usage percentage of a window shown as critical")
+}
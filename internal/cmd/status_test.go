@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+)
+
+func testStatusLine() statusLine {
+	metrics := &cerebras.RateLimitInfo{
+		LimitTokensMinute: cerebras.LimitFromInt(100000),
+		UsageTokensMinute: 62000,
+		LimitRequestsDay:  cerebras.LimitFromInt(1000),
+		UsageRequestsDay:  140,
+		LimitTokensDay:    cerebras.LimitFromInt(1000000),
+		UsageTokensDay:    800000,
+		ResetTokensDay:    3*3600 + 12*60,
+	}
+	s := newStatusLine(metrics, "org", "model", 75, 90)
+	s.Icons = config.Icons{Token: "T", Request: "R", Time: "C"}
+	return s
+}
+
+func TestNewStatusLine(t *testing.T) {
+	s := testStatusLine()
+	if s.Level != levelWarning || s.Percent != 80 {
+		t.Errorf("Expected the tokens-day window to set the level, got %s at %.1f%%", s.Level, s.Percent)
+	}
+	if s.TokensMinute.Level != levelOK || s.TokensDay.Level != levelWarning || s.RequestsHour.Level != levelUnknown {
+		t.Errorf("Unexpected window levels %+v", s)
+	}
+
+	unlimited := newStatusLine(&cerebras.RateLimitInfo{LimitRequestsDay: cerebras.LimitUnlimited}, "", "", 75, 90)
+	if unlimited.Level != levelUnknown || unlimited.RequestsDay.Level != levelOK || unlimited.RequestsDay.Pct() != "∞" {
+		t.Errorf("Expected an unlimited window to be ok without setting the level, got %+v", unlimited)
+	}
+}
+
+func TestStatusWindowFormat(t *testing.T) {
+	tests := []struct {
+		window statusWindow
+		pct    string
+		reset  string
+	}{
+		{statusWindow{Limit: cerebras.LimitFromInt(100), Percent: 61.6, ResetSeconds: 45}, "62%", "45s"},
+		{statusWindow{Limit: cerebras.LimitUnlimited, ResetSeconds: 720}, "∞", "12m"},
+		{statusWindow{ResetSeconds: 3*3600 + 12*60}, "?", "3h12m"},
+		{statusWindow{}, "?", "?"},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Pct(); got != tt.pct {
+			t.Errorf("Pct() = %q, want %q", got, tt.pct)
+		}
+		if got := tt.window.ResetIn(); got != tt.reset {
+			t.Errorf("ResetIn() = %q, want %q", got, tt.reset)
+		}
+	}
+}
+
+func TestStatusRender(t *testing.T) {
+	s := testStatusLine()
+
+	texts := map[string]string{
+		"default": "T 62% tpm · R 14% rpd · C 3h12m",
+		"plain":   "tpm 62% | rpd 14% | tpd 80% | reset 3h12m",
+		"tmux":    "#[fg=green]T 62% tpm#[default] · #[fg=green]R 14% rpd#[default] · C 3h12m",
+	}
+	for preset, want := range texts {
+		got, err := s.Render(preset)
+		if err != nil || got != want {
+			t.Errorf("Render(%q) = %q, %v; want %q", preset, got, err, want)
+		}
+	}
+
+	line, err := s.Render("waybar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var waybar struct {
+		Text       string `json:"text"`
+		Tooltip    string `json:"tooltip"`
+		Class      string `json:"class"`
+		Percentage int    `json:"percentage"`
+	}
+	if err := json.Unmarshal([]byte(line), &waybar); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", line, err)
+	}
+	if waybar.Text != texts["default"] || waybar.Class != levelWarning || waybar.Percentage != 80 || waybar.Tooltip == "" {
+		t.Errorf("Unexpected waybar output %+v", waybar)
+	}
+
+	line, _ = s.Render("i3blocks")
+	var i3blocks map[string]string
+	if err := json.Unmarshal([]byte(line), &i3blocks); err != nil || i3blocks["color"] != statusColors[levelWarning][1] || i3blocks["short_text"] != "80%" {
+		t.Errorf("Unexpected i3blocks output %q", line)
+	}
+}
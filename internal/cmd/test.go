@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
-	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras/graphql"
 	"github.com/spf13/cobra"
 )
 
@@ -18,59 +16,37 @@ var TestCmd = &cobra.Command{
 var testExampleCmd = &cobra.Command{
 	Use:   "example",
 	Short: "Example test subcommand",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("This is a test command scaffolding")
 		fmt.Println("Add your test implementations here")
+		return nil
 	},
 }
 
 var testListOrganizationUsageQuotasCmd = &cobra.Command{
 	Use:   "quotas",
 	Short: "Test ListOrganizationUsageQuotas GraphQL query",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Create Cerebras client
-		client := cerebras.NewClient()
-		if !client.HasAuth() {
-			fmt.Println("Error: No authentication method configured. Please login first.")
-			return
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
 		}
 
-		// Create GraphQL client and validate authentication
-		graphqlClient := graphql.NewClient(client.SessionToken())
-
 		// Check if organization ID is provided
 		if len(args) < 1 {
-			fmt.Println("Error: Organization ID is required as an argument")
-			return
+			return fmt.Errorf("%w: organization ID is required as an argument", cerebras.ErrNoOrganization)
 		}
 		orgID := args[0]
 
-		// Make GraphQL request to list organization usage quotas
-		query := graphql.ListOrganizationUsageQuotasQuery
-		variables := map[string]interface{}{
-			"organizationId": orgID,
-		}
-		responseBody, err := graphqlClient.MakeRequestWithOperationName("ListOrganizationUsageQuotas", query, variables)
+		quotas, err := client.GraphQL().ListOrganizationUsageQuotas(cmd.Context(), orgID, client.Region())
 		if err != nil {
-			fmt.Printf("Error fetching organization usage quotas: %v\n", err)
-			return
-		}
-
-		// Parse the GraphQL response
-		var response struct {
-			Data struct {
-				ListOrganizationUsageQuotas []cerebras.UsageQuota `json:"ListOrganizationUsageQuotas"`
-			} `json:"data"`
-		}
-
-		if err := json.Unmarshal(responseBody, &response); err != nil {
-			fmt.Printf("Error parsing response: %v\n", err)
-			return
+			return fmt.Errorf("fetching organization usage quotas: %w", err)
 		}
 
 		// Display usage quotas in a formatted way
 		fmt.Printf("Usage Quotas for Organization %s:\n", orgID)
-		for _, quota := range response.Data.ListOrganizationUsageQuotas {
+		for _, quota := range quotas {
 			fmt.Printf("  Model ID: %s\n", quota.ModelId)
 			fmt.Printf("  Region ID: %s\n", quota.RegionId)
 			fmt.Printf("  Requests Per Minute: %s\n", quota.RequestsPerMinute)
@@ -83,56 +59,34 @@ var testListOrganizationUsageQuotasCmd = &cobra.Command{
 			fmt.Printf("  Max Completion Tokens: %s\n", quota.MaxCompletionTokens)
 			fmt.Printf("  ---\n")
 		}
+		return nil
 	},
 }
 
 var testListOrganizationUsageCmd = &cobra.Command{
 	Use:   "usage",
 	Short: "Test ListOrganizationUsage GraphQL query",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Create Cerebras client
-		client := cerebras.NewClient()
-		if !client.HasAuth() {
-			fmt.Println("Error: No authentication method configured. Please login first.")
-			return
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
 		}
 
-		// Create GraphQL client and validate authentication
-		graphqlClient := graphql.NewClient(client.SessionToken())
-
 		// Check if organization ID is provided
 		if len(args) < 1 {
-			fmt.Println("Error: Organization ID is required as an argument")
-			return
+			return fmt.Errorf("%w: organization ID is required as an argument", cerebras.ErrNoOrganization)
 		}
 		orgID := args[0]
 
-		// Make GraphQL request to list organization usage
-		query := graphql.ListOrganizationUsageQuery
-		variables := map[string]interface{}{
-			"organizationId": orgID,
-		}
-		responseBody, err := graphqlClient.MakeRequestWithOperationName("ListOrganizationUsage", query, variables)
+		usages, err := client.GraphQL().ListOrganizationUsage(cmd.Context(), orgID)
 		if err != nil {
-			fmt.Printf("Error fetching organization usage: %v\n", err)
-			return
-		}
-
-		// Parse the GraphQL response
-		var response struct {
-			Data struct {
-				ListOrganizationUsage []cerebras.OrganizationUsage `json:"ListOrganizationUsage"`
-			} `json:"data"`
-		}
-
-		if err := json.Unmarshal(responseBody, &response); err != nil {
-			fmt.Printf("Error parsing response: %v\n", err)
-			return
+			return fmt.Errorf("fetching organization usage: %w", err)
 		}
 
 		// Display usage information in a formatted way
 		fmt.Printf("Usage Information for Organization %s:\n", orgID)
-		for _, usage := range response.Data.ListOrganizationUsage {
+		for _, usage := range usages {
 			fmt.Printf("  Model ID: %s\n", usage.ModelId)
 			fmt.Printf("  Region ID: %s\n", usage.RegionId)
 			fmt.Printf("  RPM: %s\n", usage.RPM)
@@ -143,6 +97,7 @@ var testListOrganizationUsageCmd = &cobra.Command{
 			fmt.Printf("  TPD: %s\n", usage.TPD)
 			fmt.Printf("  ---\n")
 		}
+		return nil
 	},
 }
 
@@ -18,8 +18,8 @@ var UsageCmd = &cobra.Command{
 var getUsageCmd = &cobra.Command{
 	Use:   "get [organization]",
 	Short: "Get usage statistics for an organization",
-	Args:  cobra.MaximumNArgs(1), // Make organization optional
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:  usageArgs(cobra.MaximumNArgs(1)), // Make organization optional
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// If organization is not provided, use the one from configuration
 		organization := ""
 		if len(args) > 0 {
@@ -30,29 +30,27 @@ var getUsageCmd = &cobra.Command{
 		}
 
 		// Create Cerebras client
-		client := cerebras.NewClient()
-		if !client.HasAuth() {
-			fmt.Println("Error: No authentication method configured. Please login first.")
-			return
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
 		}
 
 		// For session token auth, organization is required
 		// Only require organization if we're using session token auth (not API key auth)
-		if client.SessionToken() != "" && client.APIKey() == "" && organization == "" {
-			fmt.Println("Error: organization must be provided either as an argument or via --org-id flag when using session token authentication")
-			return
+		if err := requireOrganization(client, organization); err != nil {
+			return err
 		}
 		if organization != "" {
-			fmt.Printf("Getting usage statistics for organization %s...\n", organization)
+			progressf(cmd, "Getting usage statistics for organization %s...\n", organization)
 		} else {
-			fmt.Println("Getting usage statistics...")
+			progressf(cmd, "Getting usage statistics...\n")
 		}
 
-		metrics, err := client.GetMetrics(organization)
+		result, err := fetchMetricsCached(cmd, client, organization)
 		if err != nil {
-			fmt.Printf("Error fetching metrics: %v\n", err)
-			return
+			return fmt.Errorf("fetching metrics: %w", err)
 		}
+		metrics := result.Entry.Metrics
 
 		// Convert metrics to UsageMetrics and Quota types
 		orgID := organization
@@ -67,17 +65,35 @@ var getUsageCmd = &cobra.Command{
 		}
 
 		usageMetrics := metrics.ToUsageMetrics(orgID, model)
+		if IsJSONOutput() {
+			return printJSON(cmd, struct {
+				Usage     *cerebras.UsageMetrics  `json:"usage"`
+				Metrics   *cerebras.RateLimitInfo `json:"metrics"`
+				FetchedAt time.Time               `json:"fetched_at"`
+				Cached    bool                    `json:"cached"`
+				Stale     bool                    `json:"stale"`
+			}{usageMetrics, metrics, result.Entry.FetchedAt, result.Cached, result.Stale})
+		}
+
 		// Display usage metrics
 		fmt.Printf("Usage Metrics:\n")
 		fmt.Printf("  Organization ID: %s\n", usageMetrics.OrganizationID)
 		fmt.Printf("  Model Name: %s\n", usageMetrics.ModelName)
-		fmt.Printf("  Tokens Used (last minute): %d/%d\n", usageMetrics.TokensUsed, usageMetrics.TokensLimit)
-		fmt.Printf("  Requests Used (last day): %d/%d\n", usageMetrics.RequestsUsed, usageMetrics.RequestsLimit)
+		fmt.Printf("  Tokens Used (last minute): %d/%s\n", usageMetrics.TokensUsed, usageMetrics.TokensLimit)
+		fmt.Printf("  Requests Used (last day): %d/%s\n", usageMetrics.RequestsUsed, usageMetrics.RequestsLimit)
+		if result.Cached {
+			age := time.Since(result.Entry.FetchedAt).Round(time.Second)
+			if result.Stale {
+				fmt.Printf("  Cached: %s ago (stale, refreshing in background)\n", age)
+			} else {
+				fmt.Printf("  Cached: %s ago\n", age)
+			}
+		}
 
 		// Display detailed quota information with user-friendly time formats
 		fmt.Printf("\nDetailed Quota Information:\n")
-		fmt.Printf("  Daily Request Limit: %d\n", metrics.LimitRequestsDay)
-		fmt.Printf("  Daily Requests Remaining: %d\n", metrics.RemainingRequestsDay)
+		fmt.Printf("  Daily Request Limit: %s\n", metrics.LimitRequestsDay)
+		fmt.Printf("  Daily Requests Remaining: %s\n", formatRemaining(metrics.LimitRequestsDay, metrics.RemainingRequestsDay))
 		if metrics.ResetRequestsDay > 0 {
 			resetDaily := time.Now().Add(time.Duration(metrics.ResetRequestsDay) * time.Second)
 			hoursUntilReset := int(metrics.ResetRequestsDay / 3600)
@@ -87,8 +103,8 @@ var getUsageCmd = &cobra.Command{
 			fmt.Printf("  Daily Request Reset: Unknown\n")
 		}
 
-		fmt.Printf("  Minute Token Limit: %d\n", metrics.LimitTokensMinute)
-		fmt.Printf("  Minute Tokens Remaining: %d\n", metrics.RemainingTokensMinute)
+		fmt.Printf("  Minute Token Limit: %s\n", metrics.LimitTokensMinute)
+		fmt.Printf("  Minute Tokens Remaining: %s\n", formatRemaining(metrics.LimitTokensMinute, metrics.RemainingTokensMinute))
 		if metrics.ResetTokensMinute > 0 {
 			resetMinute := time.Now().Add(time.Duration(metrics.ResetTokensMinute) * time.Second)
 			secondsUntilReset := int(metrics.ResetTokensMinute)
@@ -96,12 +112,22 @@ var getUsageCmd = &cobra.Command{
 		} else {
 			fmt.Printf("  Minute Token Reset: Unknown\n")
 		}
+		return nil
 	},
 }
+
+// formatRemaining prints the remaining count, or "∞" for unlimited windows
+func formatRemaining(limit cerebras.Limit, remaining int64) string {
+	if limit.IsUnlimited() {
+		return limit.String()
+	}
+	return fmt.Sprintf("%d", remaining)
+}
+
 var monitorUsageCmd = &cobra.Command{
 	Use:   "monitor",
 	Short: "Start real-time monitoring of usage",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get organization ID from configuration/viper
 		organization := viper.GetString("org-id")
 
@@ -112,10 +138,9 @@ var monitorUsageCmd = &cobra.Command{
 		refreshRate := viper.GetInt("refresh-rate")
 
 		// Create Cerebras client
-		client := cerebras.NewClient()
-		if !client.HasAuth() {
-			fmt.Println("Error: No authentication method configured. Please login first.")
-			return
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
 		}
 
 		// Debug output
@@ -123,9 +148,8 @@ var monitorUsageCmd = &cobra.Command{
 
 		// For session token auth, organization is required
 		// Only require organization if we're using session token auth (not API key auth)
-		if client.SessionToken() != "" && client.APIKey() == "" && organization == "" {
-			fmt.Println("Error: organization ID must be set via --org-id flag or configuration when using session token authentication")
-			return
+		if err := requireOrganization(client, organization); err != nil {
+			return err
 		}
 
 		if organization != "" {
@@ -137,10 +161,12 @@ var monitorUsageCmd = &cobra.Command{
 		// TODO: Implement real-time monitoring logic
 		// This would continuously make requests to the Cerebras GraphQL endpoint
 		// and display usage information with color-coded progress bars and tables
+		return nil
 	},
 }
 
 func init() {
+	addCacheFlags(getUsageCmd)
 	UsageCmd.AddCommand(getUsageCmd)
 	UsageCmd.AddCommand(monitorUsageCmd)
 }
@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+// DefaultOrganization is the organization ID recorded when metrics come from
+// an API key without an organization
+const DefaultOrganization = "default"
+
+// Collector persists fetched rate limit information as usage snapshots
+type Collector struct {
+	queries *db.Queries
+}
+
+// New creates a collector backed by the given queries
+func New(queries *db.Queries) *Collector {
+	return &Collector{queries: queries}
+}
+
+// Record stores a snapshot of the rate limit information for the
+// organization, model and region (cerebras.AllRegions when summed)
+func (c *Collector) Record(ctx context.Context, organization, model, region, source string, info *cerebras.RateLimitInfo) error {
+	if info == nil {
+		return nil
+	}
+	if organization == "" {
+		organization = DefaultOrganization
+	}
+
+	return c.queries.InsertUsageSnapshot(ctx, SnapshotParams(time.Now().UTC(), organization, model, region, source, info))
+}
+
+// SnapshotParams maps rate limit information onto a usage snapshot row.
+// Token columns track the minute window and request columns the day window.
+// Limit columns are NULL when unknown and -1 when unlimited. The region is
+// the one monitored rather than info.RegionId, so history stays in one series
+// whether or not the organization has several regions.
+func SnapshotParams(at time.Time, organization, model, region, source string, info *cerebras.RateLimitInfo) db.InsertUsageSnapshotParams {
+	tokens, _ := info.Window(cerebras.WindowTokensMinute)
+	requests, _ := info.Window(cerebras.WindowRequestsDay)
+	tokensDay, _ := info.Window(cerebras.WindowTokensDay)
+
+	complete := info.LimitTokensMinute.IsKnown() && info.LimitRequestsDay.IsKnown() &&
+		info.ResetTokensMinute > 0 && info.ResetRequestsDay > 0
+
+	return db.InsertUsageSnapshotParams{
+		Timestamp:            at.Truncate(time.Second),
+		OrganizationID:       organization,
+		ModelName:            model,
+		TokensUsed:           &tokens.Used,
+		TokensLimit:          info.LimitTokensMinute.DB(),
+		TokensRemaining:      optional(info.RemainingTokensMinute),
+		RequestsUsed:         &requests.Used,
+		RequestsLimit:        info.LimitRequestsDay.DB(),
+		RequestsRemaining:    optional(info.RemainingRequestsDay),
+		ResetRequestsSeconds: optional(info.ResetRequestsDay),
+		ResetTokensSeconds:   optional(info.ResetTokensMinute),
+		DataSource:           source,
+		IsComplete:           &complete,
+		TokensUsedDay:        &tokensDay.Used,
+		TokensLimitDay:       info.LimitTokensDay.DB(),
+		RegionID:             region,
+	}
+}
+
+// optional returns nil for zero values so unknown fields are stored as NULL
+func optional(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// SnapshotInfo reconstructs the rate limit information recorded in a
+// snapshot: the tokens-minute, requests-day and tokens-day windows. Other
+// windows are unknown. Remaining counts that were not recorded are derived
+// from the usage.
+func SnapshotInfo(s db.UsageSnapshot) *cerebras.RateLimitInfo {
+	info := &cerebras.RateLimitInfo{
+		LimitTokensMinute: cerebras.LimitFromDB(s.TokensLimit),
+		LimitRequestsDay:  cerebras.LimitFromDB(s.RequestsLimit),
+		LimitTokensDay:    cerebras.LimitFromDB(s.TokensLimitDay),
+		RegionId:          s.RegionID,
+	}
+	restore := func(window string, used, remaining, reset *int64) {
+		if used != nil {
+			*info.Field(cerebras.FieldUsage, window) = *used
+		}
+		if reset != nil {
+			*info.Field(cerebras.FieldReset, window) = *reset
+		}
+		switch limit, ok := info.LimitField(window).Value(); {
+		case remaining != nil:
+			*info.Field(cerebras.FieldRemaining, window) = *remaining
+		case ok && used != nil:
+			*info.Field(cerebras.FieldRemaining, window) = max(limit-*used, 0)
+		}
+	}
+	restore(cerebras.WindowTokensMinute, s.TokensUsed, s.TokensRemaining, s.ResetTokensSeconds)
+	restore(cerebras.WindowRequestsDay, s.RequestsUsed, s.RequestsRemaining, s.ResetRequestsSeconds)
+	restore(cerebras.WindowTokensDay, s.TokensUsedDay, nil, nil)
+	return info
+}
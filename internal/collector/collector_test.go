@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+func TestSnapshotInfo(t *testing.T) {
+	info := &cerebras.RateLimitInfo{
+		LimitTokensMinute:     cerebras.LimitFromInt(1000),
+		RemainingTokensMinute: 0,
+		ResetTokensMinute:     30,
+		LimitRequestsDay:      cerebras.LimitUnlimited,
+		UsageRequestsDay:      12,
+		LimitTokensDay:        cerebras.LimitFromInt(100000),
+		UsageTokensDay:        25000,
+		RemainingTokensDay:    75000,
+		RegionId:              "us-east",
+	}
+	params := SnapshotParams(time.Now(), "org", "model", "us-east", "session", info)
+	restored := SnapshotInfo(db.UsageSnapshot{
+		TokensUsed:           params.TokensUsed,
+		TokensLimit:          params.TokensLimit,
+		TokensRemaining:      params.TokensRemaining,
+		RequestsUsed:         params.RequestsUsed,
+		RequestsLimit:        params.RequestsLimit,
+		RequestsRemaining:    params.RequestsRemaining,
+		ResetRequestsSeconds: params.ResetRequestsSeconds,
+		ResetTokensSeconds:   params.ResetTokensSeconds,
+		TokensUsedDay:        params.TokensUsedDay,
+		TokensLimitDay:       params.TokensLimitDay,
+		RegionID:             params.RegionID,
+	})
+
+	for _, window := range []string{cerebras.WindowTokensMinute, cerebras.WindowRequestsDay, cerebras.WindowTokensDay} {
+		want, _ := info.Window(window)
+		got, _ := restored.Window(window)
+		if got != want {
+			t.Errorf("Expected %s to round-trip as %+v, got %+v", window, want, got)
+		}
+	}
+	if restored.RegionId != "us-east" {
+		t.Errorf("Expected the region to round-trip, got %q", restored.RegionId)
+	}
+	if restored.LimitTokensHour.IsKnown() {
+		t.Errorf("Expected windows without columns to stay unknown, got %v", restored.LimitTokensHour)
+	}
+}
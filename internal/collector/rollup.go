@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+// Rollup windows stored in usage_metrics
+const (
+	RollupMinute = "minute"
+	RollupHour   = "hour"
+	RollupDay    = "day"
+)
+
+// baselineBuckets is how many earlier buckets a bucket is compared with
+const baselineBuckets = 24
+
+// RollupPeriod returns the bucket length of a rollup window
+func RollupPeriod(window string) (time.Duration, error) {
+	switch window {
+	case RollupMinute:
+		return time.Minute, nil
+	case RollupHour:
+		return time.Hour, nil
+	case RollupDay:
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown rollup window %q: expected minute, hour or day", window)
+	}
+}
+
+// BucketStart returns the start of the UTC bucket of the window containing at
+func BucketStart(window string, at time.Time) (time.Time, error) {
+	period, err := RollupPeriod(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return at.UTC().Truncate(period), nil
+}
+
+// Rollup aggregates the snapshots of the region's bucket starting at start
+// into usage_metrics, replacing an earlier rollup of the same bucket
+func (c *Collector) Rollup(ctx context.Context, organization, model, region, window string, start time.Time) error {
+	period, err := RollupPeriod(window)
+	if err != nil {
+		return err
+	}
+	if organization == "" {
+		organization = DefaultOrganization
+	}
+	start = start.UTC()
+
+	snapshots, err := c.queries.GetUsageSnapshotsBetween(ctx, db.GetUsageSnapshotsBetweenParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		StartTime:      start,
+		EndTime:        start.Add(period),
+	})
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var base *db.UsageSnapshot
+	prev, err := c.queries.GetLatestUsageSnapshotBefore(ctx, db.GetLatestUsageSnapshotBeforeParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		Timestamp:      start,
+	})
+	switch {
+	case err == nil:
+		base = &prev
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	earlier, err := c.queries.GetUsageMetrics(ctx, db.GetUsageMetricsParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		TimeWindow:     window,
+		Limit:          baselineBuckets + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	params := Aggregate(window, start, period, base, snapshots, earlier)
+	params.OrganizationID = organization
+	params.ModelName = model
+	params.RegionID = region
+	return c.queries.InsertUsageMetrics(ctx, params)
+}
+
+// Aggregate computes the usage_metrics row of a bucket from its snapshots.
+// Totals are the growth of the daily counters across the bucket, counting
+// from base when given; burn rates are per minute. The bucket is compared
+// with the average of earlier rows of the same window, ignoring a row for
+// the bucket itself.
+func Aggregate(window string, start time.Time, period time.Duration, base *db.UsageSnapshot, snapshots []db.UsageSnapshot, earlier []db.UsageMetric) db.InsertUsageMetricsParams {
+	var baseTokens, baseRequests *int64
+	if base != nil {
+		baseTokens, baseRequests = base.TokensUsedDay, base.RequestsUsed
+	}
+	tokenCounters := make([]*int64, 0, len(snapshots))
+	requestCounters := make([]*int64, 0, len(snapshots))
+	var peak float64
+	for _, s := range snapshots {
+		tokenCounters = append(tokenCounters, s.TokensUsedDay)
+		requestCounters = append(requestCounters, s.RequestsUsed)
+		// tokens_used tracks the minute window, so it is a tokens per minute rate
+		if s.TokensUsed != nil && float64(*s.TokensUsed) > peak {
+			peak = float64(*s.TokensUsed)
+		}
+	}
+	tokens := counterGrowth(baseTokens, tokenCounters)
+	requests := counterGrowth(baseRequests, requestCounters)
+
+	minutes := period.Minutes()
+	avgTokens := float64(tokens) / minutes
+	avgRequests := float64(requests) / minutes
+	count := int64(len(snapshots))
+
+	params := db.InsertUsageMetricsParams{
+		Timestamp:           start.UTC(),
+		TimeWindow:          window,
+		TotalTokensUsed:     &tokens,
+		TotalRequestsUsed:   &requests,
+		AvgBurnRateTokens:   &avgTokens,
+		PeakBurnRateTokens:  &peak,
+		AvgBurnRateRequests: &avgRequests,
+		SnapshotCount:       &count,
+	}
+
+	var sum float64
+	var n int
+	for _, m := range earlier {
+		if m.Timestamp.Equal(params.Timestamp) || m.TotalTokensUsed == nil || n == baselineBuckets {
+			continue
+		}
+		sum += float64(*m.TotalTokensUsed)
+		n++
+	}
+	if n > 0 && sum > 0 {
+		average := sum / float64(n)
+		deviation := (float64(tokens) - average) / average * 100
+		above := float64(tokens) > average
+		params.DeviationPercentage = &deviation
+		params.IsAboveAverage = &above
+	}
+	return params
+}
+
+// counterGrowth sums the growth of a cumulative counter. A counter that
+// drops was reset, in which case the new value is the growth since the
+// reset. Without a base the first value is counted in full.
+func counterGrowth(base *int64, values []*int64) int64 {
+	var total int64
+	prev := base
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if prev != nil && *v >= *prev {
+			total += *v - *prev
+		} else {
+			total += *v
+		}
+		prev = v
+	}
+	return total
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+func counters(at time.Time, tokensMinute, tokensDay, requestsDay int64) db.UsageSnapshot {
+	return db.UsageSnapshot{Timestamp: at, TokensUsed: &tokensMinute, TokensUsedDay: &tokensDay, RequestsUsed: &requestsDay}
+}
+
+func TestAggregate(t *testing.T) {
+	start := time.Date(2025, 8, 4, 10, 0, 0, 0, time.UTC)
+	base := counters(start.Add(-time.Minute), 0, 1000, 10)
+	snapshots := []db.UsageSnapshot{
+		counters(start.Add(10*time.Minute), 400, 1400, 12),
+		counters(start.Add(20*time.Minute), 900, 2300, 15),
+		// Day reset within the bucket
+		counters(start.Add(30*time.Minute), 100, 100, 1),
+	}
+	previous := int64(500)
+	earlier := []db.UsageMetric{
+		{Timestamp: start.Add(-time.Hour), TotalTokensUsed: &previous},
+		{Timestamp: start.Add(-2 * time.Hour), TotalTokensUsed: &previous},
+	}
+
+	got := Aggregate(RollupHour, start, time.Hour, &base, snapshots, earlier)
+
+	if *got.TotalTokensUsed != 1400 {
+		t.Errorf("Expected 1400 tokens (400 + 900 + 100 after reset), got %d", *got.TotalTokensUsed)
+	}
+	if *got.TotalRequestsUsed != 6 {
+		t.Errorf("Expected 6 requests, got %d", *got.TotalRequestsUsed)
+	}
+	if math.Abs(*got.AvgBurnRateTokens-1400.0/60) > 1e-9 {
+		t.Errorf("Expected an average of 1400/60 tokens per minute, got %v", *got.AvgBurnRateTokens)
+	}
+	if *got.PeakBurnRateTokens != 900 {
+		t.Errorf("Expected a peak of 900 tokens per minute, got %v", *got.PeakBurnRateTokens)
+	}
+	if *got.SnapshotCount != 3 || got.TimeWindow != RollupHour || !got.Timestamp.Equal(start) {
+		t.Errorf("Unexpected bucket: %+v", got)
+	}
+	if got.IsAboveAverage == nil || !*got.IsAboveAverage || math.Abs(*got.DeviationPercentage-180) > 1e-9 {
+		t.Errorf("Expected 180%% above the average of 500, got %v, %v", got.IsAboveAverage, got.DeviationPercentage)
+	}
+}
+
+func TestAggregateWithoutBaseline(t *testing.T) {
+	start := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	// A row for the same bucket is an earlier rollup of it, not a baseline
+	own := int64(50)
+	got := Aggregate(RollupDay, start, 24*time.Hour, nil, []db.UsageSnapshot{counters(start, 10, 200, 3)},
+		[]db.UsageMetric{{Timestamp: start, TotalTokensUsed: &own}})
+
+	if *got.TotalTokensUsed != 200 || *got.TotalRequestsUsed != 3 {
+		t.Errorf("Expected the first counters in full without a base, got %d tokens and %d requests", *got.TotalTokensUsed, *got.TotalRequestsUsed)
+	}
+	if got.IsAboveAverage != nil || got.DeviationPercentage != nil {
+		t.Errorf("Expected no deviation without earlier buckets, got %v, %v", got.IsAboveAverage, got.DeviationPercentage)
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	at := time.Date(2025, 8, 4, 10, 42, 17, 0, time.FixedZone("UTC-3", -3*3600))
+	tests := map[string]time.Time{
+		RollupMinute: time.Date(2025, 8, 4, 13, 42, 0, 0, time.UTC),
+		RollupHour:   time.Date(2025, 8, 4, 13, 0, 0, 0, time.UTC),
+		RollupDay:    time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+	}
+	for window, want := range tests {
+		got, err := BucketStart(window, at)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("BucketStart(%s) = %v, %v; want %v", window, got, err, want)
+		}
+	}
+	if _, err := BucketStart("week", at); err == nil {
+		t.Error("Expected an unknown window to fail")
+	}
+}
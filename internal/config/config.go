@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -24,12 +25,36 @@ func GetConfigDir() string {
 	return filepath.Join(xdg.ConfigHome, AppName)
 }
 
+// GetDataDir returns the directory where the usage database and other state
+// shared between processes are stored
+func GetDataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".local", "share", "cerebras-code"), nil
+}
+
 // GetUserTimezone returns the user's local timezone
 func GetUserTimezone() string {
 	zone, _ := time.Now().Zone()
 	return zone
 }
 
+// GetLocation returns the configured timezone as a location, falling back to
+// the system local time when it is "auto" or cannot be resolved
+func GetLocation() *time.Location {
+	name := viper.GetString("timezone")
+	if name == "" || name == "auto" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 // SetupViper configures viper to use XDG config directory
 func SetupViper() {
 	// Ensure config directory exists
@@ -47,6 +72,34 @@ func SetupViper() {
 	viper.AddConfigPath(".")
 }
 
+// Save writes the configuration to the config file, creating settings.yaml
+// in the config directory when there is none yet
+func Save() error {
+	err := viper.WriteConfig()
+	if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+		return viper.WriteConfigAs(filepath.Join(GetConfigDir(), ConfigFile))
+	}
+	return err
+}
+
+// WatchKey is the configuration key of the organizations the dashboard
+// watches side by side
+const WatchKey = "organizations.watch"
+
+// WatchedOrganizations returns the IDs of the watched organizations, in
+// configured order without duplicates
+func WatchedOrganizations() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range viper.GetStringSlice(WatchKey) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // Icons represents the available icon sets
 type Icons struct {
 	Check        string
@@ -62,6 +115,7 @@ type Icons struct {
 	Time         string
 	Theme        string
 	Settings     string
+	Cost         string
 }
 
 // GetIcons returns the appropriate icon set based on configuration
@@ -82,6 +136,7 @@ func GetIcons() Icons {
 			Time:         NerdfontTime,
 			Theme:        NerdfontTheme,
 			Settings:     NerdfontSettings,
+			Cost:         NerdfontCost,
 		}
 	}
 
@@ -100,5 +155,6 @@ func GetIcons() Icons {
 		Time:         EmojiTime,
 		Theme:        EmojiTheme,
 		Settings:     EmojiSettings,
+		Cost:         EmojiCost,
 	}
 }
@@ -15,6 +15,7 @@ const (
 	EmojiTime         = "⏱️"
 	EmojiTheme        = "🎨"
 	EmojiSettings     = "⚙️"
+	EmojiCost         = "💰"
 
 	// Nerdfont icons
 	NerdfontCheck        = "" // nf-fa-check
@@ -30,4 +31,5 @@ const (
 	NerdfontTime         = "" // nf-fa-clock_o
 	NerdfontTheme        = "" // nf-fa-paint_brush
 	NerdfontSettings     = "" // nf-fa-cog
+	NerdfontCost         = "" // nf-fa-dollar
 )
@@ -0,0 +1,192 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+)
+
+// dialTimeout bounds the detection of a running daemon, so frontends fall
+// back to polling quickly when none is running
+const dialTimeout = 500 * time.Millisecond
+
+// Client queries a running daemon
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient returns a client for the daemon listening on the Unix socket
+func NewClient(socket string) *Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &Client{httpClient: &http.Client{Transport: transport}, baseURL: "http://daemon"}
+}
+
+// NewTCPClient returns a client for the daemon listening on a TCP address
+func NewTCPClient(addr string) *Client {
+	return &Client{httpClient: &http.Client{}, baseURL: "http://" + addr}
+}
+
+// Dial returns a client for the running daemon, or an ErrNotRunning error
+// when none answers on the socket
+func Dial(ctx context.Context) (*Client, error) {
+	socket, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(socket)
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	if _, err := client.Status(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRunning, err)
+	}
+	return client, nil
+}
+
+// Status returns the state of the daemon
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := c.get(ctx, "/v1/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Snapshot returns the latest snapshot, or ErrNoData before the first poll
+func (c *Client) Snapshot(ctx context.Context) (*monitor.Snapshot, error) {
+	var snap monitor.Snapshot
+	if err := c.get(ctx, "/v1/metrics", &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// SnapshotFor returns the latest snapshot when the daemon polls the
+// organization, model and region, and ErrNotServed otherwise
+func (c *Client) SnapshotFor(ctx context.Context, organization, model, region string) (*monitor.Snapshot, error) {
+	snap, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Organization != organization || snap.Model != model || snap.Region != region {
+		return nil, ErrNotServed
+	}
+	return snap, nil
+}
+
+// History returns the usage snapshots recorded within since
+func (c *Client) History(ctx context.Context, since time.Duration) ([]db.UsageSnapshot, error) {
+	var snapshots []db.UsageSnapshot
+	if err := c.get(ctx, "/v1/history?since="+url.QueryEscape(since.String()), &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// UsageMetrics returns the latest rollups of the window, oldest first
+func (c *Client) UsageMetrics(ctx context.Context, window string, limit int) ([]db.UsageMetric, error) {
+	var rows []db.UsageMetric
+	if err := c.get(ctx, fmt.Sprintf("/v1/usage-metrics?window=%s&limit=%d", url.QueryEscape(window), limit), &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Alerts returns the unacknowledged alerts
+func (c *Client) Alerts(ctx context.Context) ([]db.Alert, error) {
+	var alerts []db.Alert
+	if err := c.get(ctx, "/v1/alerts", &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// Events calls fn with every snapshot the daemon publishes, starting with
+// the latest one, until ctx is cancelled or the stream ends
+func (c *Client) Events(ctx context.Context, fn func(*monitor.Snapshot)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errorFromResponse(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	event := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "snapshot":
+			var snap monitor.Snapshot
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &snap); err != nil {
+				return fmt.Errorf("decoding event: %w", err)
+			}
+			fn(&snap)
+		case line == "":
+			event = ""
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// get decodes the JSON response of a GET request into out
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errorFromResponse(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// errorFromResponse decodes an error response of the API
+func errorFromResponse(resp *http.Response) error {
+	var body apiError
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+		return fmt.Errorf("daemon returned %s", resp.Status)
+	}
+	if message, ok := strings.CutPrefix(body.Error, ErrNoData.Error()); ok {
+		return fmt.Errorf("%w%s", ErrNoData, message)
+	}
+	return errors.New(body.Error)
+}
@@ -0,0 +1,115 @@
+// Package daemon runs the poll loop in a long-running process and serves its
+// results as JSON over a local socket, so the dashboard, CLI commands and
+// other frontends share one poller instead of each calling the API.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrNotRunning is returned when no daemon listens on the socket
+	ErrNotRunning = errors.New("daemon is not running")
+	// ErrAlreadyRunning is returned when another daemon owns the socket
+	ErrAlreadyRunning = errors.New("daemon is already running")
+	// ErrNotServed is returned when the daemon polls another organization,
+	// model or region
+	ErrNotServed = errors.New("daemon does not poll this organization, model and region")
+	// ErrNoData is returned before the daemon completed its first poll
+	ErrNoData = errors.New("daemon has not fetched metrics yet")
+)
+
+// Status describes the running daemon
+type Status struct {
+	PID          int       `json:"pid"`
+	StartedAt    time.Time `json:"started_at"`
+	Uptime       float64   `json:"uptime_seconds"`
+	Organization string    `json:"organization"`
+	Model        string    `json:"model"`
+	// Region is empty when the daemon sums every region
+	Region   string  `json:"region,omitempty"`
+	Interval float64 `json:"interval_seconds"`
+	// LastPoll is when the last poll finished, successful or not
+	LastPoll    *time.Time `json:"last_poll,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	// LastError is the error of the last poll, empty when it succeeded
+	LastError string `json:"last_error,omitempty"`
+	// Addresses lists where the API is served
+	Addresses []string `json:"addresses"`
+}
+
+// SocketPath returns the Unix socket of the API: daemon.socket when set,
+// otherwise daemon.sock in the data directory
+func SocketPath() (string, error) {
+	if path := viper.GetString("daemon.socket"); path != "" {
+		return path, nil
+	}
+	dataDir, err := config.GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "daemon.sock"), nil
+}
+
+// Listen opens the Unix socket at path. A socket left behind by a daemon that
+// exited without cleaning up is replaced; a live one is an ErrAlreadyRunning.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("%w on %s", ErrAlreadyRunning, path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Only the owner may query the daemon
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// ListenTCP listens on addr, which must be a loopback address such as
+// 127.0.0.1:9465 since the API has no authentication
+func ListenTCP(addr string) (net.Listener, error) {
+	loopback, err := IsLoopback(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !loopback {
+		return nil, fmt.Errorf("listen address %q is not a loopback address", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// IsLoopback reports whether the listen address addr only accepts local
+// connections. An empty host listens on every interface.
+func IsLoopback(addr string) (bool, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
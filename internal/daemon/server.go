@@ -0,0 +1,402 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/diskcache"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+)
+
+const (
+	// DefaultHistory is how far back /v1/history reaches without a since parameter
+	DefaultHistory = 24 * time.Hour
+	// DefaultUsageMetrics is how many rollups /v1/usage-metrics returns without a limit
+	DefaultUsageMetrics = 48
+)
+
+// Poller produces snapshots, see monitor.Monitor
+type Poller interface {
+	Poll(ctx context.Context) (*monitor.Snapshot, error)
+}
+
+// Server polls on an interval and serves the results
+type Server struct {
+	poller       Poller
+	organization string
+	model        string
+	region       string
+	interval     time.Duration
+	started      time.Time
+
+	// Optional, see WithHistory and WithCache
+	queries    *db.Queries
+	historyOrg string
+	cache      *diskcache.Store
+
+	mu          sync.Mutex
+	addresses   []string
+	latest      *monitor.Snapshot
+	lastPoll    time.Time
+	lastSuccess time.Time
+	lastErr     error
+	subscribers map[chan *monitor.Snapshot]struct{}
+}
+
+// NewServer creates a server polling the organization, model and region
+// (cerebras.AllRegions for every region) every interval
+func NewServer(poller Poller, organization, model, region string, interval time.Duration) *Server {
+	return &Server{
+		poller:       poller,
+		organization: organization,
+		model:        model,
+		region:       region,
+		interval:     interval,
+		started:      time.Now(),
+		subscribers:  make(map[chan *monitor.Snapshot]struct{}),
+	}
+}
+
+// WithHistory serves history and alerts of the organization from the usage
+// database
+func (s *Server) WithHistory(queries *db.Queries, organization string) *Server {
+	s.queries = queries
+	s.historyOrg = organization
+	return s
+}
+
+// WithCache stores every poll in the metrics cache, so one-shot commands
+// that do not query the daemon still get fresh metrics
+func (s *Server) WithCache(store *diskcache.Store) *Server {
+	s.cache = store
+	return s
+}
+
+// Run polls until ctx is cancelled, starting immediately
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll runs one poll and publishes the snapshot to subscribers
+func (s *Server) poll(ctx context.Context) {
+	snap, err := s.poller.Poll(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPoll = time.Now()
+	s.lastErr = err
+	if err != nil {
+		return
+	}
+	s.lastSuccess = s.lastPoll
+	s.latest = snap
+
+	if s.cache != nil {
+		key := diskcache.Key{Organization: snap.Organization, Model: snap.Model, Region: snap.Region, AuthMode: snap.Source}
+		_ = s.cache.Save(key, snap.Metrics, snap.FetchedAt)
+	}
+	for ch := range s.subscribers {
+		// Slow subscribers skip snapshots rather than block the poll loop
+		select {
+		case ch <- snap:
+		default:
+		}
+	}
+}
+
+// Latest returns the latest snapshot, or nil before the first successful poll
+func (s *Server) Latest() *monitor.Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// LastError returns the error of the last poll, or nil when it succeeded
+func (s *Server) LastError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// Subscribe returns a channel receiving every new snapshot and a function
+// ending the subscription. Snapshots are skipped while the receiver is busy.
+func (s *Server) Subscribe() (<-chan *monitor.Snapshot, func()) {
+	ch := make(chan *monitor.Snapshot, 1)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch, func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}
+}
+
+// Status returns the state of the daemon
+func (s *Server) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := Status{
+		PID:          os.Getpid(),
+		StartedAt:    s.started.UTC(),
+		Uptime:       time.Since(s.started).Seconds(),
+		Organization: s.organization,
+		Model:        s.model,
+		Region:       s.region,
+		Interval:     s.interval.Seconds(),
+		Addresses:    append([]string(nil), s.addresses...),
+	}
+	if !s.lastPoll.IsZero() {
+		at := s.lastPoll.UTC()
+		status.LastPoll = &at
+	}
+	if !s.lastSuccess.IsZero() {
+		at := s.lastSuccess.UTC()
+		status.LastSuccess = &at
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	return status
+}
+
+// Handler returns the HTTP API:
+//
+//	GET /v1/status   daemon state, see Status
+//	GET /v1/metrics  latest snapshot, as of its fetched_at
+//	GET /v1/history  usage snapshots, ?since=<duration> (default 24h)
+//	GET /v1/usage-metrics  rollups, ?window=minute|hour|day&limit=<n>
+//	GET /v1/alerts   unacknowledged alerts
+//	GET /v1/events   server-sent "snapshot" events, one per poll
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", s.handleStatus)
+	mux.HandleFunc("GET /v1/metrics", s.handleMetrics)
+	mux.HandleFunc("GET /v1/history", s.handleHistory)
+	mux.HandleFunc("GET /v1/usage-metrics", s.handleUsageMetrics)
+	mux.HandleFunc("GET /v1/alerts", s.handleAlerts)
+	mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return mux
+}
+
+// Serve serves the API on the listeners until ctx is cancelled
+func (s *Server) Serve(ctx context.Context, listeners ...net.Listener) error {
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		s.mu.Lock()
+		s.addresses = append(s.addresses, l.Addr().Network()+"://"+l.Addr().String())
+		s.mu.Unlock()
+		go func(l net.Listener) {
+			errs <- server.Serve(l)
+		}(l)
+	}
+
+	select {
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdown)
+	case err := <-errs:
+		_ = server.Close()
+		return err
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Status())
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	snap, lastErr := s.latest, s.lastErr
+	s.mu.Unlock()
+
+	if snap == nil {
+		msg := ErrNoData.Error()
+		if lastErr != nil {
+			msg = fmt.Sprintf("%s: %v", msg, lastErr)
+		}
+		writeError(w, http.StatusServiceUnavailable, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, snap)
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if s.queries == nil {
+		writeError(w, http.StatusServiceUnavailable, "history is not available")
+		return
+	}
+	since := DefaultHistory
+	if v := r.URL.Query().Get("since"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid since %q: expected a duration such as 1h", v))
+			return
+		}
+		since = d
+	}
+
+	now := time.Now().UTC()
+	snapshots, err := s.queries.GetUsageSnapshotsBetween(r.Context(), db.GetUsageSnapshotsBetweenParams{
+		OrganizationID: s.historyOrg,
+		ModelName:      s.model,
+		RegionID:       s.region,
+		StartTime:      now.Add(-since),
+		EndTime:        now.Add(time.Second),
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if snapshots == nil {
+		snapshots = []db.UsageSnapshot{}
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+}
+
+func (s *Server) handleUsageMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.queries == nil {
+		writeError(w, http.StatusServiceUnavailable, "history is not available")
+		return
+	}
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = collector.RollupHour
+	}
+	if _, err := collector.RollupPeriod(window); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit := int64(DefaultUsageMetrics)
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+
+	rows, err := s.queries.GetUsageMetrics(r.Context(), db.GetUsageMetricsParams{
+		OrganizationID: s.historyOrg,
+		ModelName:      s.model,
+		RegionID:       s.region,
+		TimeWindow:     window,
+		Limit:          limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Oldest first, as charts draw them
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	if rows == nil {
+		rows = []db.UsageMetric{}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if s.queries == nil {
+		writeError(w, http.StatusServiceUnavailable, "history is not available")
+		return
+	}
+	alerts, err := s.queries.GetUnacknowledgedAlerts(r.Context(), s.historyOrg)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if alerts == nil {
+		alerts = []db.Alert{}
+	}
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	ch, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+	latest := s.Latest()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(snap *monitor.Snapshot) bool {
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	// New subscribers start from the latest snapshot
+	if latest != nil && !send(latest) {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case snap := <-ch:
+			if !send(snap) {
+				return
+			}
+		}
+	}
+}
+
+// apiError is the body of error responses
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Error: message})
+}
@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/diskcache"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+)
+
+// fakePoller returns snapshots with increasing daily usage, or err when set
+type fakePoller struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (p *fakePoller) Poll(ctx context.Context) (*monitor.Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &monitor.Snapshot{
+		Organization: "org_1",
+		Model:        "qwen-3-coder-480b",
+		Source:       "session",
+		FetchedAt:    time.Now().UTC(),
+		Metrics:      &cerebras.RateLimitInfo{UsageRequestsDay: int64(p.calls), ResetRequestsDay: 3600},
+	}, nil
+}
+
+// startServer serves s on a Unix socket in a temporary directory
+func startServer(t *testing.T, s *Server) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "daemon.sock")
+	listener, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = s.Serve(ctx, listener)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return NewClient(socket)
+}
+
+func TestServerStatusAndMetrics(t *testing.T) {
+	poller := &fakePoller{}
+	store := diskcache.NewStore(t.TempDir())
+	server := NewServer(poller, "org_1", "qwen-3-coder-480b", "", time.Minute).WithCache(store)
+	client := startServer(t, server)
+	ctx := context.Background()
+
+	if _, err := client.Snapshot(ctx); !errors.Is(err, ErrNoData) {
+		t.Fatalf("Expected ErrNoData before the first poll, got %v", err)
+	}
+
+	server.poll(ctx)
+	snap, err := client.SnapshotFor(ctx, "org_1", "qwen-3-coder-480b", "")
+	if err != nil {
+		t.Fatalf("SnapshotFor: %v", err)
+	}
+	if snap.Metrics.UsageRequestsDay != 1 || snap.Metrics.ResetRequestsDay != 3600 {
+		t.Errorf("Unexpected snapshot metrics: %+v", snap.Metrics)
+	}
+	if _, err := client.SnapshotFor(ctx, "org_2", "qwen-3-coder-480b", ""); !errors.Is(err, ErrNotServed) {
+		t.Errorf("Expected ErrNotServed for another organization, got %v", err)
+	}
+	if _, err := client.SnapshotFor(ctx, "org_1", "qwen-3-coder-480b", "us"); !errors.Is(err, ErrNotServed) {
+		t.Errorf("Expected ErrNotServed for a single region of a summed poll, got %v", err)
+	}
+
+	key := diskcache.Key{Organization: "org_1", Model: "qwen-3-coder-480b", AuthMode: "session"}
+	if entry, err := store.Load(key); err != nil || entry.Metrics.UsageRequestsDay != 1 {
+		t.Errorf("Expected the poll in the cache, got %+v, %v", entry, err)
+	}
+
+	poller.err = errors.New("upstream down")
+	server.poll(ctx)
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.PID != os.Getpid() || status.LastSuccess == nil || status.LastPoll == nil || status.LastError != "upstream down" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if len(status.Addresses) != 1 {
+		t.Errorf("Expected one address, got %v", status.Addresses)
+	}
+
+	// The last good snapshot is still served after a failed poll
+	if snap, err := client.Snapshot(ctx); err != nil || snap.Metrics.UsageRequestsDay != 1 {
+		t.Errorf("Expected the last snapshot after a failed poll, got %+v, %v", snap, err)
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	poller := &fakePoller{}
+	server := NewServer(poller, "org_1", "qwen-3-coder-480b", "", time.Minute)
+	client := startServer(t, server)
+	server.poll(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	received := make(chan int64, 4)
+	go func() {
+		_ = client.Events(ctx, func(snap *monitor.Snapshot) {
+			received <- snap.Metrics.UsageRequestsDay
+		})
+	}()
+
+	// Subscribers start from the latest snapshot
+	select {
+	case got := <-received:
+		if got != 1 {
+			t.Fatalf("Expected the latest snapshot first, got usage %d", got)
+		}
+	case <-ctx.Done():
+		t.Fatal("No snapshot received")
+	}
+
+	server.poll(context.Background())
+	select {
+	case got := <-received:
+		if got != 2 {
+			t.Errorf("Expected the new snapshot, got usage %d", got)
+		}
+	case <-ctx.Done():
+		t.Fatal("No event received after a poll")
+	}
+}
+
+func TestServerHistoryUnavailable(t *testing.T) {
+	client := startServer(t, NewServer(&fakePoller{}, "org_1", "qwen-3-coder-480b", "", time.Minute))
+	if _, err := client.History(context.Background(), time.Hour); err == nil {
+		t.Error("Expected an error without history")
+	}
+}
+
+func TestListen(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "daemon.sock")
+
+	// A stale socket file is replaced
+	if err := os.WriteFile(socket, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen with a stale socket: %v", err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	if _, err := Listen(socket); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Expected ErrAlreadyRunning, got %v", err)
+	}
+}
+
+func TestListenTCPRequiresLoopback(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:0", "192.0.2.1:0", "example.com:80", "nonsense"} {
+		if l, err := ListenTCP(addr); err == nil {
+			_ = l.Close()
+			t.Errorf("Expected %s to be rejected", addr)
+		}
+	}
+	l, err := ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenTCP: %v", err)
+	}
+	_ = l.Close()
+}
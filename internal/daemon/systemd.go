@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UnitName is the systemd user unit running the daemon
+const UnitName = "cerebras-monitor.service"
+
+// Unit describes the systemd user unit of the daemon
+type Unit struct {
+	// Executable is the absolute path of the binary
+	Executable string
+	// Args follow the executable, starting with "daemon"
+	Args []string
+	// Environment is set for the daemon, e.g. credentials passed as
+	// CEREBRAS_* variables
+	Environment map[string]string
+}
+
+// UnitDir returns the directory of systemd user units:
+// $XDG_CONFIG_HOME/systemd/user, or ~/.config/systemd/user
+func UnitDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// UnitPath returns the path of the daemon's unit file
+func UnitPath() (string, error) {
+	dir, err := UnitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UnitName), nil
+}
+
+// Render returns the unit file. The daemon restarts after failures, except
+// for usage, authentication and missing organization errors (exit codes 2-4)
+// that a restart cannot fix.
+func (u Unit) Render() string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=Cerebras Code Monitor daemon\n")
+	b.WriteString("Documentation=https://github.com/nathabonfim59/cerebras-code-monitor\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+
+	exec := []string{systemdQuote(u.Executable, true)}
+	for _, arg := range u.Args {
+		exec = append(exec, systemdQuote(arg, true))
+	}
+	b.WriteString("ExecStart=" + strings.Join(exec, " ") + "\n")
+
+	keys := make([]string, 0, len(u.Environment))
+	for k := range u.Environment {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("Environment=" + systemdQuote(k+"="+u.Environment[k], false) + "\n")
+	}
+
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=10\n")
+	b.WriteString("RestartPreventExitStatus=2 3 4\n")
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// Install writes the unit file to path. It is only readable by the owner
+// since the environment may hold credentials.
+func (u Unit) Install(path string) error {
+	if !filepath.IsAbs(u.Executable) {
+		return fmt.Errorf("executable path %q is not absolute", u.Executable)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create unit directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(u.Render()), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Uninstall removes the unit file at path; a missing file is not an error
+func Uninstall(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// systemdQuote quotes a word for ExecStart= (exec) or Environment= lines,
+// escaping specifiers (%) and, on ExecStart=, variable expansion ($)
+func systemdQuote(s string, exec bool) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if exec {
+		s = strings.ReplaceAll(s, "$", "$$")
+	}
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnitInstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := UnitPath()
+	if err != nil {
+		t.Fatalf("UnitPath: %v", err)
+	}
+	if want := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "systemd", "user", UnitName); path != want {
+		t.Errorf("Expected unit path %s, got %s", want, path)
+	}
+
+	unit := Unit{
+		Executable:  "/opt/cerebras monitor/cerebras-monitor",
+		Args:        []string{"daemon", "--org-id=org_1"},
+		Environment: map[string]string{"CEREBRAS_API_KEY": "csk-1", "A_FIRST": "x"},
+	}
+	if err := unit.Install(path); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected the unit to be private, got %v", perm)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, line := range []string{
+		`ExecStart="/opt/cerebras monitor/cerebras-monitor" daemon --org-id=org_1`,
+		"Environment=A_FIRST=x\nEnvironment=CEREBRAS_API_KEY=csk-1\n",
+		"Restart=on-failure",
+		"RestartPreventExitStatus=2 3 4",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected the unit to contain %q:\n%s", line, content)
+		}
+	}
+
+	if err := Uninstall(path); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the unit to be removed, got %v", err)
+	}
+	if err := Uninstall(path); err != nil {
+		t.Errorf("Expected uninstalling twice to succeed, got %v", err)
+	}
+}
+
+func TestUnitInstallRequiresAbsolutePath(t *testing.T) {
+	unit := Unit{Executable: "cerebras-monitor", Args: []string{"daemon"}}
+	if err := unit.Install(filepath.Join(t.TempDir(), UnitName)); err == nil {
+		t.Error("Expected a relative executable to be rejected")
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		exec bool
+		want string
+	}{
+		{"plain", true, "plain"},
+		{"", true, `""`},
+		{"with space", true, `"with space"`},
+		{`say "hi"`, true, `"say \"hi\""`},
+		{"100%", true, "100%%"},
+		{"$HOME", true, "$$HOME"},
+		{"KEY=$HOME", false, "KEY=$HOME"},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.in, tt.exec); got != tt.want {
+			t.Errorf("systemdQuote(%q, %v) = %s, want %s", tt.in, tt.exec, got, tt.want)
+		}
+	}
+}
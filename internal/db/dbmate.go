@@ -1,7 +1,9 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -10,18 +12,26 @@ import (
 	_ "github.com/amacneil/dbmate/v2/pkg/driver/sqlite"
 	"github.com/nathabonfim59/cerebras-code-monitor/buildtags"
 	dbfiles "github.com/nathabonfim59/cerebras-code-monitor/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
 )
 
+// DatabasePath returns the location of the usage statistics database
+func DatabasePath() (string, error) {
+	dataDir, err := config.GetDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dataDir, "database.db"), nil
+}
+
 // GetDBMate creates and configures a dbmate instance
 func GetDBMate() (*dbmate.DB, error) {
-	// Use XDG data directory
-	homeDir, err := os.UserHomeDir()
+	dbPath, err := DatabasePath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, err
 	}
 
-	dbPath := filepath.Join(homeDir, ".local", "share", "cerebras-code", "database.db")
-
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -91,3 +101,31 @@ func MigrationStatus() (int, error) {
 
 	return status, nil
 }
+
+// Open applies pending migrations quietly and returns a connection to the
+// usage statistics database
+func Open() (*sql.DB, error) {
+	dbm, err := GetDBMate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep stdout clean for the TUI and skip the sqlite3 CLI schema dump
+	dbm.Log = io.Discard
+	dbm.AutoDumpSchema = false
+	if err := dbm.CreateAndMigrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	dbPath, err := DatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return conn, nil
+}
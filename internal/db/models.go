@@ -21,6 +21,9 @@ type Alert struct {
 	Message        *string    `json:"message"`
 	Acknowledged   *bool      `json:"acknowledged"`
 	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	Status         string     `json:"status"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
 }
 
 type BaselineAverage struct {
@@ -38,6 +41,16 @@ type BaselineAverage struct {
 	PeriodDays           *int64    `json:"period_days"`
 }
 
+type RequestUsage struct {
+	ID               int64     `json:"id"`
+	Timestamp        time.Time `json:"timestamp"`
+	OrganizationID   string    `json:"organization_id"`
+	ModelName        string    `json:"model_name"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	Source           string    `json:"source"`
+}
+
 type SchemaMigration struct {
 	Version string `json:"version"`
 }
@@ -56,6 +69,7 @@ type UsageMetric struct {
 	IsAboveAverage      *bool     `json:"is_above_average"`
 	DeviationPercentage *float64  `json:"deviation_percentage"`
 	SnapshotCount       *int64    `json:"snapshot_count"`
+	RegionID            string    `json:"region_id"`
 }
 
 type UsageMetricsArchive struct {
@@ -89,4 +103,7 @@ type UsageSnapshot struct {
 	ResetTokensSeconds   *int64    `json:"reset_tokens_seconds"`
 	DataSource           string    `json:"data_source"`
 	IsComplete           *bool     `json:"is_complete"`
+	TokensUsedDay        *int64    `json:"tokens_used_day"`
+	TokensLimitDay       *int64    `json:"tokens_limit_day"`
+	RegionID             string    `json:"region_id"`
 }
@@ -10,6 +10,122 @@ import (
 	"time"
 )
 
+const createAlert = `-- name: CreateAlert :one
+INSERT INTO alerts (
+    timestamp,
+    organization_id,
+    model_name,
+    alert_type,
+    severity,
+    metric_name,
+    metric_value,
+    threshold_value,
+    message,
+    status,
+    snoozed_until
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING id
+`
+
+type CreateAlertParams struct {
+	Timestamp      time.Time  `json:"timestamp"`
+	OrganizationID string     `json:"organization_id"`
+	ModelName      string     `json:"model_name"`
+	AlertType      string     `json:"alert_type"`
+	Severity       string     `json:"severity"`
+	MetricName     string     `json:"metric_name"`
+	MetricValue    float64    `json:"metric_value"`
+	ThresholdValue float64    `json:"threshold_value"`
+	Message        *string    `json:"message"`
+	Status         string     `json:"status"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
+}
+
+func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createAlert,
+		arg.Timestamp,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.AlertType,
+		arg.Severity,
+		arg.MetricName,
+		arg.MetricValue,
+		arg.ThresholdValue,
+		arg.Message,
+		arg.Status,
+		arg.SnoozedUntil,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getAlertsResolvedSince = `-- name: GetAlertsResolvedSince :many
+SELECT id, timestamp, organization_id, model_name, alert_type, severity, metric_name, metric_value, threshold_value, message, acknowledged, acknowledged_at, status, resolved_at, snoozed_until FROM alerts
+WHERE organization_id = ?
+AND model_name = ?
+AND status = 'resolved'
+AND resolved_at >= ?
+ORDER BY resolved_at ASC
+`
+
+type GetAlertsResolvedSinceParams struct {
+	OrganizationID string     `json:"organization_id"`
+	ModelName      string     `json:"model_name"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+}
+
+func (q *Queries) GetAlertsResolvedSince(ctx context.Context, arg GetAlertsResolvedSinceParams) ([]Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getAlertsResolvedSince, arg.OrganizationID, arg.ModelName, arg.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.OrganizationID,
+			&i.ModelName,
+			&i.AlertType,
+			&i.Severity,
+			&i.MetricName,
+			&i.MetricValue,
+			&i.ThresholdValue,
+			&i.Message,
+			&i.Acknowledged,
+			&i.AcknowledgedAt,
+			&i.Status,
+			&i.ResolvedAt,
+			&i.SnoozedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBaselineAverage = `-- name: GetBaselineAverage :one
 SELECT id, organization_id, model_name, time_window, avg_tokens_per_period, avg_requests_per_period, avg_burn_rate_tokens, std_deviation_tokens, std_deviation_requests, sample_count, last_updated, period_days FROM baseline_averages
 WHERE organization_id = ?
@@ -45,8 +161,8 @@ func (q *Queries) GetBaselineAverage(ctx context.Context, arg GetBaselineAverage
 }
 
 const getLatestUsageSnapshot = `-- name: GetLatestUsageSnapshot :one
-SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete FROM usage_snapshots
-WHERE organization_id = ? AND model_name = ?
+SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete, tokens_used_day, tokens_limit_day, region_id FROM usage_snapshots
+WHERE organization_id = ? AND model_name = ? AND region_id = ?
 ORDER BY timestamp DESC
 LIMIT 1
 `
@@ -54,10 +170,58 @@ LIMIT 1
 type GetLatestUsageSnapshotParams struct {
 	OrganizationID string `json:"organization_id"`
 	ModelName      string `json:"model_name"`
+	RegionID       string `json:"region_id"`
 }
 
 func (q *Queries) GetLatestUsageSnapshot(ctx context.Context, arg GetLatestUsageSnapshotParams) (UsageSnapshot, error) {
-	row := q.db.QueryRowContext(ctx, getLatestUsageSnapshot, arg.OrganizationID, arg.ModelName)
+	row := q.db.QueryRowContext(ctx, getLatestUsageSnapshot, arg.OrganizationID, arg.ModelName, arg.RegionID)
+	var i UsageSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.Timestamp,
+		&i.OrganizationID,
+		&i.ModelName,
+		&i.TokensUsed,
+		&i.TokensLimit,
+		&i.TokensRemaining,
+		&i.RequestsUsed,
+		&i.RequestsLimit,
+		&i.RequestsRemaining,
+		&i.ResetRequestsSeconds,
+		&i.ResetTokensSeconds,
+		&i.DataSource,
+		&i.IsComplete,
+		&i.TokensUsedDay,
+		&i.TokensLimitDay,
+		&i.RegionID,
+	)
+	return i, err
+}
+
+const getLatestUsageSnapshotBefore = `-- name: GetLatestUsageSnapshotBefore :one
+SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete, tokens_used_day, tokens_limit_day, region_id FROM usage_snapshots
+WHERE organization_id = ?
+AND model_name = ?
+AND region_id = ?
+AND timestamp < ?
+ORDER BY timestamp DESC
+LIMIT 1
+`
+
+type GetLatestUsageSnapshotBeforeParams struct {
+	OrganizationID string    `json:"organization_id"`
+	ModelName      string    `json:"model_name"`
+	RegionID       string    `json:"region_id"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+func (q *Queries) GetLatestUsageSnapshotBefore(ctx context.Context, arg GetLatestUsageSnapshotBeforeParams) (UsageSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getLatestUsageSnapshotBefore,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.RegionID,
+		arg.Timestamp,
+	)
 	var i UsageSnapshot
 	err := row.Scan(
 		&i.ID,
@@ -74,14 +238,173 @@ func (q *Queries) GetLatestUsageSnapshot(ctx context.Context, arg GetLatestUsage
 		&i.ResetTokensSeconds,
 		&i.DataSource,
 		&i.IsComplete,
+		&i.TokensUsedDay,
+		&i.TokensLimitDay,
+		&i.RegionID,
 	)
 	return i, err
 }
 
+const getOpenAlerts = `-- name: GetOpenAlerts :many
+SELECT id, timestamp, organization_id, model_name, alert_type, severity, metric_name, metric_value, threshold_value, message, acknowledged, acknowledged_at, status, resolved_at, snoozed_until FROM alerts
+WHERE organization_id = ?
+AND model_name = ?
+AND status != 'resolved'
+ORDER BY timestamp ASC
+`
+
+type GetOpenAlertsParams struct {
+	OrganizationID string `json:"organization_id"`
+	ModelName      string `json:"model_name"`
+}
+
+func (q *Queries) GetOpenAlerts(ctx context.Context, arg GetOpenAlertsParams) ([]Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getOpenAlerts, arg.OrganizationID, arg.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.OrganizationID,
+			&i.ModelName,
+			&i.AlertType,
+			&i.Severity,
+			&i.MetricName,
+			&i.MetricValue,
+			&i.ThresholdValue,
+			&i.Message,
+			&i.Acknowledged,
+			&i.AcknowledgedAt,
+			&i.Status,
+			&i.ResolvedAt,
+			&i.SnoozedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentAlerts = `-- name: GetRecentAlerts :many
+SELECT id, timestamp, organization_id, model_name, alert_type, severity, metric_name, metric_value, threshold_value, message, acknowledged, acknowledged_at, status, resolved_at, snoozed_until FROM alerts
+WHERE organization_id = ?
+ORDER BY timestamp DESC
+LIMIT ?
+`
+
+type GetRecentAlertsParams struct {
+	OrganizationID string `json:"organization_id"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) GetRecentAlerts(ctx context.Context, arg GetRecentAlertsParams) ([]Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentAlerts, arg.OrganizationID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.OrganizationID,
+			&i.ModelName,
+			&i.AlertType,
+			&i.Severity,
+			&i.MetricName,
+			&i.MetricValue,
+			&i.ThresholdValue,
+			&i.Message,
+			&i.Acknowledged,
+			&i.AcknowledgedAt,
+			&i.Status,
+			&i.ResolvedAt,
+			&i.SnoozedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRequestUsageBetween = `-- name: GetRequestUsageBetween :many
+SELECT id, timestamp, organization_id, model_name, prompt_tokens, completion_tokens, source FROM request_usage
+WHERE organization_id = ?
+AND model_name = ?
+AND timestamp >= ?
+AND timestamp < ?
+ORDER BY timestamp ASC
+`
+
+type GetRequestUsageBetweenParams struct {
+	OrganizationID string    `json:"organization_id"`
+	ModelName      string    `json:"model_name"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+}
+
+func (q *Queries) GetRequestUsageBetween(ctx context.Context, arg GetRequestUsageBetweenParams) ([]RequestUsage, error) {
+	rows, err := q.db.QueryContext(ctx, getRequestUsageBetween,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RequestUsage
+	for rows.Next() {
+		var i RequestUsage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.OrganizationID,
+			&i.ModelName,
+			&i.PromptTokens,
+			&i.CompletionTokens,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUnacknowledgedAlerts = `-- name: GetUnacknowledgedAlerts :many
-SELECT id, timestamp, organization_id, model_name, alert_type, severity, metric_name, metric_value, threshold_value, message, acknowledged, acknowledged_at FROM alerts
+SELECT id, timestamp, organization_id, model_name, alert_type, severity, metric_name, metric_value, threshold_value, message, acknowledged, acknowledged_at, status, resolved_at, snoozed_until FROM alerts
 WHERE organization_id = ?
 AND acknowledged = 0
+AND status != 'resolved'
 ORDER BY timestamp DESC
 `
 
@@ -107,6 +430,9 @@ func (q *Queries) GetUnacknowledgedAlerts(ctx context.Context, organizationID st
 			&i.Message,
 			&i.Acknowledged,
 			&i.AcknowledgedAt,
+			&i.Status,
+			&i.ResolvedAt,
+			&i.SnoozedUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -122,9 +448,10 @@ func (q *Queries) GetUnacknowledgedAlerts(ctx context.Context, organizationID st
 }
 
 const getUsageMetrics = `-- name: GetUsageMetrics :many
-SELECT id, timestamp, organization_id, model_name, time_window, total_tokens_used, total_requests_used, avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests, is_above_average, deviation_percentage, snapshot_count FROM usage_metrics
+SELECT id, timestamp, organization_id, model_name, time_window, total_tokens_used, total_requests_used, avg_burn_rate_tokens, peak_burn_rate_tokens, avg_burn_rate_requests, is_above_average, deviation_percentage, snapshot_count, region_id FROM usage_metrics
 WHERE organization_id = ?
 AND model_name = ?
+AND region_id = ?
 AND time_window = ?
 ORDER BY timestamp DESC
 LIMIT ?
@@ -133,6 +460,7 @@ LIMIT ?
 type GetUsageMetricsParams struct {
 	OrganizationID string `json:"organization_id"`
 	ModelName      string `json:"model_name"`
+	RegionID       string `json:"region_id"`
 	TimeWindow     string `json:"time_window"`
 	Limit          int64  `json:"limit"`
 }
@@ -141,6 +469,7 @@ func (q *Queries) GetUsageMetrics(ctx context.Context, arg GetUsageMetricsParams
 	rows, err := q.db.QueryContext(ctx, getUsageMetrics,
 		arg.OrganizationID,
 		arg.ModelName,
+		arg.RegionID,
 		arg.TimeWindow,
 		arg.Limit,
 	)
@@ -165,6 +494,72 @@ func (q *Queries) GetUsageMetrics(ctx context.Context, arg GetUsageMetricsParams
 			&i.IsAboveAverage,
 			&i.DeviationPercentage,
 			&i.SnapshotCount,
+			&i.RegionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsageSnapshotsBetween = `-- name: GetUsageSnapshotsBetween :many
+SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete, tokens_used_day, tokens_limit_day, region_id FROM usage_snapshots
+WHERE organization_id = ?
+AND model_name = ?
+AND region_id = ?
+AND timestamp >= ?
+AND timestamp < ?
+ORDER BY timestamp ASC
+`
+
+type GetUsageSnapshotsBetweenParams struct {
+	OrganizationID string    `json:"organization_id"`
+	ModelName      string    `json:"model_name"`
+	RegionID       string    `json:"region_id"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+}
+
+func (q *Queries) GetUsageSnapshotsBetween(ctx context.Context, arg GetUsageSnapshotsBetweenParams) ([]UsageSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getUsageSnapshotsBetween,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.RegionID,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsageSnapshot
+	for rows.Next() {
+		var i UsageSnapshot
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.OrganizationID,
+			&i.ModelName,
+			&i.TokensUsed,
+			&i.TokensLimit,
+			&i.TokensRemaining,
+			&i.RequestsUsed,
+			&i.RequestsLimit,
+			&i.RequestsRemaining,
+			&i.ResetRequestsSeconds,
+			&i.ResetTokensSeconds,
+			&i.DataSource,
+			&i.IsComplete,
+			&i.TokensUsedDay,
+			&i.TokensLimitDay,
+			&i.RegionID,
 		); err != nil {
 			return nil, err
 		}
@@ -180,10 +575,11 @@ func (q *Queries) GetUsageMetrics(ctx context.Context, arg GetUsageMetricsParams
 }
 
 const getUsageSnapshotsInTimeWindow = `-- name: GetUsageSnapshotsInTimeWindow :many
-SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete FROM usage_snapshots
+SELECT id, timestamp, organization_id, model_name, tokens_used, tokens_limit, tokens_remaining, requests_used, requests_limit, requests_remaining, reset_requests_seconds, reset_tokens_seconds, data_source, is_complete, tokens_used_day, tokens_limit_day, region_id FROM usage_snapshots
 WHERE timestamp > datetime('now', ?)
 AND organization_id = ?
 AND model_name = ?
+AND region_id = ?
 ORDER BY timestamp ASC
 `
 
@@ -191,10 +587,16 @@ type GetUsageSnapshotsInTimeWindowParams struct {
 	Datetime       interface{} `json:"datetime"`
 	OrganizationID string      `json:"organization_id"`
 	ModelName      string      `json:"model_name"`
+	RegionID       string      `json:"region_id"`
 }
 
 func (q *Queries) GetUsageSnapshotsInTimeWindow(ctx context.Context, arg GetUsageSnapshotsInTimeWindowParams) ([]UsageSnapshot, error) {
-	rows, err := q.db.QueryContext(ctx, getUsageSnapshotsInTimeWindow, arg.Datetime, arg.OrganizationID, arg.ModelName)
+	rows, err := q.db.QueryContext(ctx, getUsageSnapshotsInTimeWindow,
+		arg.Datetime,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.RegionID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -217,6 +619,9 @@ func (q *Queries) GetUsageSnapshotsInTimeWindow(ctx context.Context, arg GetUsag
 			&i.ResetTokensSeconds,
 			&i.DataSource,
 			&i.IsComplete,
+			&i.TokensUsedDay,
+			&i.TokensLimitDay,
+			&i.RegionID,
 		); err != nil {
 			return nil, err
 		}
@@ -346,8 +751,47 @@ func (q *Queries) InsertBaselineAverage(ctx context.Context, arg InsertBaselineA
 	return err
 }
 
+const insertRequestUsage = `-- name: InsertRequestUsage :exec
+INSERT INTO request_usage (
+    timestamp,
+    organization_id,
+    model_name,
+    prompt_tokens,
+    completion_tokens,
+    source
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+`
+
+type InsertRequestUsageParams struct {
+	Timestamp        time.Time `json:"timestamp"`
+	OrganizationID   string    `json:"organization_id"`
+	ModelName        string    `json:"model_name"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	Source           string    `json:"source"`
+}
+
+func (q *Queries) InsertRequestUsage(ctx context.Context, arg InsertRequestUsageParams) error {
+	_, err := q.db.ExecContext(ctx, insertRequestUsage,
+		arg.Timestamp,
+		arg.OrganizationID,
+		arg.ModelName,
+		arg.PromptTokens,
+		arg.CompletionTokens,
+		arg.Source,
+	)
+	return err
+}
+
 const insertUsageMetrics = `-- name: InsertUsageMetrics :exec
-INSERT INTO usage_metrics (
+INSERT OR REPLACE INTO usage_metrics (
     timestamp,
     organization_id,
     model_name,
@@ -359,7 +803,8 @@ INSERT INTO usage_metrics (
     avg_burn_rate_requests,
     is_above_average,
     deviation_percentage,
-    snapshot_count
+    snapshot_count,
+    region_id
 ) VALUES (
     ?,
     ?,
@@ -372,6 +817,7 @@ INSERT INTO usage_metrics (
     ?,
     ?,
     ?,
+    ?,
     ?
 )
 `
@@ -389,6 +835,7 @@ type InsertUsageMetricsParams struct {
 	IsAboveAverage      *bool     `json:"is_above_average"`
 	DeviationPercentage *float64  `json:"deviation_percentage"`
 	SnapshotCount       *int64    `json:"snapshot_count"`
+	RegionID            string    `json:"region_id"`
 }
 
 func (q *Queries) InsertUsageMetrics(ctx context.Context, arg InsertUsageMetricsParams) error {
@@ -405,6 +852,7 @@ func (q *Queries) InsertUsageMetrics(ctx context.Context, arg InsertUsageMetrics
 		arg.IsAboveAverage,
 		arg.DeviationPercentage,
 		arg.SnapshotCount,
+		arg.RegionID,
 	)
 	return err
 }
@@ -423,7 +871,10 @@ INSERT INTO usage_snapshots (
     reset_requests_seconds,
     reset_tokens_seconds,
     data_source,
-    is_complete
+    is_complete,
+    tokens_used_day,
+    tokens_limit_day,
+    region_id
 ) VALUES (
     ?,
     ?,
@@ -437,6 +888,9 @@ INSERT INTO usage_snapshots (
     ?,
     ?,
     ?,
+    ?,
+    ?,
+    ?,
     ?
 )
 `
@@ -455,6 +909,9 @@ type InsertUsageSnapshotParams struct {
 	ResetTokensSeconds   *int64    `json:"reset_tokens_seconds"`
 	DataSource           string    `json:"data_source"`
 	IsComplete           *bool     `json:"is_complete"`
+	TokensUsedDay        *int64    `json:"tokens_used_day"`
+	TokensLimitDay       *int64    `json:"tokens_limit_day"`
+	RegionID             string    `json:"region_id"`
 }
 
 func (q *Queries) InsertUsageSnapshot(ctx context.Context, arg InsertUsageSnapshotParams) error {
@@ -472,6 +929,95 @@ func (q *Queries) InsertUsageSnapshot(ctx context.Context, arg InsertUsageSnapsh
 		arg.ResetTokensSeconds,
 		arg.DataSource,
 		arg.IsComplete,
+		arg.TokensUsedDay,
+		arg.TokensLimitDay,
+		arg.RegionID,
+	)
+	return err
+}
+
+const snoozeAlert = `-- name: SnoozeAlert :execrows
+UPDATE alerts
+SET status = ?, snoozed_until = ?
+WHERE id = ?
+AND organization_id = ?
+AND status != 'resolved'
+`
+
+type SnoozeAlertParams struct {
+	Status         string     `json:"status"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
+	ID             int64      `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+}
+
+func (q *Queries) SnoozeAlert(ctx context.Context, arg SnoozeAlertParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, snoozeAlert,
+		arg.Status,
+		arg.SnoozedUntil,
+		arg.ID,
+		arg.OrganizationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const snoozeOpenAlerts = `-- name: SnoozeOpenAlerts :execrows
+UPDATE alerts
+SET status = ?, snoozed_until = ?
+WHERE organization_id = ?
+AND status != 'resolved'
+`
+
+type SnoozeOpenAlertsParams struct {
+	Status         string     `json:"status"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
+	OrganizationID string     `json:"organization_id"`
+}
+
+func (q *Queries) SnoozeOpenAlerts(ctx context.Context, arg SnoozeOpenAlertsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, snoozeOpenAlerts, arg.Status, arg.SnoozedUntil, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateAlertState = `-- name: UpdateAlertState :exec
+UPDATE alerts
+SET severity = ?,
+    metric_value = ?,
+    threshold_value = ?,
+    message = ?,
+    status = ?,
+    resolved_at = ?,
+    snoozed_until = ?
+WHERE id = ?
+`
+
+type UpdateAlertStateParams struct {
+	Severity       string     `json:"severity"`
+	MetricValue    float64    `json:"metric_value"`
+	ThresholdValue float64    `json:"threshold_value"`
+	Message        *string    `json:"message"`
+	Status         string     `json:"status"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	SnoozedUntil   *time.Time `json:"snoozed_until"`
+	ID             int64      `json:"id"`
+}
+
+func (q *Queries) UpdateAlertState(ctx context.Context, arg UpdateAlertStateParams) error {
+	_, err := q.db.ExecContext(ctx, updateAlertState,
+		arg.Severity,
+		arg.MetricValue,
+		arg.ThresholdValue,
+		arg.Message,
+		arg.Status,
+		arg.ResolvedAt,
+		arg.SnoozedUntil,
+		arg.ID,
 	)
 	return err
 }
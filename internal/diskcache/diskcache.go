@@ -0,0 +1,312 @@
+// Package diskcache shares fetched metrics between processes through files in
+// the data directory, so one-shot commands such as status lines do not call
+// the API on every invocation.
+package diskcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/spf13/viper"
+)
+
+// DefaultMaxAge is how long cached metrics are served without a fetch
+const DefaultMaxAge = 30 * time.Second
+
+// DefaultMaxStale is how old an expired entry may be to be served while it
+// is revalidated
+const DefaultMaxStale = 5 * time.Minute
+
+// errLocked is returned by a non-blocking lock held by another process
+var errLocked = errors.New("cache entry is locked")
+
+// Key identifies a cache entry
+type Key struct {
+	Organization string `json:"organization"`
+	Model        string `json:"model"`
+	// Region is empty when metrics are summed across regions
+	Region string `json:"region,omitempty"`
+	// AuthMode is "session" or "api_key", see cerebras.Client.DataSource
+	AuthMode string `json:"auth_mode"`
+}
+
+// name returns the file name of the entry, without extension
+func (k Key) name() string {
+	sum := sha256.Sum256([]byte(k.Organization + "\x00" + k.Model + "\x00" + k.Region + "\x00" + k.AuthMode))
+	return "metrics-" + hex.EncodeToString(sum[:8])
+}
+
+// Entry is a cached fetch
+type Entry struct {
+	Key
+	FetchedAt time.Time               `json:"fetched_at"`
+	Metrics   *cerebras.RateLimitInfo `json:"metrics"`
+}
+
+// Age returns how old the entry is
+func (e *Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.FetchedAt)
+}
+
+// current returns the entry with reset countdowns reduced by its age, so
+// cached metrics show the time left as of now
+func (e *Entry) current(now time.Time) *Entry {
+	aged := *e
+	aged.Metrics = e.Metrics.Elapsed(e.Age(now))
+	return &aged
+}
+
+// Store reads and writes cache entries in a directory
+type Store struct {
+	dir string
+}
+
+// Open returns the store in the cache directory under the data dir
+func Open() (*Store, error) {
+	dataDir, err := config.GetDataDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(dataDir, "cache")), nil
+}
+
+// NewStore returns a store in dir, created on first write
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load reads an entry; it returns an error wrapping os.ErrNotExist when
+// there is none
+func (s *Store) Load(key Key) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, key.name()+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("corrupt cache entry: %w", err)
+	}
+	// Guard against hash collisions and hand-edited files
+	if entry.Key != key || entry.Metrics == nil {
+		return nil, fmt.Errorf("cache entry does not match: %w", os.ErrNotExist)
+	}
+	return &entry, nil
+}
+
+// Save writes an entry atomically
+func (s *Store) Save(key Key, info *cerebras.RateLimitInfo, at time.Time) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(Entry{Key: key, FetchedAt: at.UTC(), Metrics: info})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, key.name()+".json")
+	tmp, err := os.CreateTemp(s.dir, key.name()+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Clear removes every entry and lock file
+func (s *Store) Clear() error {
+	err := os.RemoveAll(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Lock takes the refresh lock of an entry, waiting for other processes. The
+// returned function releases it.
+func (s *Store) Lock(key Key) (func(), error) {
+	return s.lock(key, true)
+}
+
+// TryLock takes the refresh lock of an entry when it is free. It returns
+// false when another process is refreshing the entry.
+func (s *Store) TryLock(key Key) (func(), bool, error) {
+	unlock, err := s.lock(key, false)
+	if errors.Is(err, errLocked) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return unlock, true, nil
+}
+
+func (s *Store) lock(key Key, wait bool) (func(), error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, key.name()+".lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, wait); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// Options control how Fetch uses the cache
+type Options struct {
+	// MaxAge is how old an entry may be to be served without a fetch
+	MaxAge time.Duration
+	// NoCache forces a live fetch; the result still updates the cache
+	NoCache bool
+	// Stale serves an expired entry immediately and calls Revalidate to
+	// refresh it in the background
+	Stale bool
+	// MaxStale is how old an expired entry may be to be served with Stale;
+	// older entries, e.g. when background refreshes keep failing, are
+	// fetched live. Zero means no limit.
+	MaxStale time.Duration
+	// Revalidate starts a background refresh of the entry, typically by
+	// running "cache refresh" in a separate process
+	Revalidate func(key Key) error
+}
+
+// LoadOptions reads the cache section of the configuration
+func LoadOptions() Options {
+	opts := Options{MaxAge: DefaultMaxAge, MaxStale: DefaultMaxStale}
+	if viper.IsSet("cache.max-age") {
+		if secs := viper.GetFloat64("cache.max-age"); secs >= 0 {
+			opts.MaxAge = time.Duration(secs * float64(time.Second))
+		}
+	}
+	if viper.IsSet("cache.max-stale") {
+		if secs := viper.GetFloat64("cache.max-stale"); secs >= 0 {
+			opts.MaxStale = time.Duration(secs * float64(time.Second))
+		}
+	}
+	opts.Stale = viper.GetBool("cache.stale-while-revalidate")
+	return opts
+}
+
+// Fetcher fetches live metrics, see cerebras.Client.GetMetrics
+type Fetcher func(ctx context.Context) (*cerebras.RateLimitInfo, error)
+
+// Result is the outcome of Fetch
+type Result struct {
+	Entry *Entry
+	// Cached reports whether the entry was served from the cache
+	Cached bool
+	// Stale reports whether an expired entry was served while revalidating
+	Stale bool
+}
+
+// Fetch returns cached metrics younger than MaxAge, or fetches and caches
+// them. With Stale, entries up to MaxStale old are served while they are
+// refreshed in the background. Concurrent processes serialize on the entry
+// lock, so only one of them calls the API and the others read its result.
+func (s *Store) Fetch(ctx context.Context, key Key, opts Options, fetch Fetcher) (*Result, error) {
+	now := time.Now()
+	if !opts.NoCache {
+		if entry, err := s.Load(key); err == nil {
+			if entry.Age(now) <= opts.MaxAge {
+				return &Result{Entry: entry.current(now), Cached: true}, nil
+			}
+			if opts.servesStale(entry, now) && s.revalidate(key, opts.Revalidate) {
+				return &Result{Entry: entry.current(now), Cached: true, Stale: true}, nil
+			}
+		}
+	}
+
+	unlock, err := s.Lock(key)
+	if err != nil {
+		// The cache is an optimization; fetch directly when it is unusable
+		return s.fetchLive(ctx, key, fetch, false)
+	}
+	defer unlock()
+
+	// Another process may have refreshed the entry while we waited
+	if !opts.NoCache {
+		now := time.Now()
+		if entry, err := s.Load(key); err == nil && entry.Age(now) <= opts.MaxAge {
+			return &Result{Entry: entry.current(now), Cached: true}, nil
+		}
+	}
+	return s.fetchLive(ctx, key, fetch, true)
+}
+
+// servesStale reports whether an expired entry may be served while it is
+// revalidated
+func (o Options) servesStale(entry *Entry, now time.Time) bool {
+	if !o.Stale || o.Revalidate == nil {
+		return false
+	}
+	return o.MaxStale <= 0 || entry.Age(now) <= o.MaxStale
+}
+
+// revalidate starts a background refresh unless one is already running. It
+// reports whether the entry is being refreshed.
+func (s *Store) revalidate(key Key, start func(Key) error) bool {
+	unlock, free, err := s.TryLock(key)
+	if err != nil {
+		return false
+	}
+	if !free {
+		return true
+	}
+	unlock()
+	return start(key) == nil
+}
+
+// Refresh fetches and caches an entry unless another process is already
+// refreshing it
+func (s *Store) Refresh(ctx context.Context, key Key, fetch Fetcher) error {
+	unlock, ok, err := s.TryLock(key)
+	if err != nil || !ok {
+		return err
+	}
+	defer unlock()
+	_, err = s.fetchLive(ctx, key, fetch, true)
+	return err
+}
+
+// fetchLive calls the API and optionally stores the result
+func (s *Store) fetchLive(ctx context.Context, key Key, fetch Fetcher, save bool) (*Result, error) {
+	info, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	if save {
+		// A failed write only costs the next invocation a fetch
+		_ = s.Save(key, info, at)
+	}
+	return &Result{Entry: &Entry{Key: key, FetchedAt: at.UTC(), Metrics: info}}, nil
+}
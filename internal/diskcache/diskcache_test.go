@@ -0,0 +1,208 @@
+package diskcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+var testKey = Key{Organization: "org_1", Model: "qwen-3-coder-480b", AuthMode: "session"}
+
+// countingFetcher returns a fetcher reporting the number of calls as the
+// used daily requests
+func countingFetcher(calls *int) Fetcher {
+	return func(ctx context.Context) (*cerebras.RateLimitInfo, error) {
+		*calls++
+		return &cerebras.RateLimitInfo{UsageRequestsDay: int64(*calls), ResetRequestsDay: 3600}, nil
+	}
+}
+
+func TestFetchServesFreshEntries(t *testing.T) {
+	store := NewStore(t.TempDir())
+	calls := 0
+	opts := Options{MaxAge: time.Minute}
+
+	first, err := store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if first.Cached {
+		t.Error("First fetch should be live")
+	}
+
+	second, err := store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !second.Cached || calls != 1 || second.Entry.Metrics.UsageRequestsDay != 1 {
+		t.Errorf("Expected the cached entry after one call, got cached=%v calls=%d", second.Cached, calls)
+	}
+
+	// Entries of other keys are separate
+	other := testKey
+	other.AuthMode = "api_key"
+	if _, err := store.Fetch(context.Background(), other, opts, countingFetcher(&calls)); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected a fetch for another auth mode, got %d calls", calls)
+	}
+	other = testKey
+	other.Region = "us"
+	if _, err := store.Fetch(context.Background(), other, opts, countingFetcher(&calls)); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected a fetch for a single region, got %d calls", calls)
+	}
+}
+
+func TestFetchExpiredAndNoCache(t *testing.T) {
+	store := NewStore(t.TempDir())
+	calls := 0
+	if err := store.Save(testKey, &cerebras.RateLimitInfo{ResetRequestsDay: 3600}, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	result, err := store.Fetch(context.Background(), testKey, Options{MaxAge: 30 * time.Second}, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if result.Cached || calls != 1 {
+		t.Errorf("Expected a live fetch of an expired entry, got cached=%v calls=%d", result.Cached, calls)
+	}
+
+	result, err = store.Fetch(context.Background(), testKey, Options{MaxAge: time.Hour, NoCache: true}, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if result.Cached || calls != 2 {
+		t.Errorf("Expected NoCache to fetch, got cached=%v calls=%d", result.Cached, calls)
+	}
+	entry, err := store.Load(testKey)
+	if err != nil || entry.Metrics.UsageRequestsDay != 2 {
+		t.Errorf("Expected NoCache to update the entry, got %+v, %v", entry, err)
+	}
+}
+
+func TestFetchCachedResetsAge(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(testKey, &cerebras.RateLimitInfo{ResetRequestsDay: 3600, ResetTokensMinute: 5}, time.Now().Add(-10*time.Second)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	result, err := store.Fetch(context.Background(), testKey, Options{MaxAge: time.Minute}, nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got := result.Entry.Metrics.ResetRequestsDay; got < 3585 || got > 3590 {
+		t.Errorf("Expected the daily reset to count down by the entry age, got %d", got)
+	}
+	if got := result.Entry.Metrics.ResetTokensMinute; got != 0 {
+		t.Errorf("Expected an elapsed reset to stop at 0, got %d", got)
+	}
+}
+
+func TestFetchStaleWhileRevalidate(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(testKey, &cerebras.RateLimitInfo{UsageRequestsDay: 7}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	calls, started := 0, 0
+	opts := Options{MaxAge: time.Minute, Stale: true, Revalidate: func(Key) error {
+		started++
+		return nil
+	}}
+	result, err := store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !result.Stale || result.Entry.Metrics.UsageRequestsDay != 7 || calls != 0 || started != 1 {
+		t.Errorf("Expected the stale entry with a background refresh, got stale=%v calls=%d started=%d", result.Stale, calls, started)
+	}
+
+	// A refresh already holding the lock is not started again
+	unlock, err := store.Lock(testKey)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	result, err = store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	unlock()
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !result.Stale || started != 1 {
+		t.Errorf("Expected no second refresh while locked, got stale=%v started=%d", result.Stale, started)
+	}
+
+	// A failed start falls back to a live fetch
+	opts.Revalidate = func(Key) error { return errors.New("no executable") }
+	result, err = store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if result.Stale || calls != 1 {
+		t.Errorf("Expected a live fetch when the refresh cannot start, got stale=%v calls=%d", result.Stale, calls)
+	}
+}
+
+func TestFetchStaleLimit(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(testKey, &cerebras.RateLimitInfo{UsageRequestsDay: 7}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	calls, started := 0, 0
+	opts := Options{MaxAge: time.Minute, Stale: true, MaxStale: 10 * time.Minute, Revalidate: func(Key) error {
+		started++
+		return nil
+	}}
+	result, err := store.Fetch(context.Background(), testKey, opts, countingFetcher(&calls))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if result.Stale || result.Cached || calls != 1 || started != 0 {
+		t.Errorf("Expected a live fetch past max-stale, got stale=%v cached=%v calls=%d started=%d", result.Stale, result.Cached, calls, started)
+	}
+}
+
+func TestRefreshSkipsWhenLocked(t *testing.T) {
+	store := NewStore(t.TempDir())
+	unlock, err := store.Lock(testKey)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, ok, err := store.TryLock(testKey); ok || err != nil {
+		t.Fatalf("Expected TryLock to fail while locked, got %v, %v", ok, err)
+	}
+
+	calls := 0
+	if err := store.Refresh(context.Background(), testKey, countingFetcher(&calls)); err != nil || calls != 0 {
+		t.Errorf("Expected Refresh to skip a locked entry, got %v with %d calls", err, calls)
+	}
+	unlock()
+
+	if err := store.Refresh(context.Background(), testKey, countingFetcher(&calls)); err != nil || calls != 1 {
+		t.Errorf("Expected Refresh to fetch, got %v with %d calls", err, calls)
+	}
+	if _, err := store.Load(testKey); err != nil {
+		t.Errorf("Expected Refresh to save the entry: %v", err)
+	}
+}
+
+func TestClear(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(testKey, &cerebras.RateLimitInfo{}, time.Now()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, err := store.Load(testKey); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no entry after Clear, got %v", err)
+	}
+}
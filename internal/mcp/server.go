@@ -0,0 +1,254 @@
+// Package mcp implements a Model Context Protocol server over stdio, so
+// coding agents can check their remaining quota before starting large jobs.
+// Messages are newline-delimited JSON-RPC 2.0; only the tools capability is
+// offered.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// ProtocolVersion is the latest protocol revision the server speaks
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the revisions accepted from clients, newest first
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a tool offered to clients
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	// Call runs the tool with its arguments and returns a JSON object.
+	// Errors are reported to the client as failed tool results.
+	Call func(ctx context.Context, args json.RawMessage) (interface{}, error) `json:"-"`
+}
+
+// Server answers MCP requests with its tools
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+
+	// out serializes responses written by concurrent requests
+	out sync.Mutex
+	// inflight cancels requests by ID on notifications/cancelled
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+// NewServer returns a server identifying as name and version
+func NewServer(name, version string, tools ...Tool) *Server {
+	return &Server{name: name, version: version, tools: tools, inflight: map[string]context.CancelFunc{}}
+}
+
+// request is a JSON-RPC request, or a notification when ID is empty
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Serve answers the messages read from r on w until r ends or ctx is
+// cancelled. Requests run concurrently; Serve waits for them before
+// returning.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	errs := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errs <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			var req request
+			if err := json.Unmarshal(line, &req); err != nil {
+				s.write(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}})
+				continue
+			}
+			if len(req.ID) == 0 {
+				s.notify(req)
+				continue
+			}
+			reqCtx, cancel := context.WithCancel(ctx)
+			s.mu.Lock()
+			s.inflight[string(req.ID)] = cancel
+			s.mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					s.mu.Lock()
+					delete(s.inflight, string(req.ID))
+					s.mu.Unlock()
+					cancel()
+				}()
+				resp := response{JSONRPC: "2.0", ID: req.ID}
+				result, err := s.handle(reqCtx, req)
+				var rpcErr *rpcError
+				switch {
+				case errors.As(err, &rpcErr):
+					resp.Error = rpcErr
+				case err != nil:
+					resp.Error = &rpcError{codeInvalidRequest, err.Error()}
+				default:
+					resp.Result = result
+				}
+				s.write(w, resp)
+			}()
+		}
+	}
+}
+
+// notify handles a notification, which gets no response
+func (s *Server) notify(req request) {
+	if req.Method != "notifications/cancelled" {
+		return
+	}
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(req.Params, &params) != nil {
+		return
+	}
+	s.mu.Lock()
+	cancel := s.inflight[string(params.RequestID)]
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req request) (interface{}, error) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{codeInvalidRequest, `jsonrpc must be "2.0"`}
+	}
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := ProtocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// toolResult is the result of tools/call. The structured result is also
+// given as compact JSON text for clients without structured content.
+type toolResult struct {
+	Content           []textContent `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == params.Name })
+	if i < 0 {
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)}
+	}
+	if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	out, err := s.tools[i].Call(ctx, params.Arguments)
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: string(text)}}, StructuredContent: out}, nil
+}
+
+// write sends a response as one line
+func (s *Server) write(w io.Writer, resp response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID, Error: &rpcError{codeInvalidRequest, err.Error()}})
+	}
+	s.out.Lock()
+	defer s.out.Unlock()
+	_, _ = w.Write(append(data, '\n'))
+}
@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+// session runs a server over pipes and exchanges messages with it
+type session struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	done   chan error
+	cancel context.CancelFunc
+}
+
+func newSession(t *testing.T, tools ...Tool) *session {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &session{t: t, in: inW, out: bufio.NewScanner(outR), done: make(chan error, 1), cancel: cancel}
+	go func() {
+		s.done <- NewServer("test", "1.0", tools...).Serve(ctx, inR, outW)
+		_ = outW.Close()
+	}()
+	return s
+}
+
+func (s *session) send(line string) {
+	s.t.Helper()
+	if _, err := io.WriteString(s.in, line+"\n"); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func (s *session) receive() map[string]interface{} {
+	s.t.Helper()
+	if !s.out.Scan() {
+		s.t.Fatalf("Expected a response: %v", s.out.Err())
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(s.out.Bytes(), &msg); err != nil {
+		s.t.Fatalf("Decoding %q: %v", s.out.Text(), err)
+	}
+	return msg
+}
+
+func (s *session) close() {
+	s.t.Helper()
+	_ = s.in.Close()
+	if err := <-s.done; err != nil {
+		s.t.Errorf("Serve: %v", err)
+	}
+	s.cancel()
+}
+
+func TestServer(t *testing.T) {
+	echo := Tool{
+		Name:        "echo",
+		Description: "Returns its arguments",
+		InputSchema: json.RawMessage(`{"type":"object"}`),
+		Call: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var v map[string]interface{}
+			if err := json.Unmarshal(args, &v); err != nil {
+				return nil, err
+			}
+			if v["fail"] == true {
+				return nil, errors.New("asked to fail")
+			}
+			return v, nil
+		},
+	}
+	s := newSession(t, echo)
+	defer s.close()
+
+	s.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`)
+	init := s.receive()
+	result, _ := init["result"].(map[string]interface{})
+	if init["id"] != float64(1) || result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("Expected the client's protocol version echoed, got %v", init)
+	}
+	if caps, _ := result["capabilities"].(map[string]interface{}); caps["tools"] == nil {
+		t.Errorf("Expected the tools capability, got %v", result["capabilities"])
+	}
+
+	// Notifications get no response, so the next line answers the ping
+	s.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	s.send(`{"jsonrpc":"2.0","id":"p","method":"ping"}`)
+	if ping := s.receive(); ping["id"] != "p" || ping["error"] != nil {
+		t.Errorf("Unexpected ping response: %v", ping)
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	list := s.receive()
+	tools := list["result"].(map[string]interface{})["tools"].([]interface{})
+	if len(tools) != 1 || tools[0].(map[string]interface{})["name"] != "echo" || tools[0].(map[string]interface{})["inputSchema"] == nil {
+		t.Errorf("Unexpected tools: %v", tools)
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"a":1}}}`)
+	call := s.receive()["result"].(map[string]interface{})
+	content := call["content"].([]interface{})[0].(map[string]interface{})
+	if call["isError"] != false || content["text"] != `{"a":1}` {
+		t.Errorf("Expected compact JSON text, got %v", call)
+	}
+	if structured, _ := call["structuredContent"].(map[string]interface{}); structured["a"] != float64(1) {
+		t.Errorf("Expected structured content, got %v", call["structuredContent"])
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"fail":true}}}`)
+	failed := s.receive()["result"].(map[string]interface{})
+	if failed["isError"] != true {
+		t.Errorf("Expected a failed tool result, got %v", failed)
+	}
+
+	errorCode := func(msg map[string]interface{}) float64 {
+		e, _ := msg["error"].(map[string]interface{})
+		code, _ := e["code"].(float64)
+		return code
+	}
+	s.send(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`)
+	if code := errorCode(s.receive()); code != codeInvalidParams {
+		t.Errorf("Expected invalid params for an unknown tool, got %v", code)
+	}
+	s.send(`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`)
+	if code := errorCode(s.receive()); code != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %v", code)
+	}
+	s.send(`{not json`)
+	if code := errorCode(s.receive()); code != codeParseError {
+		t.Errorf("Expected a parse error, got %v", code)
+	}
+}
+
+func TestNewModelQuota(t *testing.T) {
+	quota := cerebras.UsageQuota{
+		ModelId:           "qwen-3-coder-480b",
+		RegionId:          "us",
+		RequestsPerMinute: "30",
+		TokensPerMinute:   "-1",
+		RequestsPerDay:    "1000",
+		MaxSequenceLength: "131072",
+	}
+	usage := []cerebras.OrganizationUsage{
+		{ModelId: "qwen-3-coder-480b", RegionId: "eu", RPD: "999"},
+		{ModelId: "qwen-3-coder-480b", RegionId: "us", RPM: "3", TPM: "500", RPD: "250"},
+	}
+
+	m := newModelQuota(cerebras.QuotaMetrics(quota, usage), "qwen-3-coder-480b")
+
+	if !m.Monitored || m.Region != "us" || m.MaxSequenceLength == nil || *m.MaxSequenceLength != 131072 || m.MaxCompletionTokens != nil {
+		t.Errorf("Unexpected model: %+v", m)
+	}
+	windows := map[string]windowLimits{}
+	for _, w := range m.Windows {
+		windows[w.Window] = w
+	}
+	if day := windows[cerebras.WindowRequestsDay]; day.Used != 250 || *day.Remaining != 750 || *day.Percent != 25 {
+		t.Errorf("Expected the usage of the same region, got %+v", day)
+	}
+	if tokens := windows[cerebras.WindowTokensMinute]; !tokens.Limit.IsUnlimited() || tokens.Remaining != nil || tokens.Used != 500 {
+		t.Errorf("Expected an unlimited window without remaining, got %+v", tokens)
+	}
+	if hour := windows[cerebras.WindowTokensHour]; hour.Limit.IsKnown() {
+		t.Errorf("Expected an unknown limit, got %+v", hour)
+	}
+
+	data, err := json.Marshal(windows[cerebras.WindowTokensMinute])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"limit":"unlimited"`) {
+		t.Errorf("Expected unlimited in the output, got %s", data)
+	}
+}
+
+func TestNewModelQuotaRegions(t *testing.T) {
+	quotas := []cerebras.UsageQuota{
+		{ModelId: "qwen-3-coder-480b", RegionId: "us", RequestsPerDay: "1000"},
+		{ModelId: "qwen-3-coder-480b", RegionId: "eu", RequestsPerDay: "500"},
+	}
+	usage := []cerebras.OrganizationUsage{
+		{ModelId: "qwen-3-coder-480b", RegionId: "us", RPD: "100"},
+		{ModelId: "qwen-3-coder-480b", RegionId: "eu", RPD: "50"},
+	}
+	var infos []*cerebras.RateLimitInfo
+	for _, q := range quotas {
+		infos = append(infos, cerebras.QuotaMetrics(q, usage))
+	}
+	merged := cerebras.MergeRegions(infos)
+	if len(merged) != 1 {
+		t.Fatalf("Expected one merged model, got %d", len(merged))
+	}
+
+	m := newModelQuota(merged[0], "qwen-3-coder-480b")
+	if m.Region != "" || len(m.Regions) != 2 || m.Regions[0].Region != "us" || m.Regions[1].Region != "eu" {
+		t.Fatalf("Expected an organization-wide model with two regions, got %+v", m)
+	}
+	for _, w := range m.Windows {
+		if w.Window == cerebras.WindowRequestsDay && (w.Used != 150 || *w.Remaining != 1350) {
+			t.Errorf("Expected the regions summed, got %+v", w)
+		}
+	}
+	if m.Regions[0].Monitored {
+		t.Error("Expected only the total to be marked monitored")
+	}
+}
+
+func TestGetUsageHistoryWithoutStore(t *testing.T) {
+	b := &Backend{Model: "qwen-3-coder-480b"}
+	if _, err := b.getUsageHistory(context.Background(), json.RawMessage(`{"since":"nope"}`)); err == nil || !strings.Contains(err.Error(), "invalid since") {
+		t.Errorf("Expected an invalid since error, got %v", err)
+	}
+	if _, err := b.getUsageHistory(context.Background(), json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("Expected history to be unavailable, got %v", err)
+	}
+}
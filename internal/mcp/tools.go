@@ -0,0 +1,467 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+)
+
+// DefaultLookback is how far back predict_exhaustion measures the recent
+// burn rate
+const DefaultLookback = time.Hour
+
+// DefaultHistory is how far back get_usage_history reaches by default
+const DefaultHistory = 24 * time.Hour
+
+// maxHistoryRows caps the rows returned by get_usage_history
+const maxHistoryRows = 500
+
+// Backend answers the tools from the Cerebras API and the snapshot store
+type Backend struct {
+	Client       *cerebras.Client
+	Organization string
+	Model        string
+	// Queries is the snapshot store, nil when history is unavailable
+	Queries *db.Queries
+	// HistoryOrganization is the organization snapshots are recorded under
+	HistoryOrganization string
+	// Region is the region snapshots are recorded under, empty when summed
+	Region string
+	// Now returns the current time, time.Now when nil
+	Now func() time.Time
+}
+
+func (b *Backend) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+	return time.Now()
+}
+
+// Tools returns the tools offered by the backend
+func (b *Backend) Tools() []Tool {
+	return []Tool{
+		{
+			Name:        "get_rate_limits",
+			Description: "Current usage, limit, remaining and seconds until reset of every rate limit window of the monitored model.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
+			Call:        b.getRateLimits,
+		},
+		{
+			Name:        "predict_exhaustion",
+			Description: "Projects when each limited window runs out at the recent burn rate and whether that happens before it resets. Use before starting a large job.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+				`"window":{"type":"string","enum":["requests-minute","requests-hour","requests-day","tokens-minute","tokens-hour","tokens-day"],"description":"Only project this window"},` +
+				`"lookback_minutes":{"type":"integer","minimum":1,"description":"Minutes of history used for the burn rate of daily windows (default 60)"}}}`),
+			Call: b.predictExhaustion,
+		},
+		{
+			Name:        "list_models_and_quotas",
+			Description: "Every model available to the organization with its limits per window, current usage and context sizes. Models quoted in several regions are summed, with a per-region breakdown.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
+			Call:        b.listModelsAndQuotas,
+		},
+		{
+			Name:        "get_usage_history",
+			Description: "Recorded usage of the monitored model: raw snapshots, or hourly or daily totals with burn rates.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+				`"since":{"type":"string","description":"Go duration to look back, such as 6h or 30m (default 24h)"},` +
+				`"granularity":{"type":"string","enum":["raw","hour","day"],"description":"Snapshots or rollups (default hour)"}}}`),
+			Call: b.getUsageHistory,
+		},
+	}
+}
+
+// windowLimits is a window in tool output. Limits are numbers, "unlimited"
+// or null when unknown.
+type windowLimits struct {
+	Window       string         `json:"window"`
+	Used         int64          `json:"used"`
+	Limit        cerebras.Limit `json:"limit"`
+	Remaining    *int64         `json:"remaining"`
+	Percent      *float64       `json:"percent"`
+	ResetSeconds int64          `json:"reset_seconds,omitempty"`
+}
+
+func newWindowLimits(w cerebras.WindowUsage) windowLimits {
+	out := windowLimits{Window: w.Name, Used: w.Used, Limit: w.Limit, ResetSeconds: w.Reset}
+	if _, ok := w.Limit.Value(); ok {
+		remaining := w.Remaining
+		percent := round(w.Percent(), 2)
+		out.Remaining, out.Percent = &remaining, &percent
+	}
+	return out
+}
+
+type rateLimits struct {
+	Organization string         `json:"organization,omitempty"`
+	Model        string         `json:"model"`
+	Region       string         `json:"region,omitempty"`
+	Source       string         `json:"source"`
+	FetchedAt    time.Time      `json:"fetched_at"`
+	Windows      []windowLimits `json:"windows"`
+}
+
+func (b *Backend) getRateLimits(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+	info, err := b.Client.GetMetrics(ctx, b.Organization)
+	if err != nil {
+		return nil, err
+	}
+	out := rateLimits{
+		Organization: b.Organization,
+		Model:        b.Model,
+		Region:       info.RegionId,
+		Source:       b.Client.DataSource(b.Organization),
+		FetchedAt:    b.now().UTC().Truncate(time.Second),
+	}
+	for _, name := range cerebras.Windows {
+		w, err := info.Window(name)
+		if err != nil {
+			return nil, err
+		}
+		out.Windows = append(out.Windows, newWindowLimits(w))
+	}
+	return out, nil
+}
+
+type exhaustion struct {
+	Window    string  `json:"window"`
+	Remaining int64   `json:"remaining"`
+	Rate      float64 `json:"rate_per_minute"`
+	// RateSource is "recent" when measured from history over the lookback,
+	// "window_average" when averaged since the window started
+	RateSource     string `json:"rate_source"`
+	ResetSeconds   int64  `json:"reset_seconds"`
+	ExhaustsIn     *int64 `json:"exhausts_in_seconds"`
+	ExhaustsBefore bool   `json:"exhausts_before_reset"`
+}
+
+type exhaustionReport struct {
+	Model   string       `json:"model"`
+	Windows []exhaustion `json:"windows"`
+	// Unlimited lists windows without a limit to run out of
+	Unlimited []string `json:"unlimited,omitempty"`
+}
+
+func (b *Backend) predictExhaustion(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Window          string `json:"window"`
+		LookbackMinutes int    `json:"lookback_minutes"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	windows := cerebras.Windows
+	if args.Window != "" {
+		if !slices.Contains(cerebras.Windows, args.Window) {
+			return nil, fmt.Errorf("unknown window %q", args.Window)
+		}
+		windows = []string{args.Window}
+	}
+	lookback := DefaultLookback
+	if args.LookbackMinutes < 0 {
+		return nil, errors.New("lookback_minutes must be positive")
+	} else if args.LookbackMinutes > 0 {
+		lookback = time.Duration(args.LookbackMinutes) * time.Minute
+	}
+
+	info, err := b.Client.GetMetrics(ctx, b.Organization)
+	if err != nil {
+		return nil, err
+	}
+	now := b.now()
+	recent := b.recentRates(ctx, now, lookback)
+
+	out := exhaustionReport{Model: b.Model, Windows: []exhaustion{}}
+	for _, name := range windows {
+		w, err := info.Window(name)
+		if err != nil {
+			return nil, err
+		}
+		if w.Limit.IsUnlimited() {
+			out.Unlimited = append(out.Unlimited, name)
+			continue
+		}
+		rate, source := recent[name], "recent"
+		if _, ok := recent[name]; !ok {
+			if rate, ok = pacing.AverageRate(w, now); !ok {
+				continue
+			}
+			source = "window_average"
+		}
+		p, ok := pacing.Project(w, rate, now)
+		if !ok {
+			continue
+		}
+		e := exhaustion{
+			Window:         name,
+			Remaining:      p.Remaining,
+			Rate:           round(p.Rate, 2),
+			RateSource:     source,
+			ResetSeconds:   int64(p.ResetsIn.Seconds()),
+			ExhaustsBefore: p.Exhausts,
+		}
+		if p.ExhaustsIn >= 0 {
+			seconds := int64(p.ExhaustsIn.Seconds())
+			e.ExhaustsIn = &seconds
+		}
+		out.Windows = append(out.Windows, e)
+	}
+	return out, nil
+}
+
+// recentRates measures the per-minute burn rate of the daily windows over
+// the lookback from the recorded snapshots. Shorter windows are measured
+// from the API alone. It returns no rates without enough history.
+func (b *Backend) recentRates(ctx context.Context, now time.Time, lookback time.Duration) map[string]float64 {
+	rates := map[string]float64{}
+	if b.Queries == nil {
+		return rates
+	}
+	start := now.Add(-lookback)
+	snapshots, err := b.Queries.GetUsageSnapshotsBetween(ctx, db.GetUsageSnapshotsBetweenParams{
+		OrganizationID: b.HistoryOrganization,
+		ModelName:      b.Model,
+		RegionID:       b.Region,
+		StartTime:      start,
+		EndTime:        now,
+	})
+	if err != nil || len(snapshots) == 0 {
+		return rates
+	}
+	var base *db.UsageSnapshot
+	if before, err := b.Queries.GetLatestUsageSnapshotBefore(ctx, db.GetLatestUsageSnapshotBeforeParams{
+		OrganizationID: b.HistoryOrganization,
+		ModelName:      b.Model,
+		RegionID:       b.Region,
+		Timestamp:      start,
+	}); err == nil {
+		base = &before
+	}
+	// Without a base the growth is counted from the first snapshot
+	from := start
+	if base == nil {
+		from = snapshots[0].Timestamp
+		if len(snapshots) < 2 {
+			return rates
+		}
+	}
+	period := snapshots[len(snapshots)-1].Timestamp.Sub(from)
+	if period < time.Minute {
+		return rates
+	}
+	if base == nil {
+		base, snapshots = &snapshots[0], snapshots[1:]
+	}
+	params := collector.Aggregate("lookback", start, period, base, snapshots, nil)
+	rates[cerebras.WindowTokensDay] = *params.AvgBurnRateTokens
+	rates[cerebras.WindowRequestsDay] = *params.AvgBurnRateRequests
+	return rates
+}
+
+type modelQuota struct {
+	Model               string         `json:"model"`
+	Region              string         `json:"region,omitempty"`
+	Windows             []windowLimits `json:"windows"`
+	MaxSequenceLength   *int64         `json:"max_sequence_length,omitempty"`
+	MaxCompletionTokens *int64         `json:"max_completion_tokens,omitempty"`
+	Monitored           bool           `json:"monitored,omitempty"`
+	// Regions breaks down a model quoted in several regions, whose windows
+	// are summed across them
+	Regions []modelQuota `json:"regions,omitempty"`
+}
+
+type modelsReport struct {
+	Organization string       `json:"organization,omitempty"`
+	Source       string       `json:"source"`
+	Models       []modelQuota `json:"models"`
+	// Warning explains why only the monitored model is listed
+	Warning string `json:"warning,omitempty"`
+}
+
+func (b *Backend) listModelsAndQuotas(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+	// API keys see the rate limit headers of their own model only
+	if b.Client.SessionToken() == "" {
+		info, err := b.Client.GetMetrics(ctx, b.Organization)
+		if err != nil {
+			return nil, err
+		}
+		model := newModelQuota(info, b.Model)
+		model.Model, model.Monitored = b.Model, true
+		return modelsReport{
+			Source:  "headers",
+			Models:  []modelQuota{model},
+			Warning: "quotas of other models need a session token; only the monitored model is listed",
+		}, nil
+	}
+
+	infos, err := b.Client.ListModelMetrics(ctx, b.Organization)
+	if err != nil {
+		return nil, err
+	}
+	out := modelsReport{Organization: b.Organization, Source: "graphql", Models: []modelQuota{}}
+	for _, info := range cerebras.MergeRegions(infos) {
+		out.Models = append(out.Models, newModelQuota(info, b.Model))
+	}
+	return out, nil
+}
+
+// newModelQuota reports the limits and usage of one model, and of each of
+// its regions when they are summed
+func newModelQuota(info *cerebras.RateLimitInfo, monitored string) modelQuota {
+	m := modelQuota{
+		Model:               info.ModelId,
+		Region:              info.RegionId,
+		MaxSequenceLength:   positive(info.MaxSequenceLength),
+		MaxCompletionTokens: positive(info.MaxCompletionTokens),
+		Monitored:           info.ModelId == monitored,
+	}
+	for _, name := range cerebras.Windows {
+		if w, err := info.Window(name); err == nil {
+			m.Windows = append(m.Windows, newWindowLimits(w))
+		}
+	}
+	for i := range info.Regions {
+		region := newModelQuota(&info.Regions[i], monitored)
+		region.Monitored = false
+		m.Regions = append(m.Regions, region)
+	}
+	return m
+}
+
+// positive returns a pointer to v, or nil when it is unknown (zero)
+func positive(v int64) *int64 {
+	if v <= 0 {
+		return nil
+	}
+	return &v
+}
+
+type historyRow struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Raw snapshots
+	TokensUsedMinute *int64 `json:"tokens_used_minute,omitempty"`
+	TokensUsedDay    *int64 `json:"tokens_used_day,omitempty"`
+	RequestsUsedDay  *int64 `json:"requests_used_day,omitempty"`
+	// Rollups
+	Tokens           *int64   `json:"tokens,omitempty"`
+	Requests         *int64   `json:"requests,omitempty"`
+	TokensPerMinute  *float64 `json:"tokens_per_minute,omitempty"`
+	PeakTokensMinute *float64 `json:"peak_tokens_per_minute,omitempty"`
+}
+
+type historyReport struct {
+	Model       string       `json:"model"`
+	Granularity string       `json:"granularity"`
+	Since       time.Time    `json:"since"`
+	Rows        []historyRow `json:"rows"`
+	Truncated   bool         `json:"truncated,omitempty"`
+}
+
+func (b *Backend) getUsageHistory(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Since       string `json:"since"`
+		Granularity string `json:"granularity"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	lookback := DefaultHistory
+	if args.Since != "" {
+		d, err := time.ParseDuration(args.Since)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid since %q: expected a positive duration such as 6h", args.Since)
+		}
+		lookback = d
+	}
+	if args.Granularity == "" {
+		args.Granularity = "hour"
+	}
+	if b.Queries == nil {
+		return nil, errors.New("usage history is unavailable: the usage database could not be opened (run 'cerebras-monitor migrations up')")
+	}
+
+	now := b.now()
+	since := now.Add(-lookback).UTC().Truncate(time.Second)
+	out := historyReport{Model: b.Model, Granularity: args.Granularity, Since: since, Rows: []historyRow{}}
+	switch args.Granularity {
+	case "raw":
+		snapshots, err := b.Queries.GetUsageSnapshotsBetween(ctx, db.GetUsageSnapshotsBetweenParams{
+			OrganizationID: b.HistoryOrganization,
+			ModelName:      b.Model,
+			RegionID:       b.Region,
+			StartTime:      since,
+			EndTime:        now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Keep the most recent snapshots
+		if len(snapshots) > maxHistoryRows {
+			snapshots, out.Truncated = snapshots[len(snapshots)-maxHistoryRows:], true
+		}
+		for _, s := range snapshots {
+			out.Rows = append(out.Rows, historyRow{
+				Timestamp:        s.Timestamp.UTC(),
+				TokensUsedMinute: s.TokensUsed,
+				TokensUsedDay:    s.TokensUsedDay,
+				RequestsUsedDay:  s.RequestsUsed,
+			})
+		}
+	case "hour", "day":
+		window := collector.RollupHour
+		if args.Granularity == "day" {
+			window = collector.RollupDay
+		}
+		// Rows come newest first
+		metrics, err := b.Queries.GetUsageMetrics(ctx, db.GetUsageMetricsParams{
+			OrganizationID: b.HistoryOrganization,
+			ModelName:      b.Model,
+			RegionID:       b.Region,
+			TimeWindow:     window,
+			Limit:          maxHistoryRows,
+		})
+		if err != nil {
+			return nil, err
+		}
+		period, _ := collector.RollupPeriod(window)
+		for i := len(metrics) - 1; i >= 0; i-- {
+			m := metrics[i]
+			if !m.Timestamp.Add(period).After(since) {
+				continue
+			}
+			out.Rows = append(out.Rows, historyRow{
+				Timestamp:        m.Timestamp.UTC(),
+				Tokens:           m.TotalTokensUsed,
+				Requests:         m.TotalRequestsUsed,
+				TokensPerMinute:  roundPtr(m.AvgBurnRateTokens),
+				PeakTokensMinute: m.PeakBurnRateTokens,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unknown granularity %q: expected raw, hour or day", args.Granularity)
+	}
+	return out, nil
+}
+
+func round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
+
+func roundPtr(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	r := round(*v, 2)
+	return &r
+}
@@ -0,0 +1,363 @@
+// Package monitor turns fetched metrics into snapshots: it records usage
+// history, evaluates soft limits and budgets, and estimates reset times. The
+// dashboard and the daemon share it, so both process a poll the same way.
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/alerts"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/collector"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
+)
+
+// Snapshot is the outcome of one poll
+type Snapshot struct {
+	Organization string                     `json:"organization"`
+	Model        string                     `json:"model"`
+	Region       string                     `json:"region,omitempty"`
+	Source       string                     `json:"source"`
+	FetchedAt    time.Time                  `json:"fetched_at"`
+	Metrics      *cerebras.RateLimitInfo    `json:"metrics"`
+	Spend        *billing.Spend             `json:"spend,omitempty"`
+	BudgetAlerts []billing.BudgetAlert      `json:"budget_alerts,omitempty"`
+	Breaches     []pacing.Breach            `json:"breaches,omitempty"`
+	Rules        []alerts.Condition         `json:"rules,omitempty"`
+	Estimates    map[string]resets.Estimate `json:"estimates,omitempty"`
+}
+
+// At returns the snapshot with reset countdowns as of now
+func (s *Snapshot) At(now time.Time) *Snapshot {
+	c := *s
+	if s.Metrics != nil {
+		c.Metrics = s.Metrics.Elapsed(now.Sub(s.FetchedAt))
+	}
+	return &c
+}
+
+// Monitor polls one organization and model
+type Monitor struct {
+	client       *cerebras.Client
+	organization string
+	model        string
+
+	// mu serializes polls so alerts see them in order
+	mu sync.Mutex
+
+	// History and cost tracking (optional, see WithHistory)
+	queries   *db.Queries
+	collector *collector.Collector
+	ledger    *billing.Ledger
+	budgets   billing.Budgets
+	// lastRollup is when usage_metrics was last updated
+	lastRollup time.Time
+
+	// Self-imposed soft limits (optional, see WithSoftLimits)
+	softLimits []pacing.SoftLimit
+
+	// Reset times for windows the API does not report
+	estimator *resets.Estimator
+
+	// Alert rules from the configuration (optional, see WithRules)
+	rules     *alerts.Evaluator
+	ruleSpecs []alerts.Rule
+
+	// Alert state across polls, see WithAlertPolicy
+	alerts *alerts.Engine
+
+	// Notifiers for alert changes (optional, see WithNotifications)
+	dispatcher *notify.Dispatcher
+}
+
+// New creates a monitor for the organization and model
+func New(client *cerebras.Client, organization, model string) *Monitor {
+	return &Monitor{
+		client:       client,
+		organization: organization,
+		model:        model,
+		estimator:    resets.NewEstimator(resets.DefaultRule()),
+		alerts:       alerts.NewEngine(organization, model, alerts.Policy{Cooldown: alerts.DefaultCooldown}),
+	}
+}
+
+// WithHistory enables snapshot recording and cost tracking backed by the usage database
+func (m *Monitor) WithHistory(queries *db.Queries, prices *billing.PriceTable, budgets billing.Budgets) *Monitor {
+	m.queries = queries
+	m.collector = collector.New(queries)
+	m.ledger = billing.NewLedger(queries, prices)
+	m.budgets = budgets
+	m.alerts.WithStore(queries, m.HistoryOrganization())
+	return m
+}
+
+// WithSoftLimits enables soft limit warnings. Crossed limits are tracked as
+// alerts, stored when history is enabled.
+func (m *Monitor) WithSoftLimits(limits []pacing.SoftLimit) *Monitor {
+	m.softLimits = limits
+	return m
+}
+
+// WithAlertPolicy replaces the default alert cooldown and quiet hours
+func (m *Monitor) WithAlertPolicy(policy alerts.Policy) *Monitor {
+	m.alerts = alerts.NewEngine(m.organization, m.model, policy)
+	if m.queries != nil {
+		m.alerts.WithStore(m.queries, m.HistoryOrganization())
+	}
+	return m
+}
+
+// WithRules enables the alert rules covering the organization and model
+func (m *Monitor) WithRules(rules []alerts.Rule) *Monitor {
+	m.rules = alerts.NewEvaluator(rules, m.HistoryOrganization(), m.model)
+	m.ruleSpecs = rules
+	return m
+}
+
+// For returns a monitor for another organization or model with the same
+// client, history, soft limits, reset rule, alert policy, rules and
+// notifiers. Its reset estimator is its own, learned from the history of the
+// new organization and model, since resets differ between them.
+func (m *Monitor) For(organization, model string) *Monitor {
+	other := New(m.client, organization, model)
+	estimator := resets.NewEstimator(m.estimator.Rule())
+	if m.queries != nil {
+		other.WithHistory(m.queries, m.ledger.Prices(), m.budgets)
+		_ = estimator.LearnFromHistory(context.Background(), m.queries, other.HistoryOrganization(), model, other.Region(), time.Now())
+	}
+	other.WithSoftLimits(m.softLimits).
+		WithResetEstimator(estimator).
+		WithAlertPolicy(m.alerts.Policy()).
+		WithNotifications(m.dispatcher)
+	if m.rules != nil {
+		other.WithRules(m.ruleSpecs)
+	}
+	return other
+}
+
+// WithResetEstimator replaces the default UTC-midnight reset estimator
+func (m *Monitor) WithResetEstimator(estimator *resets.Estimator) *Monitor {
+	m.estimator = estimator
+	return m
+}
+
+// WithNotifications delivers fired, escalated and resolved alerts to the
+// dispatcher's targets
+func (m *Monitor) WithNotifications(dispatcher *notify.Dispatcher) *Monitor {
+	m.dispatcher = dispatcher
+	return m
+}
+
+// Client returns the API client
+func (m *Monitor) Client() *cerebras.Client {
+	return m.client
+}
+
+// Organization returns the monitored organization, empty for API key auth
+// without one
+func (m *Monitor) Organization() string {
+	return m.organization
+}
+
+// Model returns the monitored model
+func (m *Monitor) Model() string {
+	return m.model
+}
+
+// Region returns the monitored region, cerebras.AllRegions when metrics are
+// summed across regions. History is recorded and read per region.
+func (m *Monitor) Region() string {
+	return m.client.Region()
+}
+
+// Budgets returns the configured spend budgets
+func (m *Monitor) Budgets() billing.Budgets {
+	return m.budgets
+}
+
+// Queries returns the usage database, or nil when history is disabled
+func (m *Monitor) Queries() *db.Queries {
+	return m.queries
+}
+
+// SnoozeAlerts holds back the notifications of the open alerts until the
+// given time, or lifts the snooze when it is not in the future. It returns
+// the number of alerts changed.
+func (m *Monitor) SnoozeAlerts(ctx context.Context, until time.Time) int {
+	return m.alerts.Snooze(ctx, time.Now(), until)
+}
+
+// HistoryOrganization returns the organization ID history is recorded under
+func (m *Monitor) HistoryOrganization() string {
+	if m.organization == "" {
+		return collector.DefaultOrganization
+	}
+	return m.organization
+}
+
+// Poll fetches the metrics and processes them into a snapshot
+func (m *Monitor) Poll(ctx context.Context) (*Snapshot, error) {
+	metrics, err := m.client.GetModelMetrics(ctx, m.organization, m.model)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	snap := &Snapshot{
+		Organization: m.organization,
+		Model:        m.model,
+		Region:       m.Region(),
+		Source:       m.client.DataSource(m.organization),
+		FetchedAt:    now.UTC(),
+		Metrics:      metrics,
+	}
+	conditions := m.checkSoftLimits(snap)
+	conditions = append(conditions, m.trackHistory(snap)...)
+	conditions = append(conditions, m.evaluateRules(snap)...)
+	// Estimates are kept apart from the metrics, which hold reported resets
+	// only, both in the history and in the daemon's cache
+	snap.Estimates = m.estimator.Apply(metrics, now)
+	m.notify(ctx, m.alerts.Observe(ctx, now, conditions))
+	return snap, nil
+}
+
+// notify delivers events in the background so slow notifiers do not delay
+// the poll; deliveries still in flight are cancelled along with ctx
+func (m *Monitor) notify(ctx context.Context, events []notify.Event) {
+	if len(events) == 0 || m.dispatcher.Empty() {
+		return
+	}
+	go func() {
+		_ = m.dispatcher.Dispatch(ctx, events...)
+	}()
+}
+
+// checkSoftLimits evaluates the soft limits against the fetched metrics and
+// returns the crossed ones as alert conditions
+func (m *Monitor) checkSoftLimits(snap *Snapshot) []alerts.Condition {
+	if len(m.softLimits) == 0 {
+		return nil
+	}
+
+	snap.Breaches = pacing.Evaluate(m.softLimits, m.HistoryOrganization(), m.model, snap.Metrics, time.Now().In(config.GetLocation()))
+	conditions := make([]alerts.Condition, 0, len(snap.Breaches))
+	for _, b := range snap.Breaches {
+		conditions = append(conditions, alerts.Condition{
+			Type:      "soft_limit",
+			Metric:    b.Limit.Window,
+			Severity:  notify.SeverityWarning,
+			Value:     b.Percent,
+			Threshold: b.Limit.MaxPercent,
+			Message:   b.Message(),
+		})
+	}
+	return conditions
+}
+
+// trackHistory records the snapshot and computes spend and budget alerts,
+// returning them as alert conditions. History is best-effort: failures leave
+// the cost fields empty.
+func (m *Monitor) trackHistory(snap *Snapshot) []alerts.Condition {
+	if m.collector == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	organization := m.HistoryOrganization()
+	if err := m.collector.Record(ctx, organization, m.model, snap.Region, snap.Source, snap.Metrics); err != nil {
+		return nil
+	}
+	m.rollup(ctx, snap.FetchedAt)
+	if m.ledger.Prices().Empty() {
+		return nil
+	}
+
+	spend, err := m.ledger.Spend(ctx, organization, m.model, snap.Region, m.estimator.Rule(), time.Now())
+	if err != nil {
+		return nil
+	}
+	snap.Spend = &spend
+	snap.BudgetAlerts = m.budgets.Evaluate(spend)
+
+	conditions := make([]alerts.Condition, 0, len(snap.BudgetAlerts))
+	for _, alert := range snap.BudgetAlerts {
+		conditions = append(conditions, alerts.Condition{
+			Type:      "budget",
+			Metric:    "cost_" + alert.Period,
+			Severity:  alert.Severity,
+			Value:     alert.Spent,
+			Threshold: alert.Budget,
+			Message:   alert.Message(spend.Currency),
+		})
+	}
+	return conditions
+}
+
+// evaluateRules evaluates the alert rules against the fetched metrics and
+// returns the conditions of the rules that fire. Deviation metrics read the
+// usage_metrics rows of the buckets in progress.
+func (m *Monitor) evaluateRules(snap *Snapshot) []alerts.Condition {
+	if m.rules.Empty() {
+		return nil
+	}
+
+	sample := alerts.Sample{Time: snap.FetchedAt, Metrics: snap.Metrics}
+	if m.rules.NeedsDeviation() && m.queries != nil {
+		sample.Deviation = make(map[string]float64)
+		for _, window := range rollupWindows {
+			rows, err := m.queries.GetUsageMetrics(context.Background(), db.GetUsageMetricsParams{
+				OrganizationID: m.HistoryOrganization(),
+				ModelName:      m.model,
+				RegionID:       snap.Region,
+				TimeWindow:     window,
+				Limit:          1,
+			})
+			if err != nil || len(rows) == 0 || rows[0].DeviationPercentage == nil {
+				continue
+			}
+			if start, err := collector.BucketStart(window, snap.FetchedAt); err == nil && rows[0].Timestamp.Equal(start) {
+				sample.Deviation[window] = *rows[0].DeviationPercentage
+			}
+		}
+	}
+	snap.Rules = m.rules.Observe(sample)
+	return snap.Rules
+}
+
+// rollupWindows are aggregated into usage_metrics for history charts
+var rollupWindows = []string{collector.RollupHour, collector.RollupDay}
+
+// rollupInterval is how often the buckets in progress are re-aggregated
+const rollupInterval = time.Minute
+
+// rollup updates the usage_metrics rows of the buckets in progress at most
+// once per rollupInterval, and finalizes buckets completed since the last
+// update
+func (m *Monitor) rollup(ctx context.Context, now time.Time) {
+	if now.Sub(m.lastRollup) < rollupInterval {
+		return
+	}
+	organization, region := m.HistoryOrganization(), m.Region()
+	for _, window := range rollupWindows {
+		current, err := collector.BucketStart(window, now)
+		if err != nil {
+			continue
+		}
+		if previous, _ := collector.BucketStart(window, m.lastRollup); m.lastRollup.IsZero() || previous.Before(current) {
+			period, _ := collector.RollupPeriod(window)
+			_ = m.collector.Rollup(ctx, organization, m.model, region, window, current.Add(-period))
+		}
+		_ = m.collector.Rollup(ctx, organization, m.model, region, window, current)
+	}
+	m.lastRollup = now
+}
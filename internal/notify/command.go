@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command runs a shell command per notification. The notification is given
+// as JSON on stdin and as CEREBRAS_ALERT_* environment variables.
+type Command struct {
+	Command string
+}
+
+// Notify implements Notifier
+func (c *Command) Notify(ctx context.Context, n Notification) error {
+	input, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", c.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", c.Command)
+	}
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Env = append(os.Environ(), commandEnv(n)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return fmt.Errorf("%w: %s", err, detail)
+		}
+		return err
+	}
+	return nil
+}
+
+// commandEnv returns the environment describing the notification
+func commandEnv(n Notification) []string {
+	value := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	var at string
+	if !n.Time.IsZero() {
+		at = n.Time.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		"CEREBRAS_ALERT_TIME=" + at,
+		"CEREBRAS_ALERT_ORGANIZATION=" + n.Organization,
+		"CEREBRAS_ALERT_MODEL=" + n.Model,
+		"CEREBRAS_ALERT_TYPE=" + n.Type,
+		"CEREBRAS_ALERT_SEVERITY=" + n.Severity,
+		"CEREBRAS_ALERT_STATUS=" + n.Status,
+		"CEREBRAS_ALERT_METRIC=" + n.Metric,
+		"CEREBRAS_ALERT_VALUE=" + value(n.Value),
+		"CEREBRAS_ALERT_THRESHOLD=" + value(n.Threshold),
+		"CEREBRAS_ALERT_MESSAGE=" + n.Message,
+		"CEREBRAS_ALERT_TITLE=" + n.Title,
+		"CEREBRAS_ALERT_TEXT=" + n.Text,
+	}
+}
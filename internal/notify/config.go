@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// Notifier types accepted in the configuration
+const (
+	TypeWebhook    = "webhook"
+	TypeSlack      = FlavorSlack
+	TypeDiscord    = FlavorDiscord
+	TypeMattermost = FlavorMattermost
+	TypeNtfy       = "ntfy"
+	TypeSMTP       = "smtp"
+	TypeCommand    = "command"
+)
+
+// notifierEntry mirrors a notifiers entry in settings.yaml
+type notifierEntry struct {
+	Name        string            `mapstructure:"name"`
+	Type        string            `mapstructure:"type"`
+	MinSeverity string            `mapstructure:"min-severity"`
+	Title       string            `mapstructure:"title"`
+	Message     string            `mapstructure:"message"`
+	URL         string            `mapstructure:"url"`
+	Headers     map[string]string `mapstructure:"headers"`
+	Token       string            `mapstructure:"token"`
+	Host        string            `mapstructure:"host"`
+	Username    string            `mapstructure:"username"`
+	Password    string            `mapstructure:"password"`
+	From        string            `mapstructure:"from"`
+	To          []string          `mapstructure:"to"`
+	Command     string            `mapstructure:"command"`
+}
+
+// Load reads the notifiers section from the configuration:
+//
+//	notifiers:
+//	  - name: team-chat          # optional, defaults to the type
+//	    type: slack              # webhook, slack, discord, mattermost, ntfy, smtp or command
+//	    url: https://hooks.slack.com/services/...
+//	    min-severity: warning    # optional: info, warning or critical
+//	    title: "{{.Severity}}: {{.Type}}"  # optional Go templates over the event
+//	    message: "{{.Message}}"
+//
+// Delivery failures are logged to stderr when debug is set.
+func Load() (*Dispatcher, error) {
+	var entries []notifierEntry
+	if err := viper.UnmarshalKey("notifiers", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse notifiers: %w", err)
+	}
+
+	names := make(map[string]bool, len(entries))
+	targets := make([]*Target, 0, len(entries))
+	for i, e := range entries {
+		if e.Name == "" {
+			e.Name = e.Type
+		}
+		if names[e.Name] {
+			return nil, fmt.Errorf("notifiers[%d]: duplicate name %q", i, e.Name)
+		}
+		names[e.Name] = true
+
+		notifier, err := e.notifier()
+		if err != nil {
+			return nil, fmt.Errorf("notifiers[%d] (%s): %w", i, e.Name, err)
+		}
+		target, err := NewTarget(e.Name, e.MinSeverity, e.Title, e.Message, notifier)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers[%d] (%s): %w", i, e.Name, err)
+		}
+		targets = append(targets, target)
+	}
+
+	d := NewDispatcher(targets...)
+	if viper.GetBool("debug") {
+		d.Logf = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "Debug: "+format+"\n", args...)
+		}
+	}
+	return d, nil
+}
+
+// notifier validates the entry and creates its backend
+func (e notifierEntry) notifier() (Notifier, error) {
+	switch e.Type {
+	case TypeWebhook:
+		if err := validURL(e.URL); err != nil {
+			return nil, err
+		}
+		return &Webhook{URL: e.URL, Headers: e.Headers}, nil
+	case TypeSlack, TypeDiscord, TypeMattermost:
+		if err := validURL(e.URL); err != nil {
+			return nil, err
+		}
+		return &Chat{URL: e.URL, Flavor: e.Type}, nil
+	case TypeNtfy:
+		if err := validURL(e.URL); err != nil {
+			return nil, err
+		}
+		return &Ntfy{URL: e.URL, Token: e.Token}, nil
+	case TypeSMTP:
+		if _, _, err := net.SplitHostPort(e.Host); err != nil {
+			return nil, fmt.Errorf("host must be host:port, got %q", e.Host)
+		}
+		if e.From == "" || len(e.To) == 0 {
+			return nil, fmt.Errorf("smtp needs from and to addresses")
+		}
+		return &SMTP{Addr: e.Host, Username: e.Username, Password: e.Password, From: e.From, To: e.To}, nil
+	case TypeCommand:
+		if e.Command == "" {
+			return nil, fmt.Errorf("command is empty")
+		}
+		return &Command{Command: e.Command}, nil
+	case "":
+		return nil, fmt.Errorf("type is required")
+	default:
+		return nil, fmt.Errorf("unknown type %q: expected webhook, slack, discord, mattermost, ntfy, smtp or command", e.Type)
+	}
+}
+
+func validURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL, got %q", raw)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Chat webhook flavors
+const (
+	FlavorSlack      = "slack"
+	FlavorDiscord    = "discord"
+	FlavorMattermost = "mattermost"
+)
+
+// Webhook posts the notification as JSON: the event fields plus the
+// rendered title and text
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// Notify implements Notifier
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return post(ctx, w.Client, w.URL, "application/json", bytes.NewReader(body), w.Headers)
+}
+
+// Chat posts to a Slack, Discord or Mattermost incoming webhook
+type Chat struct {
+	URL    string
+	Flavor string
+	Client *http.Client
+}
+
+// Notify implements Notifier
+func (c *Chat) Notify(ctx context.Context, n Notification) error {
+	var payload map[string]string
+	switch c.Flavor {
+	case FlavorDiscord:
+		payload = map[string]string{"content": "**" + n.Title + "**\n" + n.Text}
+	case FlavorSlack, FlavorMattermost, "":
+		payload = map[string]string{"text": "*" + n.Title + "*\n" + n.Text}
+	default:
+		return fmt.Errorf("unknown chat flavor %q", c.Flavor)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return post(ctx, c.Client, c.URL, "application/json", bytes.NewReader(body), nil)
+}
+
+// Ntfy publishes to an ntfy topic URL such as https://ntfy.sh/my-alerts
+type Ntfy struct {
+	URL string
+	// Token is sent as a bearer token for protected topics
+	Token  string
+	Client *http.Client
+}
+
+// ntfyPriority maps severities to ntfy priorities (1-5)
+var ntfyPriority = map[string]string{
+	SeverityInfo:     "3",
+	SeverityWarning:  "4",
+	SeverityCritical: "5",
+}
+
+// Notify implements Notifier
+func (t *Ntfy) Notify(ctx context.Context, n Notification) error {
+	headers := map[string]string{
+		"Title": n.Title,
+		"Tags":  n.Severity,
+	}
+	if p, ok := ntfyPriority[n.Severity]; ok {
+		headers["Priority"] = p
+	}
+	if t.Token != "" {
+		headers["Authorization"] = "Bearer " + t.Token
+	}
+	return post(ctx, t.Client, t.URL, "text/plain; charset=utf-8", strings.NewReader(n.Text), headers)
+}
+
+// post sends a POST request and fails on a non-2xx status
+func post(ctx context.Context, client *http.Client, url, contentType string, body io.Reader, headers map[string]string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s %s", url, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package notify delivers alerts outside the dashboard: JSON webhooks,
+// Slack-compatible chat webhooks, ntfy, email and shell commands. Every
+// target filters by severity and renders its own title and message.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Alert statuses of an event
+const (
+	StatusFiring    = "firing"
+	StatusEscalated = "escalated"
+	StatusResolved  = "resolved"
+)
+
+// Alert severities, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// DefaultTimeout bounds the delivery of one notification to one target
+const DefaultTimeout = 30 * time.Second
+
+// Default templates of the title and message
+const (
+	DefaultTitle   = "[{{if eq .Status \"resolved\"}}resolved{{else}}{{.Severity}}{{end}}] Cerebras {{.Type}} alert"
+	DefaultMessage = "{{.Message}}{{if .Model}} ({{if .Organization}}{{.Organization}}, {{end}}{{.Model}}){{end}}"
+)
+
+// Event is an alert raised by the monitor
+type Event struct {
+	Time         time.Time `json:"time"`
+	Organization string    `json:"organization,omitempty"`
+	Model        string    `json:"model,omitempty"`
+	Type         string    `json:"type"`
+	Severity     string    `json:"severity"`
+	Metric       string    `json:"metric,omitempty"`
+	Value        float64   `json:"value"`
+	Threshold    float64   `json:"threshold"`
+	Message      string    `json:"message"`
+	// Status is "firing", "escalated" or "resolved"; empty for one-off events
+	Status string `json:"status,omitempty"`
+	// Notifiers limits delivery to the named targets; empty sends to all
+	Notifiers []string `json:"-"`
+}
+
+// Notification is an event with the title and text rendered for a target
+type Notification struct {
+	Event
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// Notifier delivers notifications to one backend
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// SeverityRank orders severities; unknown severities rank below info
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Target is a configured notifier with its filter and templates
+type Target struct {
+	Name string
+	// MinSeverity drops events below this severity; empty accepts all
+	MinSeverity string
+	Notifier    Notifier
+
+	title   *template.Template
+	message *template.Template
+}
+
+// NewTarget creates a target, parsing its title and message templates.
+// Empty templates use DefaultTitle and DefaultMessage.
+func NewTarget(name, minSeverity, title, message string, notifier Notifier) (*Target, error) {
+	if minSeverity != "" && SeverityRank(minSeverity) == 0 {
+		return nil, fmt.Errorf("unknown severity %q: expected info, warning or critical", minSeverity)
+	}
+	if title == "" {
+		title = DefaultTitle
+	}
+	if message == "" {
+		message = DefaultMessage
+	}
+	t := &Target{Name: name, MinSeverity: minSeverity, Notifier: notifier}
+	var err error
+	if t.title, err = template.New("title").Parse(title); err != nil {
+		return nil, fmt.Errorf("invalid title template: %w", err)
+	}
+	if t.message, err = template.New("message").Parse(message); err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	// Render a sample so unknown fields fail at load time
+	if _, err := t.Render(Event{Type: "test", Severity: SeverityInfo}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Accepts reports whether the event passes the severity filter and is
+// addressed to the target
+func (t *Target) Accepts(e Event) bool {
+	if len(e.Notifiers) > 0 && !slices.Contains(e.Notifiers, t.Name) {
+		return false
+	}
+	return SeverityRank(e.Severity) >= SeverityRank(t.MinSeverity)
+}
+
+// Render renders the title and text of the event
+func (t *Target) Render(e Event) (Notification, error) {
+	n := Notification{Event: e}
+	var buf bytes.Buffer
+	if err := t.title.Execute(&buf, e); err != nil {
+		return n, fmt.Errorf("rendering title: %w", err)
+	}
+	n.Title = buf.String()
+	buf.Reset()
+	if err := t.message.Execute(&buf, e); err != nil {
+		return n, fmt.Errorf("rendering message: %w", err)
+	}
+	n.Text = buf.String()
+	return n, nil
+}
+
+// Send renders the event and delivers it, ignoring the severity filter
+func (t *Target) Send(ctx context.Context, e Event) error {
+	n, err := t.Render(e)
+	if err != nil {
+		return err
+	}
+	return t.Notifier.Notify(ctx, n)
+}
+
+// Dispatcher fans events out to the targets that accept them
+type Dispatcher struct {
+	targets []*Target
+	// Timeout bounds each delivery, DefaultTimeout when zero
+	Timeout time.Duration
+	// Logf reports delivery failures; nil drops them
+	Logf func(format string, args ...interface{})
+}
+
+// NewDispatcher creates a dispatcher for the targets
+func NewDispatcher(targets ...*Target) *Dispatcher {
+	return &Dispatcher{targets: targets}
+}
+
+// Targets returns the configured targets
+func (d *Dispatcher) Targets() []*Target {
+	return d.targets
+}
+
+// Empty reports whether there is nowhere to deliver to
+func (d *Dispatcher) Empty() bool {
+	return d == nil || len(d.targets) == 0
+}
+
+// Dispatch delivers the events to every accepting target concurrently and
+// returns the joined delivery errors
+func (d *Dispatcher) Dispatch(ctx context.Context, events ...Event) error {
+	if d.Empty() {
+		return nil
+	}
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, t := range d.targets {
+		for _, e := range events {
+			if !t.Accepts(e) {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sendCtx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				if err := t.Send(sendCtx, e); err != nil {
+					err = fmt.Errorf("notifier %s: %w", t.Name, err)
+					if d.Logf != nil {
+						d.Logf("%v", err)
+					}
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,374 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var testEvent = Event{
+	Time:         time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC),
+	Organization: "org_1",
+	Model:        "qwen-3-coder-480b",
+	Type:         "soft_limit",
+	Severity:     SeverityWarning,
+	Metric:       "tokens-day",
+	Value:        64.5,
+	Threshold:    60,
+	Message:      "Soft limit crossed: 64.5% used, limit is 60% of tokens-day",
+}
+
+// recorder is a stand-in HTTP server keeping the last request
+type recorder struct {
+	mu     sync.Mutex
+	header http.Header
+	body   string
+	status int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.header, r.body = req.Header, string(body)
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+	}
+}
+
+func newTarget(t *testing.T, minSeverity, title, message string, n Notifier) *Target {
+	t.Helper()
+	target, err := NewTarget("test", minSeverity, title, message, n)
+	if err != nil {
+		t.Fatalf("NewTarget: %v", err)
+	}
+	return target
+}
+
+func TestRender(t *testing.T) {
+	target := newTarget(t, "", "", "", nil)
+	n, err := target.Render(testEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Title != "[warning] Cerebras soft_limit alert" {
+		t.Errorf("Unexpected default title %q", n.Title)
+	}
+	if n.Text != testEvent.Message+" (org_1, qwen-3-coder-480b)" {
+		t.Errorf("Unexpected default message %q", n.Text)
+	}
+
+	custom := newTarget(t, "", "{{.Metric}} at {{printf \"%.0f\" .Value}}%", "{{.Severity}}", nil)
+	if n, _ := custom.Render(testEvent); n.Title != "tokens-day at 64%" || n.Text != "warning" {
+		t.Errorf("Unexpected custom rendering: %+v", n)
+	}
+
+	if _, err := NewTarget("bad", "", "{{.Nope}}", "", nil); err == nil {
+		t.Error("Expected an unknown field to fail at load time")
+	}
+	if _, err := NewTarget("bad", "urgent", "", "", nil); err == nil {
+		t.Error("Expected an unknown severity to fail")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	rec := &recorder{}
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+
+	target := newTarget(t, "", "", "", &Webhook{URL: ts.URL, Headers: map[string]string{"X-Token": "secret"}})
+	if err := target.Send(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	var got Notification
+	if err := json.Unmarshal([]byte(rec.body), &got); err != nil {
+		t.Fatalf("Decoding %q: %v", rec.body, err)
+	}
+	if got.Metric != "tokens-day" || got.Value != 64.5 || got.Title == "" || got.Text == "" {
+		t.Errorf("Expected the event with rendered title and text, got %+v", got)
+	}
+	if rec.header.Get("X-Token") != "secret" || rec.header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected headers: %v", rec.header)
+	}
+
+	rec.status = http.StatusForbidden
+	if err := target.Send(context.Background(), testEvent); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the status in the error, got %v", err)
+	}
+}
+
+func TestChat(t *testing.T) {
+	rec := &recorder{}
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+
+	tests := []struct {
+		flavor string
+		field  string
+		want   string
+	}{
+		{FlavorSlack, "text", "*T*\nM"},
+		{FlavorMattermost, "text", "*T*\nM"},
+		{FlavorDiscord, "content", "**T**\nM"},
+	}
+	for _, tt := range tests {
+		target := newTarget(t, "", "T", "M", &Chat{URL: ts.URL, Flavor: tt.flavor})
+		if err := target.Send(context.Background(), testEvent); err != nil {
+			t.Fatalf("%s: %v", tt.flavor, err)
+		}
+		var payload map[string]string
+		if err := json.Unmarshal([]byte(rec.body), &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload[tt.field] != tt.want {
+			t.Errorf("%s: expected %s %q, got %v", tt.flavor, tt.field, tt.want, payload)
+		}
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	rec := &recorder{}
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+
+	event := testEvent
+	event.Severity = SeverityCritical
+	target := newTarget(t, "", "Title", "Body", &Ntfy{URL: ts.URL + "/alerts", Token: "tk"})
+	if err := target.Send(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if rec.body != "Body" || rec.header.Get("Title") != "Title" || rec.header.Get("Priority") != "5" ||
+		rec.header.Get("Tags") != "critical" || rec.header.Get("Authorization") != "Bearer tk" {
+		t.Errorf("Unexpected ntfy request: %q %v", rec.body, rec.header)
+	}
+}
+
+// fakeSMTP accepts one message and returns its envelope and data
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		r := bufio.NewReader(conn)
+		reply := func(s string) {
+			_, _ = io.WriteString(conn, s+"\r\n")
+		}
+		var transcript strings.Builder
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+					continue
+				}
+				transcript.WriteString(line)
+				continue
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(cmd, "AUTH"):
+				transcript.WriteString(line)
+				reply("235 OK")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				transcript.WriteString(line)
+				reply("250 OK")
+			case cmd == "DATA":
+				inData = true
+				reply("354 Go ahead")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func TestSMTP(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	target := newTarget(t, "", "Quota ☕ alert", "Line one\nLine two", &SMTP{
+		Addr:     addr,
+		Username: "user",
+		Password: "pass",
+		From:     "monitor@example.com",
+		To:       []string{"a@example.com", "b@example.com"},
+	})
+	if err := target.Send(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-received:
+		for _, want := range []string{
+			"AUTH PLAIN",
+			"MAIL FROM:<monitor@example.com>",
+			"RCPT TO:<a@example.com>",
+			"RCPT TO:<b@example.com>",
+			"To: a@example.com, b@example.com",
+			"Subject: =?utf-8?q?Quota_=E2=98=95_alert?=",
+			"Line one\r\nLine two",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Expected %q in the session:\n%s", want, got)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the message to be delivered")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	target := newTarget(t, "", "", "", &Command{
+		Command: `cat > "$OUT"; echo "$CEREBRAS_ALERT_SEVERITY $CEREBRAS_ALERT_METRIC $CEREBRAS_ALERT_VALUE" >> "$OUT"`,
+	})
+	t.Setenv("OUT", out)
+	if err := target.Send(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin, env, _ := strings.Cut(string(data), "\n")
+	var got Notification
+	if err := json.Unmarshal([]byte(stdin), &got); err != nil || got.Model != testEvent.Model {
+		t.Errorf("Expected the notification as JSON on stdin, got %q (%v)", stdin, err)
+	}
+	if strings.TrimSpace(env) != "warning tokens-day 64.5" {
+		t.Errorf("Unexpected environment: %q", env)
+	}
+
+	failing := newTarget(t, "", "", "", &Command{Command: "echo broken >&2; exit 3"})
+	if err := failing.Send(context.Background(), testEvent); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected the command's stderr in the error, got %v", err)
+	}
+}
+
+// countingNotifier counts deliveries
+type countingNotifier struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (c *countingNotifier) Notify(ctx context.Context, n Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	all, critical := &countingNotifier{}, &countingNotifier{}
+	d := NewDispatcher(
+		newTarget(t, "", "", "", all),
+		newTarget(t, SeverityCritical, "", "", critical),
+	)
+	warning := testEvent
+	crit := testEvent
+	crit.Severity = SeverityCritical
+	if err := d.Dispatch(context.Background(), warning, crit); err != nil {
+		t.Fatal(err)
+	}
+	if all.count != 2 || critical.count != 1 {
+		t.Errorf("Expected the severity filter to apply, got %d and %d", all.count, critical.count)
+	}
+
+	ts := httptest.NewServer(&recorder{status: http.StatusInternalServerError})
+	defer ts.Close()
+	var logged []string
+	failing := NewDispatcher(newTarget(t, "", "", "", &Webhook{URL: ts.URL}))
+	failing.Logf = func(format string, args ...interface{}) {
+		logged = append(logged, format)
+	}
+	if err := failing.Dispatch(context.Background(), warning); err == nil || len(logged) != 1 {
+		t.Errorf("Expected the failure returned and logged, got %v, %v", err, logged)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("notifiers", []map[string]interface{}{
+		{"type": "slack", "url": "https://hooks.slack.com/services/x", "min-severity": "critical"},
+		{"name": "mail", "type": "smtp", "host": "smtp.example.com:587", "from": "a@example.com", "to": []string{"b@example.com"}},
+		{"name": "hook", "type": "command", "command": "true"},
+	})
+	d, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	targets := d.Targets()
+	if len(targets) != 3 || targets[0].Name != "slack" || targets[0].MinSeverity != SeverityCritical || targets[1].Name != "mail" {
+		t.Errorf("Unexpected targets: %+v", targets)
+	}
+
+	tests := []struct {
+		name  string
+		entry map[string]interface{}
+		want  string
+	}{
+		{"missing type", map[string]interface{}{"url": "https://x"}, "type is required"},
+		{"unknown type", map[string]interface{}{"type": "pager"}, "unknown type"},
+		{"bad url", map[string]interface{}{"type": "webhook", "url": "ftp://x"}, "url must be"},
+		{"smtp host", map[string]interface{}{"type": "smtp", "host": "smtp.example.com"}, "host:port"},
+		{"smtp to", map[string]interface{}{"type": "smtp", "host": "h:25", "from": "a@b"}, "from and to"},
+		{"template", map[string]interface{}{"type": "ntfy", "url": "https://ntfy.sh/x", "message": "{{.Missing}}"}, "rendering message"},
+	}
+	for _, tt := range tests {
+		viper.Reset()
+		viper.Set("notifiers", []map[string]interface{}{tt.entry})
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	viper.Reset()
+	viper.Set("notifiers", []map[string]interface{}{
+		{"type": "command", "command": "true"},
+		{"type": "command", "command": "false"},
+	})
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("Expected duplicate names to fail, got %v", err)
+	}
+}
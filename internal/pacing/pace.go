@@ -0,0 +1,83 @@
+package pacing
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+// onPaceTolerance is the deviation, in percentage points, still reported as on pace
+const onPaceTolerance = 1.0
+
+// Pace compares the share of a window's limit already used with the share of
+// the window that has elapsed
+type Pace struct {
+	Window   string
+	Actual   float64 // percent of the limit used
+	Expected float64 // percent of the window elapsed
+}
+
+// Delta returns how many percentage points usage is ahead (positive) or
+// behind (negative) an even burn across the window
+func (p Pace) Delta() float64 {
+	return p.Actual - p.Expected
+}
+
+// String renders the pace as "12% ahead of pace", "8% behind pace" or "on pace"
+func (p Pace) String() string {
+	delta := p.Delta()
+	switch {
+	case delta > onPaceTolerance:
+		return fmt.Sprintf("%.0f%% ahead of pace", delta)
+	case delta < -onPaceTolerance:
+		return fmt.Sprintf("%.0f%% behind pace", math.Abs(delta))
+	default:
+		return "on pace"
+	}
+}
+
+// ForWindow computes the pace of a window. The elapsed share comes from the
+// window's reset countdown when known, and otherwise from the UTC clock
+// boundary of the window period. It returns false when the limit is unknown.
+func ForWindow(w cerebras.WindowUsage, now time.Time) (Pace, bool) {
+	if w.Limit <= 0 {
+		return Pace{}, false
+	}
+	period, untilReset, err := timing(w, now)
+	if err != nil {
+		return Pace{}, false
+	}
+
+	elapsed := 1 - untilReset.Seconds()/period.Seconds()
+	elapsed = math.Max(0, math.Min(1, elapsed))
+
+	return Pace{
+		Window:   w.Name,
+		Actual:   w.Percent(),
+		Expected: elapsed * 100,
+	}, true
+}
+
+// timing returns the period of a window and the time left until it resets,
+// taken from the reset countdown when known and otherwise from the UTC clock
+// boundary of the period
+func timing(w cerebras.WindowUsage, now time.Time) (period, untilReset time.Duration, err error) {
+	period, err = cerebras.WindowPeriod(w.Name)
+	if err != nil {
+		return 0, 0, err
+	}
+	untilReset = time.Duration(w.Reset) * time.Second
+	if w.Reset <= 0 {
+		untilReset = untilBoundary(now, period)
+	}
+	return period, untilReset, nil
+}
+
+// untilBoundary returns the time left until the next UTC multiple of period
+func untilBoundary(now time.Time, period time.Duration) time.Duration {
+	now = now.UTC()
+	next := now.Truncate(period).Add(period)
+	return next.Sub(now)
+}
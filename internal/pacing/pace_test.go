@@ -0,0 +1,59 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+func TestForWindow(t *testing.T) {
+	noon := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		window   cerebras.WindowUsage
+		expected float64
+		label    string
+	}{
+		{
+			name:     "ahead using reset countdown",
+			window:   cerebras.WindowUsage{Name: cerebras.WindowTokensDay, Used: 62, Limit: 100, Reset: 12 * 3600},
+			expected: 50,
+			label:    "12% ahead of pace",
+		},
+		{
+			name:     "behind using UTC day boundary",
+			window:   cerebras.WindowUsage{Name: cerebras.WindowTokensDay, Used: 42, Limit: 100},
+			expected: 50,
+			label:    "8% behind pace",
+		},
+		{
+			name:     "on pace within tolerance",
+			window:   cerebras.WindowUsage{Name: cerebras.WindowRequestsHour, Used: 25, Limit: 100, Reset: 45 * 60},
+			expected: 25,
+			label:    "on pace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pace, ok := ForWindow(tt.window, noon)
+			if !ok {
+				t.Fatal("Expected pace to be computed")
+			}
+			if pace.Expected != tt.expected {
+				t.Errorf("Expected %.1f%% elapsed, got %.1f%%", tt.expected, pace.Expected)
+			}
+			if pace.String() != tt.label {
+				t.Errorf("Expected %q, got %q", tt.label, pace.String())
+			}
+		})
+	}
+}
+
+func TestForWindowUnknownLimit(t *testing.T) {
+	if _, ok := ForWindow(cerebras.WindowUsage{Name: cerebras.WindowTokensDay, Used: 10}, time.Now()); ok {
+		t.Error("Expected no pace without a limit")
+	}
+}
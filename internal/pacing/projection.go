@@ -0,0 +1,68 @@
+package pacing
+
+import (
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+// Projection extrapolates the usage of a window at a constant rate until
+// the window resets
+type Projection struct {
+	Window    string
+	Remaining int64
+	// Rate is the assumed usage per minute
+	Rate float64
+	// ResetsIn is the time left until the window resets
+	ResetsIn time.Duration
+	// ExhaustsIn is the time until the limit is used up at Rate: 0 when it
+	// already is, negative when Rate is 0
+	ExhaustsIn time.Duration
+	// Exhausts reports whether the limit runs out before the window resets
+	Exhausts bool
+}
+
+// AverageRate returns the usage per minute of a window since it started,
+// with the start derived as in ForWindow. It returns false when too little
+// of the window has elapsed to tell.
+func AverageRate(w cerebras.WindowUsage, now time.Time) (float64, bool) {
+	period, untilReset, err := timing(w, now)
+	if err != nil {
+		return 0, false
+	}
+	elapsed := period - untilReset
+	if elapsed < time.Second {
+		return 0, false
+	}
+	return float64(w.Used) / elapsed.Minutes(), true
+}
+
+// Project extrapolates a window at rate units per minute. It returns false
+// when the limit is unknown or unlimited.
+func Project(w cerebras.WindowUsage, rate float64, now time.Time) (Projection, bool) {
+	if _, ok := w.Limit.Value(); !ok {
+		return Projection{}, false
+	}
+	_, untilReset, err := timing(w, now)
+	if err != nil {
+		return Projection{}, false
+	}
+
+	p := Projection{
+		Window:    w.Name,
+		Remaining: w.Remaining,
+		Rate:      rate,
+		ResetsIn:  untilReset,
+	}
+	switch {
+	case w.Remaining <= 0:
+		p.ExhaustsIn = 0
+		p.Exhausts = true
+	case rate <= 0:
+		p.ExhaustsIn = -1
+	default:
+		p.ExhaustsIn = time.Duration(float64(w.Remaining) / rate * float64(time.Minute))
+		p.Exhausts = p.ExhaustsIn < untilReset
+	}
+	return p, true
+}
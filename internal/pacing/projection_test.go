@@ -0,0 +1,40 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+)
+
+func TestProject(t *testing.T) {
+	noon := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	// Half of the day gone and 600 of 1000 tokens used: 600 per 720 minutes
+	w := cerebras.WindowUsage{Name: cerebras.WindowTokensDay, Used: 600, Limit: 1000, Remaining: 400}
+
+	rate, ok := AverageRate(w, noon)
+	if !ok || rate != 600.0/720 {
+		t.Fatalf("Expected 600/720 tokens per minute, got %v, %v", rate, ok)
+	}
+	p, ok := Project(w, rate, noon)
+	if !ok {
+		t.Fatal("Expected a projection")
+	}
+	if p.ResetsIn != 12*time.Hour || p.ExhaustsIn != 8*time.Hour || !p.Exhausts {
+		t.Errorf("Expected exhaustion in 8h before the reset in 12h, got %v and %v", p.ExhaustsIn, p.ResetsIn)
+	}
+
+	if p, _ := Project(w, 0.1, noon); p.Exhausts {
+		t.Errorf("Expected a slow rate to last until the reset, got exhaustion in %v", p.ExhaustsIn)
+	}
+	if p, _ := Project(w, 0, noon); p.Exhausts || p.ExhaustsIn >= 0 {
+		t.Errorf("Expected no exhaustion without usage, got %v", p.ExhaustsIn)
+	}
+	spent := cerebras.WindowUsage{Name: cerebras.WindowRequestsMinute, Used: 30, Limit: 30, Reset: 20}
+	if p, _ := Project(spent, 1, noon); !p.Exhausts || p.ExhaustsIn != 0 || p.ResetsIn != 20*time.Second {
+		t.Errorf("Expected an exhausted window, got %+v", p)
+	}
+	if _, ok := Project(cerebras.WindowUsage{Name: cerebras.WindowTokensDay, Limit: cerebras.LimitUnlimited}, 1, noon); ok {
+		t.Error("Expected no projection for an unlimited window")
+	}
+}
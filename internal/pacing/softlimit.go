@@ -0,0 +1,129 @@
+package pacing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/spf13/viper"
+)
+
+// SoftLimit is a self-imposed ceiling below the hard quota, e.g. "use at most
+// 60% of tokens/day before 15:00"
+type SoftLimit struct {
+	Window       string        `json:"window"`
+	MaxPercent   float64       `json:"max_percent"`
+	Before       time.Duration `json:"before,omitempty"`       // time of day the limit stops applying, 0 for always
+	Organization string        `json:"organization,omitempty"` // empty matches every organization
+	Model        string        `json:"model,omitempty"`        // empty matches every model
+}
+
+// Applies reports whether the limit covers the organization and model
+func (l SoftLimit) Applies(organization, model string) bool {
+	return (l.Organization == "" || l.Organization == organization) &&
+		(l.Model == "" || l.Model == model)
+}
+
+// Active reports whether the limit is in effect at the given local time
+func (l SoftLimit) Active(now time.Time) bool {
+	if l.Before == 0 {
+		return true
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return now.Sub(midnight) < l.Before
+}
+
+// String describes the limit, e.g. "60% of tokens-day before 15:00"
+func (l SoftLimit) String() string {
+	s := fmt.Sprintf("%.0f%% of %s", l.MaxPercent, l.Window)
+	if l.Before > 0 {
+		s += fmt.Sprintf(" before %02d:%02d", int(l.Before.Hours()), int(l.Before.Minutes())%60)
+	}
+	return s
+}
+
+// Breach is a soft limit exceeded by the current usage
+type Breach struct {
+	Limit   SoftLimit `json:"limit"`
+	Percent float64   `json:"percent"`
+}
+
+// Message returns a human-readable description of the breach
+func (b Breach) Message() string {
+	return fmt.Sprintf("Soft limit crossed: %.1f%% used, limit is %s", b.Percent, b.Limit)
+}
+
+// Evaluate returns the soft limits exceeded by the metrics. now must be in the
+// configured timezone so "before" times match the user's clock.
+func Evaluate(limits []SoftLimit, organization, model string, info *cerebras.RateLimitInfo, now time.Time) []Breach {
+	if info == nil {
+		return nil
+	}
+
+	var breaches []Breach
+	for _, l := range limits {
+		if !l.Applies(organization, model) || !l.Active(now) {
+			continue
+		}
+		w, err := info.Window(l.Window)
+		if err != nil || w.Limit <= 0 {
+			continue
+		}
+		if percent := w.Percent(); percent >= l.MaxPercent {
+			breaches = append(breaches, Breach{Limit: l, Percent: percent})
+		}
+	}
+
+	return breaches
+}
+
+// softLimitEntry mirrors a soft-limits entry in settings.yaml
+type softLimitEntry struct {
+	Window       string  `mapstructure:"window"`
+	MaxPercent   float64 `mapstructure:"max-percent"`
+	Before       string  `mapstructure:"before"`
+	Organization string  `mapstructure:"organization"`
+	Model        string  `mapstructure:"model"`
+}
+
+// LoadSoftLimits reads the soft-limits section from the configuration:
+//
+//	soft-limits:
+//	  - window: tokens-day
+//	    max-percent: 60
+//	    before: "15:00"
+//	    organization: org_123   # optional
+//	    model: qwen-3-coder-480b # optional
+func LoadSoftLimits() ([]SoftLimit, error) {
+	var entries []softLimitEntry
+	if err := viper.UnmarshalKey("soft-limits", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse soft-limits: %w", err)
+	}
+
+	limits := make([]SoftLimit, 0, len(entries))
+	for i, e := range entries {
+		if _, err := cerebras.WindowPeriod(e.Window); err != nil {
+			return nil, fmt.Errorf("soft-limits[%d]: %w", i, err)
+		}
+		if e.MaxPercent <= 0 || e.MaxPercent > 100 {
+			return nil, fmt.Errorf("soft-limits[%d]: max-percent must be between 0 and 100, got %v", i, e.MaxPercent)
+		}
+
+		limit := SoftLimit{
+			Window:       e.Window,
+			MaxPercent:   e.MaxPercent,
+			Organization: e.Organization,
+			Model:        e.Model,
+		}
+		if e.Before != "" {
+			t, err := time.Parse("15:04", e.Before)
+			if err != nil {
+				return nil, fmt.Errorf("soft-limits[%d]: invalid before %q, expected HH:MM", i, e.Before)
+			}
+			limit.Before = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		}
+		limits = append(limits, limit)
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,72 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/spf13/viper"
+)
+
+func TestLoadSoftLimits(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("soft-limits", []map[string]interface{}{
+		{"window": "tokens-day", "max-percent": 60, "before": "15:00"},
+		{"window": "requests-minute", "max-percent": 80, "model": "llama-4"},
+	})
+
+	limits, err := LoadSoftLimits()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(limits) != 2 {
+		t.Fatalf("Expected 2 limits, got %d", len(limits))
+	}
+	if limits[0].Before != 15*time.Hour {
+		t.Errorf("Expected before 15h, got %v", limits[0].Before)
+	}
+	if limits[1].Model != "llama-4" || limits[1].Before != 0 {
+		t.Errorf("Unexpected second limit: %+v", limits[1])
+	}
+}
+
+func TestLoadSoftLimitsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry map[string]interface{}
+	}{
+		{name: "unknown window", entry: map[string]interface{}{"window": "tokens-week", "max-percent": 60}},
+		{name: "percent out of range", entry: map[string]interface{}{"window": "tokens-day", "max-percent": 120}},
+		{name: "bad before", entry: map[string]interface{}{"window": "tokens-day", "max-percent": 60, "before": "3pm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("soft-limits", []map[string]interface{}{tt.entry})
+
+			if _, err := LoadSoftLimits(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	limits := []SoftLimit{{Window: cerebras.WindowTokensDay, MaxPercent: 60, Before: 15 * time.Hour}}
+	info := &cerebras.RateLimitInfo{LimitTokensDay: 1000, RemainingTokensDay: 300}
+
+	morning := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+	breaches := Evaluate(limits, "org", "model", info, morning)
+	if len(breaches) != 1 || breaches[0].Percent != 70 {
+		t.Fatalf("Expected one breach at 70%%, got %+v", breaches)
+	}
+
+	evening := time.Date(2025, 8, 10, 16, 0, 0, 0, time.UTC)
+	if breaches := Evaluate(limits, "org", "model", info, evening); len(breaches) != 0 {
+		t.Errorf("Expected no breaches after the cut-off, got %+v", breaches)
+	}
+}
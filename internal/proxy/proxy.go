@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+// Source is the request_usage source of the rows the proxy records
+const Source = "proxy"
+
+// maxRecordedBody bounds how much of a response is kept to find its usage;
+// larger responses are forwarded but not recorded
+const maxRecordedBody = 8 << 20
+
+// Store records the token usage of proxied requests
+type Store interface {
+	InsertRequestUsage(ctx context.Context, arg db.InsertRequestUsageParams) error
+}
+
+// Proxy forwards OpenAI-compatible requests to the Cerebras API unchanged and
+// records the prompt/completion split of every response that reports usage,
+// so costs can be priced per direction instead of with a blended rate. The
+// rate limit headers of those responses are captured for the API-key poller,
+// which then needs no probe of its own.
+type Proxy struct {
+	store        Store
+	organization string
+	proxy        *httputil.ReverseProxy
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// New creates a proxy to target recording usage under the organization
+func New(target *url.URL, store Store, organization string) *Proxy {
+	p := &Proxy{store: store, organization: organization, now: time.Now}
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.Host = target.Host
+		},
+		// Flush every write so streamed completions are not delayed
+		FlushInterval: -1,
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Body = &recordingBody{
+				ReadCloser:  resp.Body,
+				proxy:       p,
+				header:      resp.Header.Clone(),
+				receivedAt:  p.now(),
+				eventStream: strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"),
+			}
+			return nil
+		},
+	}
+	return p
+}
+
+// ServeHTTP forwards the request
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.proxy.ServeHTTP(w, r)
+}
+
+// completionUsage is the part of a completion response, or of the chunk of a
+// streamed one, that carries its usage
+type completionUsage struct {
+	Model string `json:"model"`
+	Usage *struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// recordingBody keeps a copy of the response as it is forwarded and records
+// its usage when the client is done with it
+type recordingBody struct {
+	io.ReadCloser
+	proxy       *Proxy
+	header      http.Header
+	receivedAt  time.Time
+	eventStream bool
+	buf         bytes.Buffer
+	truncated   bool
+	recorded    bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && !b.truncated {
+		if b.buf.Len()+n > maxRecordedBody {
+			b.truncated = true
+			b.buf.Reset()
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	if !b.recorded && !b.truncated {
+		b.recorded = true
+		if usage, ok := parseUsage(b.buf.Bytes(), b.eventStream); ok {
+			b.proxy.record(usage)
+			b.proxy.capture(usage.Model, b.header, b.receivedAt)
+		}
+	}
+	return err
+}
+
+// record stores the usage of one response; failures only lose the split
+func (p *Proxy) record(usage completionUsage) {
+	_ = p.store.InsertRequestUsage(context.Background(), db.InsertRequestUsageParams{
+		Timestamp:        p.now().UTC().Truncate(time.Second),
+		OrganizationID:   p.organization,
+		ModelName:        usage.Model,
+		PromptTokens:     usage.Usage.PromptTokens,
+		CompletionTokens: usage.Usage.CompletionTokens,
+		Source:           Source,
+	})
+}
+
+// capture stores the rate limit headers of a completion response, if any,
+// for pollers of the model; failures only cost them a probe
+func (p *Proxy) capture(model string, header http.Header, at time.Time) {
+	if !cerebras.ParseRateLimitHeaders(header).Found {
+		return
+	}
+	_ = cerebras.SaveCapturedHeaders(model, header, at)
+}
+
+// parseUsage finds the usage of a JSON response, or of the last chunk of an
+// event stream that reports one
+func parseUsage(body []byte, eventStream bool) (completionUsage, bool) {
+	var found completionUsage
+	ok := false
+	decode := func(data []byte) {
+		var c completionUsage
+		if json.Unmarshal(data, &c) == nil && c.Usage != nil && c.Model != "" {
+			found, ok = c, true
+		}
+	}
+	if !eventStream {
+		decode(body)
+		return found, ok
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64<<10), maxRecordedBody)
+	for scanner.Scan() {
+		if data, isData := strings.CutPrefix(scanner.Text(), "data:"); isData {
+			decode([]byte(strings.TrimSpace(data)))
+		}
+	}
+	return found, ok
+}
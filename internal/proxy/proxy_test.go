@@ -0,0 +1,149 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+type fakeStore struct {
+	rows []db.InsertRequestUsageParams
+}
+
+func (s *fakeStore) InsertRequestUsage(_ context.Context, arg db.InsertRequestUsageParams) error {
+	s.rows = append(s.rows, arg)
+	return nil
+}
+
+// proxyTo starts a proxy in front of an upstream answering every request
+// with the content type and body
+func proxyTo(t *testing.T, contentType, body string) (*httptest.Server, *fakeStore) {
+	t.Helper()
+	return proxyWithHeaders(t, http.Header{"Content-Type": {contentType}}, body)
+}
+
+// proxyWithHeaders starts a proxy in front of an upstream answering every
+// request with the headers and body. Captured headers go to a temporary home.
+func proxyWithHeaders(t *testing.T, header http.Header, body string) (*httptest.Server, *fakeStore) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, _ := url.Parse(upstream.URL)
+	store := &fakeStore{}
+	p := New(target, store, "org_1")
+	p.now = func() time.Time { return time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC) }
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+	return server, store
+}
+
+// post sends a completion request through the proxy and waits for it to finish
+func post(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/chat/completions", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	// Usage is recorded once the proxy is done with the upstream response,
+	// which can be after the client has read it
+	server.Close()
+	return string(body)
+}
+
+func TestProxyRecordsCompletionUsage(t *testing.T) {
+	body := `{"model":"qwen-3-coder-480b","usage":{"prompt_tokens":120,"completion_tokens":30,"total_tokens":150}}`
+	server, store := proxyTo(t, "application/json", body)
+
+	if got := post(t, server); got != body {
+		t.Errorf("forwarded body = %q, want %q", got, body)
+	}
+	if len(store.rows) != 1 {
+		t.Fatalf("recorded %d rows, want 1", len(store.rows))
+	}
+	want := db.InsertRequestUsageParams{
+		Timestamp:        time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC),
+		OrganizationID:   "org_1",
+		ModelName:        "qwen-3-coder-480b",
+		PromptTokens:     120,
+		CompletionTokens: 30,
+		Source:           Source,
+	}
+	if store.rows[0] != want {
+		t.Errorf("recorded %+v, want %+v", store.rows[0], want)
+	}
+}
+
+func TestProxyRecordsStreamedUsage(t *testing.T) {
+	body := "data: {\"model\":\"qwen-3-coder-480b\",\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n" +
+		"data: {\"model\":\"qwen-3-coder-480b\",\"choices\":[],\"usage\":{\"prompt_tokens\":50,\"completion_tokens\":7}}\n\n" +
+		"data: [DONE]\n\n"
+	server, store := proxyTo(t, "text/event-stream", body)
+
+	if got := post(t, server); got != body {
+		t.Errorf("forwarded body = %q, want %q", got, body)
+	}
+	if len(store.rows) != 1 {
+		t.Fatalf("recorded %d rows, want 1", len(store.rows))
+	}
+	if got := store.rows[0]; got.PromptTokens != 50 || got.CompletionTokens != 7 {
+		t.Errorf("recorded %d/%d tokens, want 50/7", got.PromptTokens, got.CompletionTokens)
+	}
+}
+
+func TestProxySkipsResponsesWithoutUsage(t *testing.T) {
+	server, store := proxyTo(t, "application/json", `{"object":"list","data":[]}`)
+	post(t, server)
+	if len(store.rows) != 0 {
+		t.Errorf("recorded %d rows for a response without usage", len(store.rows))
+	}
+}
+
+func TestProxyCapturesRateLimitHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Ratelimit-Limit-Tokens-Minute", "1000")
+	header.Set("X-Ratelimit-Remaining-Tokens-Minute", "400")
+	server, _ := proxyWithHeaders(t, header, `{"model":"qwen-3-coder-480b","usage":{"prompt_tokens":1,"completion_tokens":1}}`)
+	post(t, server)
+
+	now := time.Date(2025, 8, 1, 12, 0, 10, 0, time.UTC)
+	captured, ok := cerebras.LoadCapturedHeaders("qwen-3-coder-480b", time.Minute, now)
+	if !ok {
+		t.Fatal("expected the rate limit headers to be captured")
+	}
+	if got := captured.Info(now).Info.UsageTokensMinute; got != 600 {
+		t.Errorf("captured UsageTokensMinute = %d, want 600", got)
+	}
+}
+
+func TestProxySkipsResponsesWithoutRateLimitHeaders(t *testing.T) {
+	server, _ := proxyTo(t, "application/json", `{"model":"qwen-3-coder-480b","usage":{"prompt_tokens":1,"completion_tokens":1}}`)
+	post(t, server)
+
+	if _, ok := cerebras.LoadCapturedHeaders("qwen-3-coder-480b", time.Hour, time.Now()); ok {
+		t.Error("captured a response without rate limit headers")
+	}
+}
@@ -0,0 +1,377 @@
+package resets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+	"github.com/spf13/viper"
+)
+
+// HistoryWindow is how far back snapshot history is scanned for counter drops
+const HistoryWindow = 7 * 24 * time.Hour
+
+// Confidence describes where a reset time comes from
+type Confidence int
+
+const (
+	// ConfidenceAssumed comes from the configured boundary rule
+	ConfidenceAssumed Confidence = iota
+	// ConfidenceObserved is learned from counter drops or earlier headers
+	ConfidenceObserved
+	// ConfidenceReported comes straight from the current response headers
+	ConfidenceReported
+)
+
+// Marker returns the symbol shown next to a reset time: nothing for reported
+// resets, "~" for observed ones and "?" for assumed ones
+func (c Confidence) Marker() string {
+	switch c {
+	case ConfidenceReported:
+		return ""
+	case ConfidenceObserved:
+		return "~"
+	default:
+		return "?"
+	}
+}
+
+// String returns the confidence name
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceReported:
+		return "reported"
+	case ConfidenceObserved:
+		return "observed"
+	default:
+		return "assumed"
+	}
+}
+
+// MarshalText encodes the confidence by name
+func (c Confidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a confidence name
+func (c *Confidence) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "reported":
+		*c = ConfidenceReported
+	case "observed":
+		*c = ConfidenceObserved
+	case "assumed":
+		*c = ConfidenceAssumed
+	default:
+		return fmt.Errorf("unknown confidence %q", text)
+	}
+	return nil
+}
+
+// Estimate is the next reset of a rate limit window
+type Estimate struct {
+	Window     string     `json:"window"`
+	At         time.Time  `json:"at"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// Seconds returns the whole seconds until the reset, at least 1
+func (e Estimate) Seconds(now time.Time) int64 {
+	secs := int64(e.At.Sub(now).Round(time.Second) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
+
+// Rule is the fallback boundary rule: windows reset on clock boundaries in
+// Location, with daily windows resetting at DayStart past midnight
+type Rule struct {
+	Location *time.Location
+	DayStart time.Duration
+}
+
+// DefaultRule resets daily windows at UTC midnight
+func DefaultRule() Rule {
+	return Rule{Location: time.UTC}
+}
+
+// LoadRule reads the resets section from the configuration:
+//
+//	resets:
+//	  timezone: UTC      # timezone of the window boundaries
+//	  day-start: "00:00" # time of day the daily windows reset
+func LoadRule() (Rule, error) {
+	rule := DefaultRule()
+
+	if tz := viper.GetString("resets.timezone"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid resets.timezone %q: %w", tz, err)
+		}
+		rule.Location = loc
+	}
+	if start := viper.GetString("resets.day-start"); start != "" {
+		t, err := time.Parse("15:04", start)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid resets.day-start %q, expected HH:MM", start)
+		}
+		rule.DayStart = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	return rule, nil
+}
+
+// Next returns the next boundary of the window strictly after now
+func (r Rule) Next(window string, now time.Time) (time.Time, error) {
+	period, err := cerebras.WindowPeriod(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := now.In(r.Location)
+	var anchor time.Time
+	switch period {
+	case time.Minute:
+		anchor = time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, r.Location)
+	case time.Hour:
+		anchor = time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, r.Location)
+	default:
+		anchor = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, r.Location).Add(r.DayStart)
+	}
+
+	return nextAfter(anchor, period, now), nil
+}
+
+// CurrentDay returns the start of the daily window containing now
+func (r Rule) CurrentDay(now time.Time) time.Time {
+	next, _ := r.Next(cerebras.WindowTokensDay, now)
+	return next.Add(-24 * time.Hour)
+}
+
+// nextAfter returns the first anchor + k*period strictly after now
+func nextAfter(anchor time.Time, period time.Duration, now time.Time) time.Time {
+	k := now.Sub(anchor) / period
+	next := anchor.Add(k * period)
+	for !next.After(now) {
+		next = next.Add(period)
+	}
+	for next.Add(-period).After(now) {
+		next = next.Add(-period)
+	}
+	return next
+}
+
+// anchor is a known past or future reset instant of a window
+type anchor struct {
+	at         time.Time
+	precision  time.Duration // width of the interval the reset was seen in
+	confidence Confidence
+}
+
+// Estimator learns window boundaries from response headers and snapshot
+// history, falling back to a boundary rule for windows it has not seen reset
+type Estimator struct {
+	rule Rule
+
+	mu      sync.Mutex
+	anchors map[string]anchor
+	last    map[string]observation
+}
+
+// observation is the last usage seen for a daily window
+type observation struct {
+	at   time.Time
+	used int64
+}
+
+// NewEstimator creates an estimator with the given fallback rule
+func NewEstimator(rule Rule) *Estimator {
+	if rule.Location == nil {
+		rule.Location = time.UTC
+	}
+	return &Estimator{rule: rule, anchors: make(map[string]anchor), last: make(map[string]observation)}
+}
+
+// Rule returns the fallback rule of the estimator
+func (e *Estimator) Rule() Rule {
+	return e.rule
+}
+
+// maxPrecision is the widest drop interval still trusted for a window period
+func maxPrecision(period time.Duration) time.Duration {
+	return period / 12
+}
+
+// learn keeps the most precise anchor seen for the window
+func (e *Estimator) learn(window string, a anchor) {
+	period, err := cerebras.WindowPeriod(window)
+	if err != nil || a.precision > maxPrecision(period) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if current, ok := e.anchors[window]; ok && current.precision < a.precision {
+		return
+	}
+	e.anchors[window] = a
+}
+
+// Learn scans snapshots, ordered oldest first, for daily counter drops and
+// reported reset countdowns
+func (e *Estimator) Learn(snapshots []db.UsageSnapshot) {
+	var prev *db.UsageSnapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.ResetRequestsSeconds != nil && *s.ResetRequestsSeconds > 0 {
+			at := s.Timestamp.Add(time.Duration(*s.ResetRequestsSeconds) * time.Second)
+			e.learn(cerebras.WindowRequestsDay, anchor{at: at, precision: time.Second, confidence: ConfidenceObserved})
+		}
+
+		if prev != nil {
+			for window, used := range map[string]func(*db.UsageSnapshot) *int64{
+				cerebras.WindowRequestsDay: func(s *db.UsageSnapshot) *int64 { return s.RequestsUsed },
+				cerebras.WindowTokensDay:   func(s *db.UsageSnapshot) *int64 { return s.TokensUsedDay },
+			} {
+				before, after := used(prev), used(s)
+				if before == nil || after == nil || *after >= *before {
+					continue
+				}
+				// The counter dropped, so the window reset between the two snapshots
+				gap := s.Timestamp.Sub(prev.Timestamp)
+				e.learn(window, anchor{
+					at:         prev.Timestamp.Add(gap / 2),
+					precision:  gap,
+					confidence: ConfidenceObserved,
+				})
+			}
+		}
+		prev = s
+	}
+}
+
+// LearnFromHistory loads the recent snapshot history of the region and
+// learns from it
+func (e *Estimator) LearnFromHistory(ctx context.Context, queries *db.Queries, organization, model, region string, now time.Time) error {
+	snapshots, err := queries.GetUsageSnapshotsBetween(ctx, db.GetUsageSnapshotsBetweenParams{
+		OrganizationID: organization,
+		ModelName:      model,
+		RegionID:       region,
+		StartTime:      now.Add(-HistoryWindow).UTC(),
+		EndTime:        now.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	e.Learn(snapshots)
+	return nil
+}
+
+// observe learns from a drop in a daily counter since the previous fetch
+func (e *Estimator) observe(info *cerebras.RateLimitInfo, now time.Time) {
+	for _, window := range []string{cerebras.WindowRequestsDay, cerebras.WindowTokensDay} {
+		w, err := info.Window(window)
+		if err != nil || w.Limit <= 0 {
+			continue
+		}
+
+		e.mu.Lock()
+		prev, ok := e.last[window]
+		e.last[window] = observation{at: now, used: w.Used}
+		e.mu.Unlock()
+
+		if ok && w.Used < prev.used {
+			gap := now.Sub(prev.at)
+			e.learn(window, anchor{at: prev.at.Add(gap / 2), precision: gap, confidence: ConfidenceObserved})
+		}
+	}
+}
+
+// Estimate returns the next reset of the window. reported is the countdown
+// from the current response, 0 when unknown.
+func (e *Estimator) Estimate(window string, reported int64, now time.Time) (Estimate, error) {
+	period, err := cerebras.WindowPeriod(window)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	if reported > 0 {
+		at := now.Add(time.Duration(reported) * time.Second)
+		e.learn(window, anchor{at: at, precision: time.Second, confidence: ConfidenceObserved})
+		return Estimate{Window: window, At: at, Confidence: ConfidenceReported}, nil
+	}
+
+	e.mu.Lock()
+	a, ok := e.anchors[window]
+	if !ok {
+		// Daily request and token windows share a boundary
+		a, ok = e.anchors[siblingWindow(window)]
+	}
+	e.mu.Unlock()
+	if ok {
+		return Estimate{Window: window, At: nextAfter(a.at, period, now), Confidence: a.confidence}, nil
+	}
+
+	at, err := e.rule.Next(window, now)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return Estimate{Window: window, At: at, Confidence: ConfidenceAssumed}, nil
+}
+
+// Apply estimates every window of info and returns the estimates keyed by
+// window name. The reset countdowns of info are left as reported, so metrics
+// that are cached or served elsewhere never pass an estimate off as reported.
+func (e *Estimator) Apply(info *cerebras.RateLimitInfo, now time.Time) map[string]Estimate {
+	if info == nil {
+		return nil
+	}
+
+	e.observe(info, now)
+
+	estimates := make(map[string]Estimate, len(cerebras.Windows))
+	for _, window := range cerebras.Windows {
+		field := info.Field(cerebras.FieldReset, window)
+		est, err := e.Estimate(window, *field, now)
+		if err != nil {
+			continue
+		}
+		estimates[window] = est
+	}
+
+	return estimates
+}
+
+// Fill sets the reset countdowns of info the API did not report from the
+// estimates. It is meant for metrics about to be displayed, not stored.
+func Fill(info *cerebras.RateLimitInfo, estimates map[string]Estimate, now time.Time) {
+	for window, est := range estimates {
+		if field := info.Field(cerebras.FieldReset, window); field != nil && *field <= 0 {
+			*field = est.Seconds(now)
+		}
+	}
+}
+
+// siblingWindow pairs the request and token windows of the same period
+func siblingWindow(window string) string {
+	switch window {
+	case cerebras.WindowRequestsMinute:
+		return cerebras.WindowTokensMinute
+	case cerebras.WindowTokensMinute:
+		return cerebras.WindowRequestsMinute
+	case cerebras.WindowRequestsHour:
+		return cerebras.WindowTokensHour
+	case cerebras.WindowTokensHour:
+		return cerebras.WindowRequestsHour
+	case cerebras.WindowRequestsDay:
+		return cerebras.WindowTokensDay
+	case cerebras.WindowTokensDay:
+		return cerebras.WindowRequestsDay
+	default:
+		return ""
+	}
+}
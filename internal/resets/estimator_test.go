@@ -0,0 +1,134 @@
+package resets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/db"
+)
+
+func ptr(v int64) *int64 { return &v }
+
+func TestRuleNext(t *testing.T) {
+	now := time.Date(2025, 8, 10, 14, 30, 15, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+
+	tests := []struct {
+		name     string
+		rule     Rule
+		window   string
+		expected time.Time
+	}{
+		{name: "minute", rule: DefaultRule(), window: cerebras.WindowTokensMinute, expected: time.Date(2025, 8, 10, 14, 31, 0, 0, time.UTC)},
+		{name: "hour", rule: DefaultRule(), window: cerebras.WindowRequestsHour, expected: time.Date(2025, 8, 10, 15, 0, 0, 0, time.UTC)},
+		{name: "UTC midnight", rule: DefaultRule(), window: cerebras.WindowTokensDay, expected: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "local day start", rule: Rule{Location: berlin, DayStart: 2 * time.Hour}, window: cerebras.WindowRequestsDay, expected: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := tt.rule.Next(tt.window, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !next.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestRuleCurrentDay(t *testing.T) {
+	// 23:30 in New York is already the next UTC day
+	newYork := time.FixedZone("EDT", -4*60*60)
+	now := time.Date(2025, 8, 10, 23, 30, 0, 0, newYork)
+
+	if got, want := DefaultRule().CurrentDay(now), time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected the UTC day to start at %v, got %v", want, got)
+	}
+	rule := Rule{Location: time.UTC, DayStart: 6 * time.Hour}
+	if got, want := rule.CurrentDay(now), time.Date(2025, 8, 10, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected the day to start at %v before day-start, got %v", want, got)
+	}
+}
+
+func TestEstimatorFallsBackToRule(t *testing.T) {
+	e := NewEstimator(DefaultRule())
+	now := time.Date(2025, 8, 10, 18, 0, 0, 0, time.UTC)
+
+	est, err := e.Estimate(cerebras.WindowTokensDay, 0, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if est.Confidence != ConfidenceAssumed || est.Seconds(now) != 6*3600 {
+		t.Errorf("Expected assumed reset in 6h, got %s in %ds", est.Confidence, est.Seconds(now))
+	}
+}
+
+func TestEstimatorLearnsCounterDrops(t *testing.T) {
+	base := time.Date(2025, 8, 9, 4, 55, 0, 0, time.UTC)
+	e := NewEstimator(DefaultRule())
+	e.Learn([]db.UsageSnapshot{
+		{Timestamp: base, RequestsUsed: ptr(900), TokensUsedDay: ptr(50000)},
+		{Timestamp: base.Add(5 * time.Minute), RequestsUsed: ptr(950), TokensUsedDay: ptr(52000)},
+		{Timestamp: base.Add(15 * time.Minute), RequestsUsed: ptr(3), TokensUsedDay: ptr(100)},
+	})
+
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	est, err := e.Estimate(cerebras.WindowRequestsDay, 0, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := time.Date(2025, 8, 11, 5, 5, 0, 0, time.UTC)
+	if est.Confidence != ConfidenceObserved || !est.At.Equal(expected) {
+		t.Errorf("Expected observed reset at %v, got %s at %v", expected, est.Confidence, est.At)
+	}
+}
+
+func TestEstimatorIgnoresWideGaps(t *testing.T) {
+	base := time.Date(2025, 8, 9, 1, 0, 0, 0, time.UTC)
+	e := NewEstimator(DefaultRule())
+	e.Learn([]db.UsageSnapshot{
+		{Timestamp: base, RequestsUsed: ptr(900)},
+		{Timestamp: base.Add(6 * time.Hour), RequestsUsed: ptr(10)},
+	})
+
+	est, _ := e.Estimate(cerebras.WindowRequestsDay, 0, base.Add(7*time.Hour))
+	if est.Confidence != ConfidenceAssumed {
+		t.Errorf("Expected a 6h gap to be ignored, got %s", est.Confidence)
+	}
+}
+
+func TestEstimatorApply(t *testing.T) {
+	now := time.Date(2025, 8, 10, 23, 0, 0, 0, time.UTC)
+	info := &cerebras.RateLimitInfo{ResetRequestsDay: 7200}
+
+	estimates := NewEstimator(DefaultRule()).Apply(info, now)
+	if len(estimates) != len(cerebras.Windows) {
+		t.Fatalf("Expected %d estimates, got %d", len(cerebras.Windows), len(estimates))
+	}
+	if estimates[cerebras.WindowRequestsDay].Confidence != ConfidenceReported || info.ResetRequestsDay != 7200 {
+		t.Errorf("Expected reported request reset to be kept, got %+v", estimates[cerebras.WindowRequestsDay])
+	}
+	// The token day window shares the reported boundary
+	tokensDay := estimates[cerebras.WindowTokensDay]
+	if tokensDay.Confidence != ConfidenceObserved || tokensDay.Seconds(now) != 7200 {
+		t.Errorf("Expected token reset from the reported boundary, got %d (%s)", tokensDay.Seconds(now), tokensDay.Confidence)
+	}
+	if got := estimates[cerebras.WindowTokensHour].Seconds(now); got != 3600 {
+		t.Errorf("Expected hourly reset at the next hour, got %d", got)
+	}
+	// Estimates stay out of the metrics, which may be cached and reloaded
+	if info.ResetTokensDay != 0 || info.ResetTokensHour != 0 {
+		t.Errorf("Expected unreported resets to stay unset, got %d and %d", info.ResetTokensDay, info.ResetTokensHour)
+	}
+
+	Fill(info, estimates, now)
+	if info.ResetRequestsDay != 7200 || info.ResetTokensDay != 7200 || info.ResetTokensHour != 3600 {
+		t.Errorf("Expected Fill to set the unreported resets, got %+v", info)
+	}
+}
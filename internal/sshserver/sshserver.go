@@ -0,0 +1,72 @@
+// Package sshserver hosts the terminal dashboard over SSH, so teammates can
+// watch a shared monitor with "ssh -p 2222 monitor@host" without installing
+// anything. Every session runs its own bubbletea program.
+package sshserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	bm "github.com/charmbracelet/wish/bubbletea"
+)
+
+// Options configure the SSH server
+type Options struct {
+	// HostKeyPath is the private host key, generated when missing
+	HostKeyPath string
+	// AuthorizedKeysPath lists the public keys allowed to connect, in the
+	// OpenSSH authorized_keys format. It is re-read on every login.
+	AuthorizedKeysPath string
+}
+
+// NewModel returns the model shown to a new session
+type NewModel func(sess ssh.Session) tea.Model
+
+// New returns a server showing a model created by newModel in every
+// session. Sessions must authenticate with an authorized key and request a
+// terminal.
+func New(opts Options, newModel NewModel) (*ssh.Server, error) {
+	if _, err := os.Stat(opts.AuthorizedKeysPath); err != nil {
+		return nil, fmt.Errorf("authorized keys: %w", err)
+	}
+	handler := func(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
+		return newModel(sess), []tea.ProgramOption{tea.WithAltScreen()}
+	}
+	return wish.NewServer(
+		wish.WithHostKeyPath(opts.HostKeyPath),
+		wish.WithAuthorizedKeys(opts.AuthorizedKeysPath),
+		// Middlewares run last to first: sessions without a terminal are
+		// turned away before a program starts
+		wish.WithMiddleware(
+			bm.Middleware(handler),
+			activeterm.Middleware(),
+		),
+	)
+}
+
+// Serve serves on l until ctx is cancelled, then closes every session
+func Serve(ctx context.Context, server *ssh.Server, l net.Listener) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(l)
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Sessions stay open until their viewers quit, so close them
+		// rather than wait
+		return server.Close()
+	case err := <-errs:
+		if errors.Is(err, ssh.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
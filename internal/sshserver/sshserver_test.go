@@ -0,0 +1,158 @@
+package sshserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// staticModel shows a fixed view until q is pressed
+type staticModel struct{ user string }
+
+func (m staticModel) Init() tea.Cmd { return nil }
+
+func (m staticModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "q" {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m staticModel) View() string { return "hello " + m.user }
+
+// syncBuffer collects session output across goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newSigner(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	allowed := newSigner(t)
+	authorizedKeys := filepath.Join(dir, "authorized_keys")
+	if err := os.WriteFile(authorizedKeys, gossh.MarshalAuthorizedKey(allowed.PublicKey()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := New(Options{
+		HostKeyPath:        filepath.Join(dir, "host_key"),
+		AuthorizedKeysPath: authorizedKeys,
+	}, func(sess ssh.Session) tea.Model {
+		return staticModel{user: sess.User()}
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, server, listener)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	}()
+
+	dial := func(signer gossh.Signer) (*gossh.Client, error) {
+		return gossh.Dial("tcp", listener.Addr().String(), &gossh.ClientConfig{
+			User:            "viewer",
+			Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+			HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+			Timeout:         5 * time.Second,
+		})
+	}
+
+	if client, err := dial(newSigner(t)); err == nil {
+		_ = client.Close()
+		t.Error("Expected a key missing from authorized_keys to be rejected")
+	}
+
+	client, err := dial(allowed)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out syncBuffer
+	session.Stdout = &out
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.RequestPty("xterm-256color", 24, 80, gossh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Shell(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "hello viewer") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the model's view, got %q", out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// Quitting the program ends the session
+	if _, err := stdin.Write([]byte("q")); err != nil {
+		t.Fatal(err)
+	}
+	waited := make(chan error, 1)
+	go func() {
+		waited <- session.Wait()
+	}()
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the session to end after quitting")
+	}
+}
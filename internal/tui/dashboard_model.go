@@ -1,20 +1,42 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/alerts"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/notify"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/resets"
 )
 
 // DashboardModel represents the model for the dashboard
 type DashboardModel struct {
-	client       *cerebras.Client
+	// ctx is cancelled on quit, abandoning in-flight fetches
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// monitor polls and processes metrics; daemon, when set, is asked first
+	monitor *monitor.Monitor
+	daemon  *daemon.Client
+	// source, when set, replaces polling (see WithSource)
+	source Source
+	// readOnly sessions cannot change the organization, model or credentials
+	readOnly bool
+
 	organization string
 	modelName    string
 	refreshRate  int
@@ -25,18 +47,122 @@ type DashboardModel struct {
 	width        int
 	height       int
 	quitting     bool
+
+	// Cost tracking results (see monitor.Monitor.WithHistory)
+	budgets      billing.Budgets
+	spend        *billing.Spend
+	budgetAlerts []billing.BudgetAlert
+
+	// Soft limits exceeded by the current usage (see monitor.Monitor.WithSoftLimits)
+	breaches []pacing.Breach
+
+	// Alert rules firing at the last poll (see monitor.Monitor.WithRules)
+	rules []alerts.Condition
+
+	// Reset times for windows the API does not report
+	estimates map[string]resets.Estimate
+
+	// snoozedUntil is when the alerts snoozed from the dashboard notify again
+	snoozedUntil time.Time
+
+	// Watched organizations polled side by side (see WithOrganizations);
+	// the other fields show the one opened from the Overview tab
+	watched []watchedOrganization
+	cursor  int
+
+	// picker and organizations, when open, replace the active tab (see
+	// openModelPicker and openOrganizations); monitors holds the monitor of
+	// each organization and model switched to
+	picker        *modelPicker
+	organizations *OrganizationListModel
+	monitors      map[string]*monitor.Monitor
+}
+
+// watchedOrganization is one row of the Overview tab
+type watchedOrganization struct {
+	monitor *monitor.Monitor
+	name    string
+	snap    *monitor.Snapshot
+	err     error
 }
 
-// NewDashboardModel creates a new dashboard model
-func NewDashboardModel(client *cerebras.Client, organization, modelName string, refreshRate int) DashboardModel {
+// NewDashboardModel creates a new dashboard model showing the metrics of the monitor
+func NewDashboardModel(mon *monitor.Monitor, refreshRate int) DashboardModel {
+	ctx, cancel := context.WithCancel(context.Background())
 	return DashboardModel{
-		client:       client,
-		organization: organization,
-		modelName:    modelName,
+		ctx:          ctx,
+		cancel:       cancel,
+		monitor:      mon,
+		organization: mon.Organization(),
+		modelName:    mon.Model(),
 		refreshRate:  refreshRate,
 		tabs:         []string{"Dashboard", "Usage", "Quotas", "Settings"},
 		activeTab:    0,
+		budgets:      mon.Budgets(),
+		monitors:     make(map[string]*monitor.Monitor),
+	}
+}
+
+// WithContext derives the dashboard's context from ctx, so cancelling ctx
+// also stops in-flight fetches
+func (m DashboardModel) WithContext(ctx context.Context) DashboardModel {
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	return m
+}
+
+// WithDaemon shows the snapshots of a running daemon polling the same
+// organization and model, polling directly only when the daemon is not
+// available
+func (m DashboardModel) WithDaemon(client *daemon.Client) DashboardModel {
+	m.daemon = client
+	return m
+}
+
+// WithOrganizations watches several organizations: they are polled
+// concurrently and the Overview tab shows the tightest window of each, from
+// which any of them opens in the other tabs. names maps organization IDs to
+// display names. The dashboard's own monitor should be one of monitors.
+func (m DashboardModel) WithOrganizations(monitors []*monitor.Monitor, names map[string]string) DashboardModel {
+	if len(monitors) < 2 {
+		return m
+	}
+	m.watched = make([]watchedOrganization, len(monitors))
+	for i, mon := range monitors {
+		name := names[mon.Organization()]
+		if name == "" {
+			name = mon.Organization()
+		}
+		m.watched[i] = watchedOrganization{monitor: mon, name: name}
+		if mon == m.monitor {
+			m.cursor = i
+		}
 	}
+	m.tabs = append([]string{"Overview"}, m.tabs...)
+	m.activeTab = 0
+	return m
+}
+
+// Source provides snapshots polled elsewhere
+type Source interface {
+	// Latest returns the latest snapshot, or nil before the first poll
+	Latest() *monitor.Snapshot
+	// LastError returns the error of the last poll, or nil when it succeeded
+	LastError() error
+}
+
+// WithSource shows the snapshots of src instead of polling, so several
+// dashboards can share one poller
+func (m DashboardModel) WithSource(src Source) DashboardModel {
+	m.source = src
+	return m
+}
+
+// ReadOnly marks the dashboard as a viewer, such as a remote session, that
+// must not change the organization, model or credentials
+func (m DashboardModel) ReadOnly() DashboardModel {
+	m.readOnly = true
+	return m
 }
 
 // Init initializes the model
@@ -54,20 +180,68 @@ func (m DashboardModel) Init() tea.Cmd {
 // tickMsg represents a tick message
 type tickMsg time.Time
 
-// fetchMetrics fetches metrics from the Cerebras API
+// fetchMetrics fetches metrics from the source, the daemon or the Cerebras API
 func (m DashboardModel) fetchMetrics() tea.Cmd {
+	if len(m.watched) > 0 && m.source == nil {
+		if slices.ContainsFunc(m.watched, func(w watchedOrganization) bool { return w.monitor == m.monitor }) {
+			return m.fetchOverview()
+		}
+		// An organization opened from the switcher is polled on its own
+		overview := m.fetchOverview()
+		m.watched = nil
+		return tea.Batch(overview, m.fetchMetrics())
+	}
 	return func() tea.Msg {
-		metrics, err := m.client.GetMetrics(m.organization)
+		if m.source != nil {
+			snap := m.source.Latest()
+			if snap == nil {
+				if err := m.source.LastError(); err != nil {
+					return errMsg{err}
+				}
+				return nil
+			}
+			return metricsMsg{snap.At(time.Now())}
+		}
+		snap, err := m.poll(m.monitor)
 		if err != nil {
 			return errMsg{err}
 		}
-		return metricsMsg{metrics}
+		return metricsMsg{snap}
 	}
 }
 
+// fetchOverview polls every watched organization concurrently
+func (m DashboardModel) fetchOverview() tea.Cmd {
+	return func() tea.Msg {
+		results := make(overviewMsg, len(m.watched))
+		var wg sync.WaitGroup
+		for i, w := range m.watched {
+			wg.Add(1)
+			go func(i int, mon *monitor.Monitor) {
+				defer wg.Done()
+				snap, err := m.poll(mon)
+				results[i] = organizationResult{snap: snap, err: err}
+			}(i, w.monitor)
+		}
+		wg.Wait()
+		return results
+	}
+}
+
+// poll returns the snapshot of the monitor's organization from the daemon,
+// or polls the Cerebras API
+func (m DashboardModel) poll(mon *monitor.Monitor) (*monitor.Snapshot, error) {
+	if m.daemon != nil {
+		if snap, err := m.daemon.SnapshotFor(m.ctx, mon.Organization(), mon.Model(), mon.Region()); err == nil {
+			return snap.At(time.Now()), nil
+		}
+	}
+	return mon.Poll(m.ctx)
+}
+
 // metricsMsg represents a metrics message
 type metricsMsg struct {
-	metrics *cerebras.RateLimitInfo
+	*monitor.Snapshot
 }
 
 // errMsg represents an error message
@@ -75,21 +249,104 @@ type errMsg struct {
 	err error
 }
 
+// overviewMsg holds the poll results of the watched organizations, in order
+type overviewMsg []organizationResult
+
+// organizationResult is the poll result of one watched organization
+type organizationResult struct {
+	snap *monitor.Snapshot
+	err  error
+}
+
+// snoozeMsg reports the outcome of snoozing the open alerts
+type snoozeMsg struct {
+	until time.Time
+	count int
+}
+
+// alertSnooze is how long the z key snoozes the open alerts
+const alertSnooze = time.Hour
+
+// toggleSnooze snoozes the open alerts for alertSnooze, or lifts the snooze
+// set earlier
+func (m DashboardModel) toggleSnooze() tea.Cmd {
+	until := time.Now().Add(alertSnooze)
+	if m.snoozedUntil.After(time.Now()) {
+		until = time.Time{}
+	}
+	return func() tea.Msg {
+		return snoozeMsg{until: until, count: m.monitor.SnoozeAlerts(m.ctx, until)}
+	}
+}
+
 // Update handles messages in the model
 func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.picker != nil {
+			return m.updateModelPicker(msg)
+		}
+		if m.organizations != nil {
+			return m.updateOrganizations(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
+			m.cancel()
 			return m, tea.Quit
 		case "tab":
 			m.activeTab = (m.activeTab + 1) % len(m.tabs)
 			return m, nil
+		case "up", "k":
+			if m.tabs[m.activeTab] == "Overview" && m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "j":
+			if m.tabs[m.activeTab] == "Overview" && m.cursor < len(m.watched)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case "enter":
+			if m.tabs[m.activeTab] == "Overview" {
+				return m.openOrganization(m.cursor), nil
+			}
+			return m, nil
+		case "esc":
+			if len(m.watched) > 0 {
+				m.activeTab = 0
+			}
+			return m, nil
 		case "r":
 			// Refresh data immediately
 			return m, m.fetchMetrics()
+		case "z":
+			if m.readOnly {
+				return m, nil
+			}
+			return m, m.toggleSnooze()
+		case "m":
+			if m.readOnly || m.source != nil {
+				return m, nil
+			}
+			return m.openModelPicker()
+		case "o":
+			if m.readOnly || m.source != nil {
+				return m, nil
+			}
+			return m.openOrganizations()
 		}
+	case modelsMsg:
+		return m.showModels(msg), nil
+	case organizationsMsg:
+		return m.showOrganizations(msg), nil
+	case organizationPickedMsg:
+		return m.switchOrganization(msg.organization, msg.save)
+	case organizationListClosedMsg:
+		m.organizations = nil
+		return m, nil
+	case organizationSavedMsg:
+		return m.savedOrganization(msg), nil
 	case tickMsg:
 		// Refresh data on tick
 		return m, tea.Batch(
@@ -99,11 +356,37 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}),
 		)
 	case metricsMsg:
-		m.metrics = msg.metrics
+		// Polls started before switching organization or model are stale
+		if msg.Organization != m.organization || msg.Model != m.modelName {
+			return m, nil
+		}
+		m = m.showSnapshot(msg.Snapshot)
+		return m, nil
+	case overviewMsg:
+		m.watched = slices.Clone(m.watched)
+		for i, res := range msg {
+			w := &m.watched[i]
+			w.err = res.err
+			if res.snap != nil {
+				w.snap = res.snap
+			}
+			if w.monitor == m.monitor {
+				m.err = res.err
+				if res.snap != nil {
+					m = m.showSnapshot(res.snap)
+				}
+			}
+		}
 		return m, nil
 	case errMsg:
 		m.err = msg.err
 		return m, nil
+	case snoozeMsg:
+		m.snoozedUntil = msg.until
+		if msg.count == 0 {
+			m.snoozedUntil = time.Time{}
+		}
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -113,6 +396,34 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// showSnapshot shows the results of a poll
+func (m DashboardModel) showSnapshot(snap *monitor.Snapshot) DashboardModel {
+	m.metrics = snap.Metrics
+	m.spend = snap.Spend
+	m.budgetAlerts = snap.BudgetAlerts
+	m.breaches = snap.Breaches
+	m.rules = snap.Rules
+	m.estimates = snap.Estimates
+	return m
+}
+
+// openOrganization shows the watched organization at index i in the other
+// tabs, starting from its last poll, and switches to the Dashboard tab
+func (m DashboardModel) openOrganization(i int) DashboardModel {
+	w := m.watched[i]
+	m.monitor = w.monitor
+	m.organization = w.monitor.Organization()
+	m.budgets = w.monitor.Budgets()
+	m.metrics, m.spend, m.budgetAlerts, m.breaches, m.rules, m.estimates = nil, nil, nil, nil, nil, nil
+	m.snoozedUntil = time.Time{}
+	m.err = w.err
+	if w.snap != nil {
+		m = m.showSnapshot(w.snap)
+	}
+	m.activeTab = slices.Index(m.tabs, "Dashboard")
+	return m
+}
+
 // View renders the model
 func (m DashboardModel) View() string {
 	if m.quitting {
@@ -163,15 +474,15 @@ func (m DashboardModel) View() string {
 		Align(lipgloss.Left)
 
 	// Render header with specific coloring: icon + "Cerebras" in primary, rest in subtle.
-    // Use Header.Copy() for segments so they keep the same background and do not reset it.
-    brandPrimary := styles.Header.Copy().Foreground(styles.Palette.Primary)
-    headerSubtle := styles.Header.Copy().Foreground(styles.Palette.Subtle)
+	// Use Header.Copy() for segments so they keep the same background and do not reset it.
+	brandPrimary := styles.Header.Copy().Foreground(styles.Palette.Primary)
+	headerSubtle := styles.Header.Copy().Foreground(styles.Palette.Subtle)
 
-    iconText := brandPrimary.Render(fmt.Sprintf(" %s", icons.Dashboard)) // leading space before icon
-    brandText := brandPrimary.Render(" Cerebras")
-    restText := headerSubtle.Render(" Code Monitor Dashboard")
-    headerLine := lipgloss.JoinHorizontal(lipgloss.Left, iconText, brandText, restText)
-    title := titleStyle.Render(headerLine)
+	iconText := brandPrimary.Render(fmt.Sprintf(" %s", icons.Dashboard)) // leading space before icon
+	brandText := brandPrimary.Render(" Cerebras")
+	restText := headerSubtle.Render(" Code Monitor Dashboard")
+	headerLine := lipgloss.JoinHorizontal(lipgloss.Left, iconText, brandText, restText)
+	title := titleStyle.Render(headerLine)
 
 	// Render tabs
 	var tabs []string
@@ -187,6 +498,8 @@ func (m DashboardModel) View() string {
 	// Render content based on active tab
 	var content string
 	switch m.tabs[m.activeTab] {
+	case "Overview":
+		content = m.renderOverview()
 	case "Dashboard":
 		content = m.renderDashboard()
 	case "Usage":
@@ -198,13 +511,25 @@ func (m DashboardModel) View() string {
 	default:
 		content = "Unknown tab"
 	}
+	if m.picker != nil {
+		content = m.renderModelPicker()
+	}
+	if m.organizations != nil {
+		content = m.organizations.View()
+	}
 
 	// Render status bar
-	statusBar := statusBarStyle.Render(
-		fmt.Sprintf("%s Organization: %s | %s Model: %s | %s Refresh: %ds",
-			icons.Organization, m.organization,
-			icons.Model, m.modelName,
-			icons.Time, m.refreshRate))
+	status := fmt.Sprintf("%s Organization: %s | %s Model: %s | %s Refresh: %ds",
+		icons.Organization, m.organization,
+		icons.Model, m.modelName,
+		icons.Time, m.refreshRate)
+	if cost := m.renderCost(); cost != "" {
+		status += " | " + cost
+	}
+	if m.snoozedUntil.After(time.Now()) {
+		status += fmt.Sprintf(" | %s Alerts snoozed until %s", icons.Time, m.snoozedUntil.In(config.GetLocation()).Format("15:04"))
+	}
+	statusBar := statusBarStyle.Render(status)
 
 	// Combine all elements (add a blank spacer line after the header)
 	spacer := ""
@@ -222,214 +547,251 @@ func (m DashboardModel) View() string {
 	return outer.Render(view)
 }
 
+// renderCost renders today's spend for the status bar, colored by budget state
+func (m DashboardModel) renderCost() string {
+	if m.spend == nil {
+		return ""
+	}
+
+	icons := config.GetIcons()
+	styles := GetStyles()
+
+	text := fmt.Sprintf("%s Today: %s", icons.Cost, billing.FormatAmount(m.spend.Day, m.spend.Currency))
+	if m.budgets.Daily > 0 {
+		text += "/" + billing.FormatAmount(m.budgets.Daily, m.spend.Currency)
+	}
+
+	color := styles.Palette.Subtle
+	for _, alert := range m.budgetAlerts {
+		if alert.Severity == billing.SeverityCritical {
+			color = styles.Palette.Error
+			break
+		}
+		color = styles.Palette.Warning
+	}
+
+	return lipgloss.NewStyle().Foreground(color).Render(text)
+}
+
 // renderDashboard renders the main dashboard content
 func (m DashboardModel) renderDashboard() string {
-    icons := config.GetIcons()
-    styles := GetStyles()
-
-    if m.err != nil {
-        return styles.Error.Render(fmt.Sprintf("Error: %v", m.err))
-    }
-
-    if m.metrics == nil {
-        return fmt.Sprintf("%s Loading metrics...", icons.Info)
-    }
-
-    // Helpers to compute used counts with GraphQL-first fallback strategy
-    usedFrom := func(usage, limit, remaining int64) int64 {
-        if usage > 0 {
-            return usage
-        }
-        if limit > 0 && remaining >= 0 {
-            u := limit - remaining
-            if u < 0 {
-                u = 0
-            }
-            return u
-        }
-        return 0
-    }
-
-    // Layout measurements
-    available := m.width - 2 // matches outer padding in View()
-    if available < 40 {
-        available = 40
-    }
-    gap := 2
-    // Use a slightly higher breakpoint so two columns only activate on comfortably wide terminals
-    twoCols := available >= 96
-    colW := available
-    if twoCols {
-        colW = (available - gap) / 2
-    }
-
-    // Base styles (muted, no backgrounds except progress bars)
-    title := styles.SectionTitle
-    label := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
-    value := lipgloss.NewStyle().Foreground(styles.Palette.Text).Bold(true)
-    dim := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
-
-    // Progress rows
-    // Layout: first line has label; second line has a full-width bar; third line has left-aligned percentage and right-aligned (used/limit).
-    lblW := 16
-    // Make the progress bar span the entire card column width including brackets
-    // createProgressBar wraps content in [ and ], so subtract 2 to avoid wrapping
-    barW := colW - 2
-    if barW < 10 {
-        barW = 10
-    }
-
-    // Helper to render a metric block (title, bar, stats) or Unknown when limit is missing
-    // resetSecs: optional reset seconds shown centered in the stats row when > 0
-    renderMetric := func(icon, name string, used, limit, resetSecs int64) []string {
-        // Allow natural width for the title to avoid forced wrapping
-        titleRow := label.Render(fmt.Sprintf("%s %s", icon, name))
-        if limit <= 0 {
-            return []string{titleRow, dim.Render("Unknown")}
-        }
-        percent := 0.0
-        if limit > 0 {
-            percent = float64(used) / float64(limit) * 100
-        }
-        bar := m.createProgressBar(percent, barW)
-        left := value.Render(fmt.Sprintf("%.1f%%", percent))
-        right := value.Render(fmt.Sprintf("(%s/%s)", m.formatInt(used), m.formatInt(limit)))
-
-        // Build a centered middle area to optionally display reset information
-        center := ""
-        if resetSecs > 0 {
-            center = dim.Render(fmt.Sprintf("resets in %s", m.formatResetTime(resetSecs)))
-        }
-        leftW := lipgloss.Width(left)
-        rightW := lipgloss.Width(right)
-        centerW := lipgloss.Width(center)
-        fillerW := colW - leftW - rightW
-        if fillerW < 1 {
-            fillerW = 1
-        }
-        var stats string
-        if centerW == 0 || centerW+2 > fillerW { // not enough room, omit center text
-            stats = lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Repeat(" ", fillerW), right)
-        } else {
-            // Center the middle text between left and right blocks
-            leftPad := (fillerW - centerW) / 2
-            rightPad := fillerW - centerW - leftPad
-            stats = lipgloss.JoinHorizontal(
-                lipgloss.Top,
-                left,
-                strings.Repeat(" ", leftPad),
-                center,
-                strings.Repeat(" ", rightPad),
-                right,
-            )
-        }
-        return []string{titleRow, bar, stats}
-    }
-
-    // Compute used/limits for all relevant metrics
-    rpmLimit := m.metrics.LimitRequestsMinute
-    rphLimit := m.metrics.LimitRequestsHour
-    rpdLimit := m.metrics.LimitRequestsDay
-    tpmLimit := m.metrics.LimitTokensMinute
-    tphLimit := m.metrics.LimitTokensHour
-    tpdLimit := m.metrics.LimitTokensDay
-
-    rpmUsed := usedFrom(m.metrics.UsageRequestsMinute, rpmLimit, m.metrics.RemainingRequestsMinute)
-    rphUsed := usedFrom(m.metrics.UsageRequestsHour, rphLimit, m.metrics.RemainingRequestsHour)
-    rpdUsed := usedFrom(m.metrics.UsageRequestsDay, rpdLimit, m.metrics.RemainingRequestsDay)
-    tpmUsed := usedFrom(m.metrics.UsageTokensMinute, tpmLimit, m.metrics.RemainingTokensMinute)
-    tphUsed := usedFrom(m.metrics.UsageTokensHour, tphLimit, m.metrics.RemainingTokensHour)
-    tpdUsed := usedFrom(m.metrics.UsageTokensDay, tpdLimit, m.metrics.RemainingTokensDay)
-
-    // Card 1: Rate Limits (Requests & Tokens across minute/hour/day)
-    // Insert a blank line between metrics when in vertical (single column) layout
-    cardRows := []string{title.Render("Rate Limits")}
-    addMetric := func(rows []string) {
-        cardRows = append(cardRows, rows...)
-        if !twoCols {
-            cardRows = append(cardRows, "")
-        }
-    }
-    // Known reset timers (when available)
-    reqDayReset := m.metrics.ResetRequestsDay
-    tokMinReset := m.metrics.ResetTokensMinute
-
-    addMetric(renderMetric(icons.Request, "Requests/min", rpmUsed, rpmLimit, 0))
-    addMetric(renderMetric(icons.Request, "Requests/hr", rphUsed, rphLimit, 0))
-    addMetric(renderMetric(icons.Request, "Requests/day", rpdUsed, rpdLimit, reqDayReset))
-    addMetric(renderMetric(icons.Token, "Tokens/min", tpmUsed, tpmLimit, tokMinReset))
-    addMetric(renderMetric(icons.Token, "Tokens/hr", tphUsed, tphLimit, 0))
-    addMetric(renderMetric(icons.Token, "Tokens/day", tpdUsed, tpdLimit, 0))
-    // Trim trailing blank in two-column mode already avoided; in vertical it's fine to end with a blank
-    card1 := lipgloss.JoinVertical(lipgloss.Left, cardRows...)
-
-    // Card 2: Quotas/Remaining
-    // Define rows with label on left and value on right
-    lr := func(k string, v string) string {
-        return lipgloss.JoinHorizontal(lipgloss.Top,
-            label.Width(lblW).Render(k),
-            dim.Render(""),
-            value.Width(colW-lblW).Align(lipgloss.Right).Render(v),
-        )
-    }
-
-    // Daily request reset string
-    dailyReset := "Unknown"
-    if m.metrics.ResetRequestsDay > 0 {
-        resetDaily := time.Now().Add(time.Duration(m.metrics.ResetRequestsDay) * time.Second)
-        hours := int(m.metrics.ResetRequestsDay / 3600)
-        mins := int((m.metrics.ResetRequestsDay % 3600) / 60)
-        dailyReset = fmt.Sprintf("%s  (%dh %dm)", resetDaily.Format("15:04"), hours, mins)
-    }
-    // Minute token reset string
-    minuteReset := "Unknown"
-    if m.metrics.ResetTokensMinute > 0 {
-        resetMinute := time.Now().Add(time.Duration(m.metrics.ResetTokensMinute) * time.Second)
-        minuteReset = fmt.Sprintf("%s  (%ds)", resetMinute.Format("15:04:05"), int(m.metrics.ResetTokensMinute))
-    }
-
-    card2 := lipgloss.JoinVertical(lipgloss.Left,
-        title.Render("Quotas & Remaining"),
-        lr("Daily Limit", m.formatInt(m.metrics.LimitRequestsDay)),
-        lr("Daily Remaining", m.formatInt(m.metrics.RemainingRequestsDay)),
-        lr("Daily Reset", dailyReset),
-        "",
-        lr("Minute Limit", m.formatInt(m.metrics.LimitTokensMinute)),
-        lr("Minute Remaining", m.formatInt(m.metrics.RemainingTokensMinute)),
-        lr("Minute Reset", minuteReset),
-    )
-
-    var content string
-    if twoCols {
-        left := lipgloss.NewStyle().Width(colW).Render(card1)
-        right := lipgloss.NewStyle().Width(colW).Render(card2)
-        content = lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Repeat(" ", gap), right)
-    } else {
-        content = lipgloss.JoinVertical(lipgloss.Left, card1, "", card2)
-    }
-
-    return content
+	icons := config.GetIcons()
+	styles := GetStyles()
+
+	if errors.Is(m.err, cerebras.ErrModelNotFound) {
+		text := lipgloss.NewStyle().Foreground(styles.Palette.Warning).Bold(true).Render(fmt.Sprintf("%s Not showing any usage: %v", icons.Warning, m.err))
+		if !m.readOnly && m.source == nil {
+			text += "\n\n" + styles.Hint.Render(fmt.Sprintf("%s Press ", icons.Info)) + styles.Key.Render("m") + styles.Hint.Render(" to pick a model")
+		}
+		return text
+	}
+	if m.err != nil {
+		return styles.Error.Render(fmt.Sprintf("Error: %v", m.err))
+	}
+
+	if m.metrics == nil {
+		return fmt.Sprintf("%s Loading metrics...", icons.Info)
+	}
+
+	// Layout measurements
+	available := m.width - 2 // matches outer padding in View()
+	if available < 40 {
+		available = 40
+	}
+	gap := 2
+	// Use a slightly higher breakpoint so two columns only activate on comfortably wide terminals
+	twoCols := available >= 96
+	colW := available
+	if twoCols {
+		colW = (available - gap) / 2
+	}
+
+	// Base styles (muted, no backgrounds except progress bars)
+	title := styles.SectionTitle
+	label := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
+	value := lipgloss.NewStyle().Foreground(styles.Palette.Text).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
+
+	// Progress rows
+	// Layout: first line has label; second line has a full-width bar; third line has left-aligned percentage and right-aligned (used/limit).
+	lblW := 16
+	// Make the progress bar span the entire card column width including brackets
+	// createProgressBar wraps content in [ and ], so subtract 2 to avoid wrapping
+	barW := colW - 2
+	if barW < 10 {
+		barW = 10
+	}
+
+	now := time.Now()
+
+	// Helper to render a metric block (title, bar, stats), a usage row for
+	// unlimited windows, or Unknown when the limit is missing
+	// window: rate limit window used for the reset time, pace marker and label
+	renderMetric := func(icon, name, window string) []string {
+		// Allow natural width for the title to avoid forced wrapping
+		titleRow := label.Render(fmt.Sprintf("%s %s", icon, name))
+		w, err := m.metrics.Window(window)
+		if err != nil || !w.Limit.IsKnown() {
+			return []string{titleRow, dim.Render("Unknown")}
+		}
+		used := w.Used
+
+		// Build a centered middle area to optionally display reset information
+		center := ""
+		if est, ok := m.estimates[window]; ok {
+			w.Reset = est.Seconds(now)
+			center = dim.Render(fmt.Sprintf("resets in %s%s", est.Confidence.Marker(), m.formatResetTime(w.Reset)))
+		}
+		// joinStats lays out left, center and right blocks across the column
+		joinStats := func(left, center, right string) string {
+			leftW := lipgloss.Width(left)
+			rightW := lipgloss.Width(right)
+			centerW := lipgloss.Width(center)
+			fillerW := colW - leftW - rightW
+			if fillerW < 1 {
+				fillerW = 1
+			}
+			if centerW == 0 || centerW+2 > fillerW { // not enough room, omit center text
+				return lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Repeat(" ", fillerW), right)
+			}
+			// Center the middle text between left and right blocks
+			leftPad := (fillerW - centerW) / 2
+			rightPad := fillerW - centerW - leftPad
+			return lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				left,
+				strings.Repeat(" ", leftPad),
+				center,
+				strings.Repeat(" ", rightPad),
+				right,
+			)
+		}
+
+		// Unlimited windows have no bar, but usage still counts
+		if w.Limit.IsUnlimited() {
+			left := value.Render(w.Limit.String())
+			right := value.Render(fmt.Sprintf("(%s/%s)", m.formatInt(used), w.Limit))
+			return []string{titleRow, joinStats(left, center, right)}
+		}
+		percent := w.Percent()
+
+		// Pace compares usage with an even burn across the window
+		marker := -1.0
+		if pace, ok := pacing.ForWindow(w, now); ok {
+			marker = pace.Expected
+			paceText := pace.String()
+			paceW := colW - lipgloss.Width(titleRow)
+			if paceW > lipgloss.Width(paceText)+2 {
+				titleRow = lipgloss.JoinHorizontal(lipgloss.Top,
+					titleRow,
+					lipgloss.NewStyle().Width(paceW).Align(lipgloss.Right).Render(m.renderPace(pace)),
+				)
+			}
+		}
+		bar := m.createProgressBar(percent, barW, marker)
+		left := value.Render(fmt.Sprintf("%.1f%%", percent))
+		right := value.Render(fmt.Sprintf("(%s/%s)", m.formatInt(used), m.formatLimit(w.Limit)))
+		stats := joinStats(left, center, right)
+		return []string{titleRow, bar, stats}
+	}
+
+	// Card 1: Rate Limits (Requests & Tokens across minute/hour/day)
+	// Insert a blank line between metrics when in vertical (single column) layout
+	cardRows := []string{title.Render("Rate Limits")}
+	addMetric := func(rows []string) {
+		cardRows = append(cardRows, rows...)
+		if !twoCols {
+			cardRows = append(cardRows, "")
+		}
+	}
+	addMetric(renderMetric(icons.Request, "Requests/min", cerebras.WindowRequestsMinute))
+	addMetric(renderMetric(icons.Request, "Requests/hr", cerebras.WindowRequestsHour))
+	addMetric(renderMetric(icons.Request, "Requests/day", cerebras.WindowRequestsDay))
+	addMetric(renderMetric(icons.Token, "Tokens/min", cerebras.WindowTokensMinute))
+	addMetric(renderMetric(icons.Token, "Tokens/hr", cerebras.WindowTokensHour))
+	addMetric(renderMetric(icons.Token, "Tokens/day", cerebras.WindowTokensDay))
+	// Trim trailing blank in two-column mode already avoided; in vertical it's fine to end with a blank
+	card1 := lipgloss.JoinVertical(lipgloss.Left, cardRows...)
+
+	// Card 2: Quotas/Remaining
+	// Define rows with label on left and value on right
+	lr := func(k string, v string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Top,
+			label.Width(lblW).Render(k),
+			dim.Render(""),
+			value.Width(colW-lblW).Align(lipgloss.Right).Render(v),
+		)
+	}
+
+	// Daily request reset string
+	dailyReset := "Unknown"
+	if secs := m.resetSeconds(cerebras.WindowRequestsDay); secs > 0 {
+		resetDaily := time.Now().Add(time.Duration(secs) * time.Second)
+		hours := int(secs / 3600)
+		mins := int((secs % 3600) / 60)
+		dailyReset = fmt.Sprintf("%s%s  (%dh %dm)", m.resetMarker(cerebras.WindowRequestsDay), resetDaily.Format("15:04"), hours, mins)
+	}
+	// Minute token reset string
+	minuteReset := "Unknown"
+	if secs := m.resetSeconds(cerebras.WindowTokensMinute); secs > 0 {
+		resetMinute := time.Now().Add(time.Duration(secs) * time.Second)
+		minuteReset = fmt.Sprintf("%s%s  (%ds)", m.resetMarker(cerebras.WindowTokensMinute), resetMinute.Format("15:04:05"), int(secs))
+	}
+
+	card2 := lipgloss.JoinVertical(lipgloss.Left,
+		title.Render("Quotas & Remaining"),
+		lr("Daily Limit", m.formatLimit(m.metrics.LimitRequestsDay)),
+		lr("Daily Remaining", m.formatRemaining(m.metrics.LimitRequestsDay, m.metrics.RemainingRequestsDay)),
+		lr("Daily Reset", dailyReset),
+		"",
+		lr("Minute Limit", m.formatLimit(m.metrics.LimitTokensMinute)),
+		lr("Minute Remaining", m.formatRemaining(m.metrics.LimitTokensMinute, m.metrics.RemainingTokensMinute)),
+		lr("Minute Reset", minuteReset),
+	)
+
+	var content string
+	if twoCols {
+		left := lipgloss.NewStyle().Width(colW).Render(card1)
+		right := lipgloss.NewStyle().Width(colW).Render(card2)
+		content = lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Repeat(" ", gap), right)
+	} else {
+		content = lipgloss.JoinVertical(lipgloss.Left, card1, "", card2)
+	}
+
+	// Soft limit and rule warnings go above the cards so they are seen first
+	if len(m.breaches) > 0 || len(m.rules) > 0 {
+		warn := lipgloss.NewStyle().Foreground(styles.Palette.Warning).Bold(true)
+		critical := lipgloss.NewStyle().Foreground(styles.Palette.Error).Bold(true)
+		lines := make([]string, 0, len(m.breaches)+len(m.rules)+1)
+		for _, b := range m.breaches {
+			lines = append(lines, warn.Render(fmt.Sprintf("%s %s", icons.Warning, b.Message())))
+		}
+		for _, c := range m.rules {
+			style := warn
+			if c.Severity == notify.SeverityCritical {
+				style = critical
+			}
+			lines = append(lines, style.Render(fmt.Sprintf("%s %s", icons.Warning, c.Message)))
+		}
+		lines = append(lines, "", content)
+		content = lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	return content
 }
 
 // renderUsage renders the usage tab content
 func (m DashboardModel) renderUsage() string {
-    icons := config.GetIcons()
-    styles := GetStyles()
-
-    if m.metrics == nil {
-        return fmt.Sprintf("%s Loading usage data...", icons.Info)
-    }
+	icons := config.GetIcons()
+	styles := GetStyles()
 
-	// Calculate usage
-	var requestsUsed float64
-	if m.metrics.LimitRequestsDay > 0 {
-		requestsUsed = float64(m.metrics.LimitRequestsDay - m.metrics.RemainingRequestsDay)
+	if m.metrics == nil {
+		return fmt.Sprintf("%s Loading usage data...", icons.Info)
 	}
 
-	var tokensUsed float64
-	if m.metrics.LimitTokensMinute > 0 {
-		tokensUsed = float64(m.metrics.LimitTokensMinute - m.metrics.RemainingTokensMinute)
-	}
+	// Calculate usage
+	requests, _ := m.metrics.Window(cerebras.WindowRequestsDay)
+	tokens, _ := m.metrics.Window(cerebras.WindowTokensMinute)
 
 	// Create a table-like view for usage data
 	var s strings.Builder
@@ -440,50 +802,275 @@ func (m DashboardModel) renderUsage() string {
 
 	s.WriteString(headerStyle.Render("Metric") + headerStyle.Render("Used") + headerStyle.Render("Limit") + headerStyle.Render("Reset") + "\n")
 	s.WriteString(valueStyle.Render("Daily Requests") +
-		valueStyle.Render(fmt.Sprintf("%.0f", requestsUsed)) +
-		valueStyle.Render(fmt.Sprintf("%d", m.metrics.LimitRequestsDay)) +
-		valueStyle.Render(m.formatResetTime(m.metrics.ResetRequestsDay)) + "\n")
+		valueStyle.Render(fmt.Sprintf("%d", requests.Used)) +
+		valueStyle.Render(m.formatLimit(m.metrics.LimitRequestsDay)) +
+		valueStyle.Render(m.formatResetTime(m.resetSeconds(cerebras.WindowRequestsDay))) + "\n")
 	s.WriteString(valueStyle.Render("Minute Tokens") +
-		valueStyle.Render(fmt.Sprintf("%.0f", tokensUsed)) +
-		valueStyle.Render(fmt.Sprintf("%d", m.metrics.LimitTokensMinute)) +
-		valueStyle.Render(m.formatResetTime(m.metrics.ResetTokensMinute)) + "\n")
+		valueStyle.Render(fmt.Sprintf("%d", tokens.Used)) +
+		valueStyle.Render(m.formatLimit(m.metrics.LimitTokensMinute)) +
+		valueStyle.Render(m.formatResetTime(m.resetSeconds(cerebras.WindowTokensMinute))) + "\n")
+
+	if len(m.metrics.Regions) > 0 {
+		s.WriteString("\n" + styles.SectionTitle.Render("Usage by Region") + "\n\n")
+		windows := []string{cerebras.WindowRequestsDay, cerebras.WindowTokensMinute, cerebras.WindowTokensDay}
+		s.WriteString(m.renderRegionTable([]string{"Daily Requests", "Minute Tokens", "Daily Tokens"}, func(info *cerebras.RateLimitInfo, i int) string {
+			w, _ := info.Window(windows[i])
+			return fmt.Sprintf("%s/%s", m.formatInt(w.Used), m.formatLimit(w.Limit))
+		}))
+	}
+
+	if m.spend != nil {
+		s.WriteString("\n" + styles.SectionTitle.Render("Estimated Cost") + "\n\n")
+		s.WriteString(headerStyle.Render("Period") + headerStyle.Render("Spent") + headerStyle.Render("Budget") + "\n")
+		budget := func(limit float64) string {
+			if limit <= 0 {
+				return "-"
+			}
+			return billing.FormatAmount(limit, m.spend.Currency)
+		}
+		s.WriteString(valueStyle.Render("Today") +
+			valueStyle.Render(billing.FormatAmount(m.spend.Day, m.spend.Currency)) +
+			valueStyle.Render(budget(m.budgets.Daily)) + "\n")
+		s.WriteString(valueStyle.Render("This Week") +
+			valueStyle.Render(billing.FormatAmount(m.spend.Week, m.spend.Currency)) +
+			valueStyle.Render("-") + "\n")
+		s.WriteString(valueStyle.Render("This Month") +
+			valueStyle.Render(billing.FormatAmount(m.spend.Month, m.spend.Currency)) +
+			valueStyle.Render(budget(m.budgets.Monthly)) + "\n")
+		for _, alert := range m.budgetAlerts {
+			s.WriteString("\n" + styles.Error.Render(fmt.Sprintf("%s %s", icons.Warning, alert.Message(m.spend.Currency))))
+		}
+	}
 
 	return s.String()
 }
 
+// renderOverview renders one row per watched organization with its tightest
+// window
+func (m DashboardModel) renderOverview() string {
+	icons := config.GetIcons()
+	styles := GetStyles()
+	dim := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
+	value := lipgloss.NewStyle().Foreground(styles.Palette.Text).Bold(true)
+
+	nameW := 12
+	for _, w := range m.watched {
+		nameW = max(nameW, min(len([]rune(w.name)), 32))
+	}
+	barW := 20
+	now := time.Now()
+
+	rows := []string{
+		styles.SectionTitle.Render(fmt.Sprintf("%s Organizations (%d)", icons.Organization, len(m.watched))),
+		"",
+		"  " + styles.TableHeader.Render(fmt.Sprintf("%-*s  %-16s %s", nameW, "Organization", "Tightest", "Usage")),
+	}
+	for i, w := range m.watched {
+		name := w.name
+		if runes := []rune(name); len(runes) > nameW {
+			name = string(runes[:nameW-1]) + "…"
+		}
+		cursor := "  "
+		nameText := styles.ListItem.Render(fmt.Sprintf("%-*s", nameW, name))
+		if i == m.cursor {
+			cursor = styles.ListCursor.Render(">") + " "
+			nameText = styles.ListSelected.Render(fmt.Sprintf("%-*s", nameW, name))
+		}
+
+		var detail string
+		switch {
+		case w.snap == nil && w.err != nil:
+			detail = styles.Error.Render(fmt.Sprintf("Error: %v", w.err))
+		case w.snap == nil:
+			detail = dim.Render("Loading...")
+		default:
+			tightest, ok := w.snap.Metrics.Tightest()
+			if !ok {
+				detail = dim.Render(fmt.Sprintf("%-16s no numeric limits", "-"))
+				break
+			}
+			detail = fmt.Sprintf("%-16s %s %s", tightest.Name,
+				m.createProgressBar(tightest.Percent(), barW, -1),
+				lipgloss.NewStyle().Foreground(m.getStatusColor(tightest.Percent())).Bold(true).Render(fmt.Sprintf("%5.1f%%", tightest.Percent())))
+			detail += value.Render(fmt.Sprintf("  (%s/%s)", m.formatInt(tightest.Used), m.formatLimit(tightest.Limit)))
+			if est, ok := w.snap.Estimates[tightest.Name]; ok {
+				detail += dim.Render(fmt.Sprintf("  resets in %s%s", est.Confidence.Marker(), m.formatResetTime(est.Seconds(now))))
+			}
+			if w.err != nil {
+				detail += styles.Error.Render(fmt.Sprintf("  %s last refresh failed", icons.Warning))
+			}
+		}
+		rows = append(rows, cursor+nameText+"  "+detail)
+	}
+
+	rows = append(rows, "", styles.Hint.Render(fmt.Sprintf("%s ", icons.Info))+
+		styles.Key.Render("up/down")+styles.Hint.Render(": select  •  ")+
+		styles.Key.Render("enter")+styles.Hint.Render(": open dashboard  •  ")+
+		styles.Key.Render("esc")+styles.Hint.Render(": back to overview"))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
 // renderQuotas renders the quotas tab content
 func (m DashboardModel) renderQuotas() string {
-    icons := config.GetIcons()
+	icons := config.GetIcons()
 	styles := GetStyles()
 
+	if m.metrics == nil {
+		return fmt.Sprintf("%s Loading quotas...", icons.Info)
+	}
+
 	var s strings.Builder
-	s.WriteString(styles.SectionTitle.Render("Quotas Information") + "\n\n")
-	s.WriteString(fmt.Sprintf("%s Detailed quota information will be displayed here.\n", icons.Info))
-	s.WriteString(fmt.Sprintf("%s Currently showing basic rate limits from the Dashboard tab.\n", icons.Info))
+	s.WriteString(styles.SectionTitle.Render(fmt.Sprintf("Quotas of %s", m.modelName)) + "\n\n")
+	s.WriteString(m.renderRegionTable([]string{"Req/min", "Req/hour", "Req/day", "Tok/min", "Tok/hour", "Tok/day"}, func(info *cerebras.RateLimitInfo, i int) string {
+		return m.formatLimit(*info.LimitField(cerebras.Windows[i]))
+	}))
+	var sizes []string
+	if m.metrics.MaxSequenceLength > 0 {
+		sizes = append(sizes, "Max sequence length: "+m.formatInt(m.metrics.MaxSequenceLength))
+	}
+	if m.metrics.MaxCompletionTokens > 0 {
+		sizes = append(sizes, "Max completion tokens: "+m.formatInt(m.metrics.MaxCompletionTokens))
+	}
+	if len(sizes) > 0 {
+		s.WriteString(fmt.Sprintf("\n%s %s\n", icons.Info, strings.Join(sizes, "  •  ")))
+	}
+	if len(m.metrics.Regions) > 0 {
+		s.WriteString(fmt.Sprintf("\n%s The Dashboard tab sums every region; use --region to monitor one.\n", icons.Info))
+	}
 
 	return s.String()
 }
 
+// renderRegionTable renders one row per region, followed by their total when
+// the metrics are summed across regions. cell renders column i of a row.
+func (m DashboardModel) renderRegionTable(columns []string, cell func(info *cerebras.RateLimitInfo, i int) string) string {
+	styles := GetStyles()
+
+	rows := []*cerebras.RateLimitInfo{m.metrics}
+	if len(m.metrics.Regions) > 0 {
+		rows = rows[:0]
+		for i := range m.metrics.Regions {
+			rows = append(rows, &m.metrics.Regions[i])
+		}
+		rows = append(rows, m.metrics)
+	}
+	name := func(info *cerebras.RateLimitInfo) string {
+		switch {
+		case info == m.metrics && len(m.metrics.Regions) > 0:
+			return "All regions"
+		case info.RegionId == "":
+			return "-"
+		default:
+			return info.RegionId
+		}
+	}
+
+	nameW := len("All regions")
+	for _, info := range rows {
+		nameW = max(nameW, len(name(info)))
+	}
+	cells := make([][]string, len(rows))
+	colW := make([]int, len(columns))
+	for c, title := range columns {
+		colW[c] = len(title)
+	}
+	for r, info := range rows {
+		for c := range columns {
+			cells[r] = append(cells[r], cell(info, c))
+			colW[c] = max(colW[c], lipgloss.Width(cells[r][c]))
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(styles.TableHeader.Width(nameW + 2).Render("Region"))
+	for c, title := range columns {
+		s.WriteString(styles.TableHeader.Width(colW[c] + 2).Render(title))
+	}
+	s.WriteString("\n")
+	for r, info := range rows {
+		style := styles.TableCell
+		if info == m.metrics && len(m.metrics.Regions) > 0 {
+			style = styles.TableHeader
+		}
+		s.WriteString(style.Width(nameW + 2).Render(name(info)))
+		for c := range columns {
+			s.WriteString(style.Width(colW[c] + 2).Render(cells[r][c]))
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
 // renderSettings renders the settings tab content
 func (m DashboardModel) renderSettings() string {
-    icons := config.GetIcons()
+	icons := config.GetIcons()
 	styles := GetStyles()
 
 	var s strings.Builder
 	s.WriteString(styles.SectionTitle.Render("Settings") + "\n\n")
 	s.WriteString(fmt.Sprintf("%s Refresh Rate: %d seconds\n", icons.Time, m.refreshRate))
 	s.WriteString(fmt.Sprintf("%s Organization: %s\n", icons.Organization, m.organization))
-	s.WriteString(fmt.Sprintf("%s Model: %s\n\n", icons.Model, m.modelName))
+	s.WriteString(fmt.Sprintf("%s Model: %s\n", icons.Model, m.modelName))
+	region := m.monitor.Client().Region()
+	if region == cerebras.AllRegions {
+		region = "all regions, summed"
+	}
+	s.WriteString(fmt.Sprintf("%s Region: %s\n\n", icons.Organization, region))
+	if m.readOnly {
+		s.WriteString(fmt.Sprintf("%s Read-only session: the organization and model are set by the host\n\n", icons.Info))
+	}
 	s.WriteString("Available controls:\n")
 	s.WriteString(fmt.Sprintf("  %s q/ctrl+c: Quit\n", icons.Error))
 	s.WriteString(fmt.Sprintf("  %s tab: Switch tabs\n", icons.Theme))
 	s.WriteString(fmt.Sprintf("  %s r: Refresh data\n", icons.Refresh))
+	if len(m.watched) > 0 {
+		s.WriteString(fmt.Sprintf("  %s up/down, enter: Open an organization from the Overview tab\n", icons.Organization))
+		s.WriteString(fmt.Sprintf("  %s esc: Back to the Overview tab\n", icons.Dashboard))
+	}
+	if !m.readOnly && m.source == nil {
+		s.WriteString(fmt.Sprintf("  %s m: Pick the model to monitor\n", icons.Model))
+		s.WriteString(fmt.Sprintf("  %s o: Switch organization, s in the list also saves it\n", icons.Organization))
+	}
+	if !m.readOnly {
+		s.WriteString(fmt.Sprintf("  %s z: Snooze alert notifications for an hour, again to resume\n", icons.Warning))
+	}
 
 	return s.String()
 }
 
-// createProgressBar creates a visual progress bar
-func (m DashboardModel) createProgressBar(percent float64, width int) string {
+// resetSeconds returns the seconds until the window resets, estimated when
+// the API does not report it
+func (m DashboardModel) resetSeconds(window string) int64 {
+	if est, ok := m.estimates[window]; ok {
+		return est.Seconds(time.Now())
+	}
+	return *m.metrics.Field(cerebras.FieldReset, window)
+}
+
+// resetMarker returns the confidence marker of the window's reset estimate
+func (m DashboardModel) resetMarker(window string) string {
+	if est, ok := m.estimates[window]; ok {
+		return est.Confidence.Marker()
+	}
+	return ""
+}
+
+// renderPace renders the pace label, highlighting usage well ahead of pace
+func (m DashboardModel) renderPace(pace pacing.Pace) string {
+	styles := GetStyles()
+	color := styles.Palette.Subtle
+	switch delta := pace.Delta(); {
+	case delta >= 25:
+		color = styles.Palette.Error
+	case delta >= 10:
+		color = styles.Palette.Warning
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(pace.String())
+}
+
+// createProgressBar creates a visual progress bar. marker is the percent at
+// which the expected-pace marker is drawn, negative to omit it.
+func (m DashboardModel) createProgressBar(percent float64, width int, marker float64) string {
 	if width <= 0 {
 		return ""
 	}
@@ -512,6 +1099,23 @@ func (m DashboardModel) createProgressBar(percent float64, width int) string {
 	filledStyled := lipgloss.NewStyle().Foreground(fillColor).Render(filledStr)
 	emptyStyled := styles.ProgressEmpty.Render(emptyStr)
 
+	if marker >= 0 && marker <= 100 {
+		pos := int(marker / 100 * float64(width))
+		if pos >= width {
+			pos = width - 1
+		}
+		markerStyled := lipgloss.NewStyle().Foreground(styles.Palette.Text).Render("│")
+		if pos < filled {
+			filledStyled = lipgloss.NewStyle().Foreground(fillColor).Render(strings.Repeat("█", pos)) +
+				markerStyled +
+				lipgloss.NewStyle().Foreground(fillColor).Render(strings.Repeat("█", filled-pos-1))
+		} else {
+			emptyStyled = styles.ProgressEmpty.Render(strings.Repeat("░", pos-filled)) +
+				markerStyled +
+				styles.ProgressEmpty.Render(strings.Repeat("░", empty-(pos-filled)-1))
+		}
+	}
+
 	return "[" + filledStyled + emptyStyled + "]"
 }
 
@@ -549,30 +1153,46 @@ func (m DashboardModel) formatResetTime(seconds int64) string {
 	return fmt.Sprintf("%dh%dm", hours, minutes)
 }
 
+// formatLimit renders a limit with thousands separators, "∞" or "Unknown"
+func (m DashboardModel) formatLimit(l cerebras.Limit) string {
+	if v, ok := l.Value(); ok {
+		return m.formatInt(v)
+	}
+	return l.String()
+}
+
+// formatRemaining renders the remaining count, which is "∞" for unlimited windows
+func (m DashboardModel) formatRemaining(l cerebras.Limit, remaining int64) string {
+	if l.IsUnlimited() {
+		return l.String()
+	}
+	return m.formatInt(remaining)
+}
+
 // formatInt prints an int64 with thousands separators for readability
 func (m DashboardModel) formatInt(n int64) string {
-    // Handle sign
-    sign := ""
-    if n < 0 {
-        sign = "-"
-        n = -n
-    }
-    s := strconv.FormatInt(n, 10)
-    // Group digits in threes from the right
-    l := len(s)
-    if l <= 3 {
-        return sign + s
-    }
-    first := l % 3
-    if first == 0 {
-        first = 3
-    }
-    var b strings.Builder
-    b.Grow(l + (l-1)/3)
-    b.WriteString(s[:first])
-    for i := first; i < l; i += 3 {
-        b.WriteByte(',')
-        b.WriteString(s[i : i+3])
-    }
-    return sign + b.String()
+	// Handle sign
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	s := strconv.FormatInt(n, 10)
+	// Group digits in threes from the right
+	l := len(s)
+	if l <= 3 {
+		return sign + s
+	}
+	first := l % 3
+	if first == 0 {
+		first = 3
+	}
+	var b strings.Builder
+	b.Grow(l + (l-1)/3)
+	b.WriteString(s[:first])
+	for i := first; i < l; i += 3 {
+		b.WriteByte(',')
+		b.WriteString(s[i : i+3])
+	}
+	return sign + b.String()
 }
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+)
+
+// modelPicker lists the models the organization has a quota for, with their
+// current usage, to switch the dashboard to one of them
+type modelPicker struct {
+	models []*cerebras.RateLimitInfo
+	cursor int
+	err    error
+}
+
+// modelsMsg holds the models of the organization for the picker
+type modelsMsg struct {
+	models []*cerebras.RateLimitInfo
+	err    error
+}
+
+// openModelPicker opens the picker and lists the models in the background
+func (m DashboardModel) openModelPicker() (DashboardModel, tea.Cmd) {
+	m.picker = &modelPicker{}
+	client := m.monitor.Client()
+	if client.SessionToken() == "" || m.organization == "" {
+		m.picker.err = errors.New("picking a model needs session token authentication and an organization")
+		return m, nil
+	}
+	organization := m.organization
+	return m, func() tea.Msg {
+		models, err := client.ListModelMetrics(m.ctx, organization)
+		return modelsMsg{models: models, err: err}
+	}
+}
+
+// showModels fills the picker with one row per model, the cursor on the
+// current one
+func (m DashboardModel) showModels(msg modelsMsg) DashboardModel {
+	if m.picker == nil {
+		return m
+	}
+	picker := &modelPicker{models: cerebras.MergeRegions(msg.models), err: msg.err}
+	if msg.err == nil && len(picker.models) == 0 {
+		picker.err = errors.New("the organization has no model quotas")
+	}
+	picker.cursor = max(0, slices.IndexFunc(picker.models, func(info *cerebras.RateLimitInfo) bool {
+		return info.ModelId == m.modelName
+	}))
+	m.picker = picker
+	return m
+}
+
+// updateModelPicker handles keys while the picker is open
+func (m DashboardModel) updateModelPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.quitting = true
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "m":
+		m.picker = nil
+	case "up", "k":
+		if m.picker.cursor > 0 {
+			m.picker.cursor--
+		}
+	case "down", "j":
+		if m.picker.cursor < len(m.picker.models)-1 {
+			m.picker.cursor++
+		}
+	case "enter":
+		if len(m.picker.models) == 0 {
+			return m, nil
+		}
+		model := m.picker.models[m.picker.cursor].ModelId
+		m.picker = nil
+		if model == m.modelName && !errors.Is(m.err, cerebras.ErrModelNotFound) {
+			return m, nil
+		}
+		m = m.switchModel(model)
+		return m, m.fetchMetrics()
+	}
+	return m, nil
+}
+
+// switchModel shows another model in every tab. Watched organizations
+// switch too, so the Overview compares the same model across them.
+func (m DashboardModel) switchModel(model string) DashboardModel {
+	m.monitor = m.monitorFor(m.monitor, m.organization, model)
+	m.modelName = model
+	m.metrics, m.spend, m.budgetAlerts, m.breaches, m.rules, m.estimates = nil, nil, nil, nil, nil, nil
+	m.snoozedUntil = time.Time{}
+	m.err = nil
+
+	m.watched = slices.Clone(m.watched)
+	for i := range m.watched {
+		w := &m.watched[i]
+		w.monitor = m.monitorFor(w.monitor, w.monitor.Organization(), model)
+		w.snap, w.err = nil, nil
+	}
+	return m
+}
+
+// monitorFor returns the monitor of the organization and model, derived from
+// mon. Monitors created by earlier switches are reused so their alert state
+// survives switching back.
+func (m DashboardModel) monitorFor(mon *monitor.Monitor, organization, model string) *monitor.Monitor {
+	if mon.Organization() == organization && mon.Model() == model {
+		return mon
+	}
+	key := func(organization, model string) string { return organization + "\x00" + model }
+	if _, ok := m.monitors[key(mon.Organization(), mon.Model())]; !ok {
+		m.monitors[key(mon.Organization(), mon.Model())] = mon
+	}
+	if other, ok := m.monitors[key(organization, model)]; ok {
+		return other
+	}
+	other := mon.For(organization, model)
+	m.monitors[key(organization, model)] = other
+	return other
+}
+
+// renderModelPicker renders the picker in place of the active tab
+func (m DashboardModel) renderModelPicker() string {
+	icons := config.GetIcons()
+	styles := GetStyles()
+	dim := lipgloss.NewStyle().Foreground(styles.Palette.Subtle)
+
+	rows := []string{styles.SectionTitle.Render(fmt.Sprintf("%s Select a model:", icons.Model)), ""}
+	switch {
+	case m.picker.err != nil:
+		rows = append(rows, styles.Error.Render(fmt.Sprintf("Error: %v", m.picker.err)))
+	case m.picker.models == nil:
+		rows = append(rows, fmt.Sprintf("%s Loading models...", icons.Info))
+	}
+
+	nameW := 12
+	for _, info := range m.picker.models {
+		nameW = max(nameW, len(info.ModelId))
+	}
+	for i, info := range m.picker.models {
+		line := fmt.Sprintf("%-*s", nameW, info.ModelId)
+		if i == m.picker.cursor {
+			line = styles.ListCursor.Render(">") + " " + styles.ListSelected.Render(line)
+		} else {
+			line = "  " + styles.ListItem.Render(line)
+		}
+		if tightest, ok := info.Tightest(); ok {
+			line += "  " + lipgloss.NewStyle().Foreground(m.getStatusColor(tightest.Percent())).Bold(true).Render(fmt.Sprintf("%5.1f%%", tightest.Percent())) +
+				dim.Render(" "+tightest.Name)
+		} else {
+			line += "  " + dim.Render("no numeric limits")
+		}
+		if info.ModelId == m.modelName {
+			line += dim.Render("  (current)")
+		}
+		rows = append(rows, line)
+	}
+
+	rows = append(rows, "", styles.Hint.Render(fmt.Sprintf("%s ", icons.Info))+
+		styles.Key.Render("up/down")+styles.Hint.Render(": navigate  •  ")+
+		styles.Key.Render("enter")+styles.Hint.Render(": switch  •  ")+
+		styles.Key.Render("esc")+styles.Hint.Render(": cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
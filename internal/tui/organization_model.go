@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
-	"path/filepath"
+	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
 	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
 	"github.com/spf13/viper"
@@ -15,6 +17,11 @@ type OrganizationListModel struct {
 	Organizations []cerebras.Organization
 	Cursor        int
 	Selected      map[int]struct{}
+	// multi selects the organizations to watch instead of the one to monitor
+	multi bool
+	// embedded lists the organizations inside the dashboard (see Embedded)
+	embedded bool
+	err      error
 }
 
 // NewOrganizationListModel creates a new organization list model
@@ -25,6 +32,44 @@ func NewOrganizationListModel(orgs []cerebras.Organization) OrganizationListMode
 	}
 }
 
+// WithMultiSelect picks the organizations the dashboard watches, starting
+// from the given IDs, and saves them as the watch list
+func (m OrganizationListModel) WithMultiSelect(watched []string) OrganizationListModel {
+	m.multi = true
+	for i, org := range m.Organizations {
+		if slices.Contains(watched, org.ID) {
+			m.Selected[i] = struct{}{}
+		}
+	}
+	return m
+}
+
+// Embedded opens the list inside the dashboard, with the cursor on the
+// current organization. Picking one switches the dashboard to it instead of
+// saving it and quitting; s also saves it as the organization to monitor.
+func (m OrganizationListModel) Embedded(current string) OrganizationListModel {
+	m.embedded = true
+	m.Cursor = max(0, slices.IndexFunc(m.Organizations, func(org cerebras.Organization) bool {
+		return org.ID == current
+	}))
+	return m
+}
+
+// organizationPickedMsg reports the organization picked in an embedded list
+type organizationPickedMsg struct {
+	organization cerebras.Organization
+	save         bool
+}
+
+// organizationListClosedMsg reports that an embedded list was closed
+type organizationListClosedMsg struct{}
+
+// organizationActive reports whether the organization can be used; an
+// organization without a state is assumed active
+func organizationActive(org cerebras.Organization) bool {
+	return org.State == "" || strings.EqualFold(org.State, "active")
+}
+
 // Init initializes the model
 func (m OrganizationListModel) Init() tea.Cmd {
 	// Just return `nil`, which means "no I/O right now, please."
@@ -35,6 +80,9 @@ func (m OrganizationListModel) Init() tea.Cmd {
 func (m OrganizationListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.embedded {
+			return m.updateEmbedded(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -46,60 +94,159 @@ func (m OrganizationListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.Cursor < len(m.Organizations)-1 {
 				m.Cursor++
 			}
-		case "enter", " ":
-			// Select the organization
-			org := m.Organizations[m.Cursor]
-
-			// Save the organization ID to configuration
-			viper.Set("org-id", org.ID)
-			if err := viper.WriteConfig(); err != nil {
-				// If config file doesn't exist, create it
-				if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-					configDir := config.GetConfigDir()
-					configPath := filepath.Join(configDir, "settings.yaml")
-					if err := viper.WriteConfigAs(configPath); err != nil {
-						fmt.Printf("Error saving configuration: %v\n", err)
-						return m, tea.Quit
-					}
-				} else {
-					fmt.Printf("Error saving configuration: %v\n", err)
-					return m, tea.Quit
-				}
+		case " ":
+			if !m.multi {
+				return m.selectOrganization()
 			}
-
-			fmt.Printf("Organization %s selected and saved to configuration.\n", org.Name)
-			return m, tea.Quit
+			if _, ok := m.Selected[m.Cursor]; ok {
+				delete(m.Selected, m.Cursor)
+			} else {
+				m.Selected[m.Cursor] = struct{}{}
+			}
+		case "enter":
+			if m.multi {
+				return m.saveWatchList()
+			}
+			return m.selectOrganization()
 		}
 	}
 
 	return m, nil
 }
 
+// updateEmbedded handles keys when the list is inside the dashboard, which
+// handles quitting
+func (m OrganizationListModel) updateEmbedded(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pick := func(save bool) (tea.Model, tea.Cmd) {
+		if len(m.Organizations) == 0 {
+			return m, nil
+		}
+		org := m.Organizations[m.Cursor]
+		return m, func() tea.Msg { return organizationPickedMsg{organization: org, save: save} }
+	}
+	switch msg.String() {
+	case "esc", "o":
+		return m, func() tea.Msg { return organizationListClosedMsg{} }
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Organizations)-1 {
+			m.Cursor++
+		}
+	case "enter", " ":
+		return pick(false)
+	case "s":
+		return pick(true)
+	}
+	return m, nil
+}
+
+// selectOrganization saves the organization under the cursor as the one to
+// monitor
+func (m OrganizationListModel) selectOrganization() (tea.Model, tea.Cmd) {
+	org := m.Organizations[m.Cursor]
+
+	// Save the organization ID to configuration
+	viper.Set("org-id", org.ID)
+	if err := config.Save(); err != nil {
+		fmt.Printf("Error saving configuration: %v\n", err)
+		return m, tea.Quit
+	}
+
+	fmt.Printf("Organization %s selected and saved to configuration.\n", org.Name)
+	return m, tea.Quit
+}
+
+// saveWatchList saves the selected organizations as the watch list
+func (m OrganizationListModel) saveWatchList() (tea.Model, tea.Cmd) {
+	ids := []string{}
+	for i, org := range m.Organizations {
+		if _, ok := m.Selected[i]; ok {
+			ids = append(ids, org.ID)
+		}
+	}
+
+	viper.Set(config.WatchKey, ids)
+	if err := config.Save(); err != nil {
+		fmt.Printf("Error saving configuration: %v\n", err)
+		return m, tea.Quit
+	}
+
+	fmt.Printf("Watching %d organization(s), saved to configuration.\n", len(ids))
+	return m, tea.Quit
+}
+
 // View renders the model
 func (m OrganizationListModel) View() string {
 	icons := config.GetIcons()
 	styles := GetStyles()
 
 	// Header
-	s := styles.SectionTitle.Render(fmt.Sprintf("%s Select an organization:", icons.Organization)) + "\n\n"
+	header := "Select an organization:"
+	if m.multi {
+		header = "Select the organizations to watch:"
+	}
+	s := styles.SectionTitle.Render(fmt.Sprintf("%s %s", icons.Organization, header)) + "\n\n"
 
 	// List
+	switch {
+	case m.err != nil:
+		s += styles.Error.Render(fmt.Sprintf("Error: %v", m.err)) + "\n"
+	case m.embedded && m.Organizations == nil:
+		s += fmt.Sprintf("%s Loading organizations...\n", icons.Info)
+	case m.embedded && len(m.Organizations) == 0:
+		s += fmt.Sprintf("%s No organizations found.\n", icons.Info)
+	}
+	inactive := lipgloss.NewStyle().Foreground(styles.Palette.Subtle).Faint(true)
 	for i, org := range m.Organizations {
 		line := fmt.Sprintf("%s (ID: %s)", org.Name, org.ID)
-		if m.Cursor == i {
+		if m.multi {
+			check := "[ ]"
+			if _, ok := m.Selected[i]; ok {
+				check = "[x]"
+			}
+			line = check + " " + line
+		}
+		var details []string
+		for _, d := range []string{org.OrganizationType, org.State} {
+			if d != "" {
+				details = append(details, strings.ToLower(d))
+			}
+		}
+		detail := ""
+		if len(details) > 0 {
+			detail = styles.Hint.Render("  " + strings.Join(details, " · "))
+		}
+		switch {
+		case m.Cursor == i:
 			// cursor and selected style
 			cursor := styles.ListCursor.Render(">")
 			line = cursor + " " + styles.ListSelected.Render(line)
-		} else {
+		case !organizationActive(org):
+			line = "  " + inactive.Render(line)
+		default:
 			line = "  " + styles.ListItem.Render(line)
 		}
-		s += line + "\n"
+		s += line + detail + "\n"
 	}
 
 	// Hints
-	s += "\n" + styles.Hint.Render(fmt.Sprintf("%s ", icons.Info)) +
-		styles.Key.Render("enter/space") + styles.Hint.Render(": select  •  ") +
-		styles.Key.Render("up/down") + styles.Hint.Render(": navigate  •  ") +
+	s += "\n" + styles.Hint.Render(fmt.Sprintf("%s ", icons.Info))
+	if m.embedded {
+		return s + styles.Key.Render("enter") + styles.Hint.Render(": switch  •  ") +
+			styles.Key.Render("s") + styles.Hint.Render(": switch and save  •  ") +
+			styles.Key.Render("up/down") + styles.Hint.Render(": navigate  •  ") +
+			styles.Key.Render("esc") + styles.Hint.Render(": cancel") + "\n"
+	}
+	if m.multi {
+		s += styles.Key.Render("space") + styles.Hint.Render(": toggle  •  ") +
+			styles.Key.Render("enter") + styles.Hint.Render(": save  •  ")
+	} else {
+		s += styles.Key.Render("enter/space") + styles.Hint.Render(": select  •  ")
+	}
+	s += styles.Key.Render("up/down") + styles.Hint.Render(": navigate  •  ") +
 		styles.Key.Render("q") + styles.Hint.Render(": quit") + "\n"
 
 	return s
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/config"
+	"github.com/spf13/viper"
+)
+
+// organizationsMsg holds the organizations of the session for the switcher
+type organizationsMsg struct {
+	organizations []cerebras.Organization
+	err           error
+}
+
+// organizationSavedMsg reports the outcome of saving the organization
+type organizationSavedMsg struct {
+	err error
+}
+
+// openOrganizations opens the organization list and fetches it in the
+// background
+func (m DashboardModel) openOrganizations() (DashboardModel, tea.Cmd) {
+	list := NewOrganizationListModel(nil).Embedded(m.organization)
+	m.organizations = &list
+	client := m.monitor.Client()
+	if client.SessionToken() == "" {
+		m.organizations.err = errors.New("switching organizations needs session token authentication")
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		orgs, err := client.GraphQL().ListOrganizations(m.ctx)
+		return organizationsMsg{organizations: orgs, err: err}
+	}
+}
+
+// showOrganizations fills the organization list
+func (m DashboardModel) showOrganizations(msg organizationsMsg) DashboardModel {
+	if m.organizations == nil {
+		return m
+	}
+	orgs := msg.organizations
+	if orgs == nil {
+		orgs = []cerebras.Organization{}
+	}
+	list := NewOrganizationListModel(orgs).Embedded(m.organization)
+	list.err = msg.err
+	m.organizations = &list
+	return m
+}
+
+// updateOrganizations handles keys while the organization list is open
+func (m DashboardModel) updateOrganizations(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.quitting = true
+		m.cancel()
+		return m, tea.Quit
+	}
+	list, cmd := m.organizations.Update(msg)
+	updated := list.(OrganizationListModel)
+	m.organizations = &updated
+	return m, cmd
+}
+
+// switchOrganization shows another organization in every tab with the
+// current model, optionally saving it as the organization to monitor
+func (m DashboardModel) switchOrganization(org cerebras.Organization, save bool) (DashboardModel, tea.Cmd) {
+	m.organizations = nil
+	var cmds []tea.Cmd
+	if save {
+		cmds = append(cmds, func() tea.Msg {
+			viper.Set("org-id", org.ID)
+			return organizationSavedMsg{err: config.Save()}
+		})
+	}
+	if org.ID == m.organization {
+		return m, tea.Batch(cmds...)
+	}
+
+	if i := slices.IndexFunc(m.watched, func(w watchedOrganization) bool { return w.monitor.Organization() == org.ID }); i >= 0 {
+		m.cursor = i
+		m = m.openOrganization(i)
+	} else {
+		m.monitor = m.monitorFor(m.monitor, org.ID, m.modelName)
+		m.organization = org.ID
+		m.budgets = m.monitor.Budgets()
+		m.metrics, m.spend, m.budgetAlerts, m.breaches, m.rules, m.estimates = nil, nil, nil, nil, nil, nil
+		m.snoozedUntil = time.Time{}
+		m.err = nil
+		if m.tabs[m.activeTab] == "Overview" {
+			m.activeTab = slices.Index(m.tabs, "Dashboard")
+		}
+	}
+	return m, tea.Batch(append(cmds, m.fetchMetrics())...)
+}
+
+// savedOrganization reports a failure to save the organization
+func (m DashboardModel) savedOrganization(msg organizationSavedMsg) DashboardModel {
+	if msg.err != nil {
+		m.err = fmt.Errorf("saving organization: %w", msg.err)
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// tokenCookie keeps a token given in the URL for the requests of the page
+const tokenCookie = "cerebras_monitor_token"
+
+// Auth protects the dashboard with a bearer token, basic auth or both. The
+// zero value allows every request.
+type Auth struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// Enabled reports whether requests must authenticate
+func (a Auth) Enabled() bool {
+	return a.Token != "" || a.Username != ""
+}
+
+// Wrap returns a handler that rejects unauthenticated requests. The token is
+// accepted as a bearer token, a ?token= parameter or the cookie set after a
+// ?token= parameter, so a link with the token opens the dashboard and its
+// event stream; basic auth credentials are checked otherwise.
+func (a Auth) Wrap(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.Token != "" {
+			if token := r.URL.Query().Get("token"); token != "" && equal(token, a.Token) {
+				http.SetCookie(w, &http.Cookie{
+					Name:     tokenCookie,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					SameSite: http.SameSiteStrictMode,
+				})
+				next.ServeHTTP(w, r)
+				return
+			}
+			if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && equal(token, a.Token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if c, err := r.Cookie(tokenCookie); err == nil && equal(c.Value, a.Token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if a.Username != "" {
+			if user, pass, ok := r.BasicAuth(); ok && equal(user, a.Username) && equal(pass, a.Password) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="cerebras-monitor", charset="UTF-8"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// equal compares secrets in constant time, regardless of their lengths
+func equal(given, want string) bool {
+	g := sha256.Sum256([]byte(given))
+	w := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(g[:], w[:]) == 1
+}
@@ -0,0 +1,253 @@
+// Dashboard page: renders /api/dashboard, kept live by the /api/stream
+// server-sent events, with usage_metrics charts and alerts.
+"use strict";
+
+const state = { dashboard: null, receivedAt: 0 };
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key === "class") node.className = value;
+    else if (key === "style") node.style.cssText = value;
+    else node.setAttribute(key, value);
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : document.createTextNode(String(child)));
+  }
+  return node;
+}
+
+function svg(tag, attrs) {
+  const node = document.createElementNS("http://www.w3.org/2000/svg", tag);
+  for (const [key, value] of Object.entries(attrs || {})) node.setAttribute(key, value);
+  return node;
+}
+
+// formatInt prints an integer with thousands separators
+function formatInt(n) {
+  return Math.round(n).toLocaleString("en-US");
+}
+
+// formatLimit renders a limit as a number, "∞" or "Unknown"
+function formatLimit(limit) {
+  if (limit === "unlimited") return "∞";
+  if (limit === null || limit === undefined) return "Unknown";
+  return formatInt(limit);
+}
+
+// formatResetTime formats seconds as 42s, 17m or 3h12m
+function formatResetTime(seconds) {
+  if (seconds <= 0) return "Unknown";
+  if (seconds < 60) return `${seconds}s`;
+  if (seconds < 3600) return `${Math.floor(seconds / 60)}m`;
+  return `${Math.floor(seconds / 3600)}h${Math.floor((seconds % 3600) / 60)}m`;
+}
+
+function formatAmount(amount, currency) {
+  try {
+    return new Intl.NumberFormat(undefined, { style: "currency", currency }).format(amount);
+  } catch (e) {
+    return `${amount.toFixed(2)} ${currency}`;
+  }
+}
+
+// statusClass matches the bar colors of the terminal dashboard
+function statusClass(percent) {
+  if (percent > 90) return "red";
+  if (percent > 75) return "orange";
+  return "";
+}
+
+// resetSeconds counts the window's reset down since the dashboard arrived
+function resetSeconds(w) {
+  if (w.reset_seconds <= 0) return 0;
+  const elapsed = Math.floor((Date.now() - state.receivedAt) / 1000);
+  return Math.max(w.reset_seconds - elapsed, 0);
+}
+
+function renderWindow(w) {
+  const title = el("div", { class: "title" }, el("span", { class: "subtle" }, w.label));
+  const node = el("div", { class: "metric" }, title);
+  if (w.limit === null) {
+    node.append(el("div", { class: "subtle" }, "Unknown"));
+    return node;
+  }
+
+  const reset = resetSeconds(w);
+  const center = reset > 0 ? el("span", { class: "subtle" }, `resets in ${w.reset_marker || ""}${formatResetTime(reset)}`) : el("span");
+  if (w.limit === "unlimited") {
+    node.append(el("div", { class: "stats" },
+      el("span", { class: "value" }, "∞"), center,
+      el("span", { class: "value" }, `(${formatInt(w.used)}/∞)`)));
+    return node;
+  }
+
+  if (w.pace) {
+    const paceClass = w.pace_delta >= 25 ? "critical" : w.pace_delta >= 10 ? "warning" : "subtle";
+    title.append(el("span", { class: paceClass }, w.pace));
+  }
+  const percent = Math.min(Math.max(w.percent, 0), 100);
+  const bar = el("div", { class: "bar" }, el("div", { class: `fill ${statusClass(percent)}`, style: `width: ${percent}%` }));
+  if (w.expected !== undefined) {
+    bar.append(el("div", { class: "marker", style: `left: calc(${Math.min(w.expected, 100)}% - 1px)` }));
+  }
+  node.append(bar, el("div", { class: "stats" },
+    el("span", { class: "value" }, `${w.percent.toFixed(1)}%`), center,
+    el("span", { class: "value" }, `(${formatInt(w.used)}/${formatLimit(w.limit)})`)));
+  return node;
+}
+
+function quotaRows(d) {
+  const find = (name) => d.windows.find((w) => w.name === name);
+  const remaining = (w) => (w.limit === "unlimited" ? "∞" : formatInt(w.remaining));
+  const rows = [];
+  const day = find("requests-day");
+  if (day) {
+    const reset = resetSeconds(day);
+    const at = new Date(Date.now() + reset * 1000).toLocaleTimeString([], { hour: "2-digit", minute: "2-digit" });
+    rows.push(["Daily Limit", formatLimit(day.limit)], ["Daily Remaining", remaining(day)],
+      ["Daily Reset", reset > 0 ? `${day.reset_marker || ""}${at}  (${Math.floor(reset / 3600)}h ${Math.floor((reset % 3600) / 60)}m)` : "Unknown"]);
+  }
+  const minute = find("tokens-minute");
+  if (minute) {
+    const reset = resetSeconds(minute);
+    const at = new Date(Date.now() + reset * 1000).toLocaleTimeString();
+    rows.push(["Minute Limit", formatLimit(minute.limit)], ["Minute Remaining", remaining(minute)],
+      ["Minute Reset", reset > 0 ? `${minute.reset_marker || ""}${at}  (${reset}s)` : "Unknown"]);
+  }
+  return rows;
+}
+
+function renderList(node, rows) {
+  node.replaceChildren(...rows.flatMap(([key, value]) => [el("dt", {}, key), el("dd", {}, value)]));
+}
+
+function render() {
+  const d = state.dashboard;
+  if (!d) return;
+  $("status").hidden = true;
+  $("meta").textContent = `${d.organization ? d.organization + " · " : ""}${d.model} · updated ${new Date(d.fetched_at).toLocaleTimeString()}`;
+
+  $("warnings").replaceChildren(
+    ...d.warnings.map((msg) => el("li", { class: "warning" }, `⚠ ${msg}`)),
+    ...d.budget_alerts.map((a) => el("li", { class: a.severity === "critical" ? "critical" : "warning" }, `⚠ ${a.message}`)));
+
+  $("windows").replaceChildren(...d.windows.map(renderWindow));
+  renderList($("quotas"), quotaRows(d));
+
+  const spend = d.spend;
+  $("spend-title").hidden = !spend;
+  $("cost").textContent = "";
+  if (spend) {
+    const budget = (limit) => (limit > 0 ? formatAmount(limit, spend.currency) : "-");
+    renderList($("spend"), [
+      ["Today", `${formatAmount(spend.day, spend.currency)} / ${budget(d.daily_budget)}`],
+      ["This Week", formatAmount(spend.week, spend.currency)],
+      ["This Month", `${formatAmount(spend.month, spend.currency)} / ${budget(d.monthly_budget)}`],
+    ]);
+    $("cost").textContent = `Today: ${formatAmount(spend.day, spend.currency)}`;
+    $("cost").className = d.budget_alerts.some((a) => a.severity === "critical") ? "critical" : d.budget_alerts.length ? "warning" : "subtle";
+  }
+}
+
+function setDashboard(d) {
+  state.dashboard = d;
+  state.receivedAt = Date.now();
+  render();
+}
+
+async function getJSON(path) {
+  const resp = await fetch(path, { credentials: "same-origin" });
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) throw new Error(body.error || resp.statusText);
+  return body;
+}
+
+async function loadChart() {
+  const bucket = $("chart-window").value;
+  const metric = $("chart-metric").value;
+  const limit = bucket === "hour" ? 48 : 30;
+  const chart = $("chart");
+  let rows = [];
+  try {
+    rows = await getJSON(`api/v1/usage-metrics?window=${bucket}&limit=${limit}`);
+  } catch (e) {
+    rows = [];
+  }
+  $("chart-empty").hidden = rows.length > 0;
+  chart.replaceChildren();
+  if (!rows.length) return;
+
+  const width = 800, height = 200, bottom = 18;
+  const values = rows.map((r) => r[metric] || 0);
+  const max = Math.max(...values, 1);
+  const step = width / rows.length;
+  rows.forEach((row, i) => {
+    const h = (values[i] / max) * (height - bottom - 12);
+    const rect = svg("rect", { x: i * step + 1, y: height - bottom - h, width: Math.max(step - 2, 1), height: h });
+    if (row.is_above_average) rect.setAttribute("class", "above");
+    const at = new Date(row.timestamp);
+    const title = svg("title");
+    title.textContent = `${at.toLocaleString()}: ${formatInt(values[i])}`;
+    rect.append(title);
+    chart.append(rect);
+  });
+  const label = (text, x, anchor) => {
+    const t = svg("text", { x, y: height - 4, "text-anchor": anchor });
+    t.textContent = text;
+    chart.append(t);
+  };
+  const fmt = (r) => (bucket === "hour" ? new Date(r.timestamp).toLocaleString([], { month: "short", day: "numeric", hour: "2-digit" }) : new Date(r.timestamp).toLocaleDateString());
+  label(fmt(rows[0]), 0, "start");
+  label(fmt(rows[rows.length - 1]), width, "end");
+  const top = svg("text", { x: 0, y: 10 });
+  top.textContent = `max ${formatInt(max)}`;
+  chart.append(top);
+}
+
+async function loadAlerts() {
+  let alerts = [];
+  try {
+    alerts = await getJSON("api/v1/alerts");
+  } catch (e) {
+    alerts = [];
+  }
+  $("alerts-empty").hidden = alerts.length > 0;
+  $("alerts").hidden = alerts.length === 0;
+  $("alerts").tBodies[0].replaceChildren(...alerts.map((a) => el("tr", {},
+    el("td", {}, new Date(a.timestamp).toLocaleString()),
+    el("td", { class: a.severity === "critical" ? "critical" : "warning" }, a.severity),
+    el("td", {}, a.message || `${a.metric_name} ${a.metric_value} over ${a.threshold_value}`))));
+}
+
+function connect() {
+  const events = new EventSource("api/stream");
+  events.addEventListener("dashboard", (e) => {
+    setDashboard(JSON.parse(e.data));
+    loadAlerts();
+  });
+  events.onerror = () => {
+    $("status").hidden = false;
+    $("status").textContent = "Connection lost, reconnecting...";
+  };
+}
+
+async function start() {
+  try {
+    setDashboard(await getJSON("api/dashboard"));
+  } catch (e) {
+    $("status").textContent = `${e.message}, waiting for the first poll...`;
+  }
+  connect();
+  loadChart();
+  loadAlerts();
+  $("chart-window").addEventListener("change", loadChart);
+  $("chart-metric").addEventListener("change", loadChart);
+  // Countdowns tick between polls; charts follow the hourly rollups
+  setInterval(render, 1000);
+  setInterval(loadChart, 60 * 1000);
+}
+
+start();
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+)
+
+// windowLabels names the rate limit windows as the terminal dashboard does
+var windowLabels = map[string]string{
+	cerebras.WindowRequestsMinute: "Requests/min",
+	cerebras.WindowRequestsHour:   "Requests/hr",
+	cerebras.WindowRequestsDay:    "Requests/day",
+	cerebras.WindowTokensMinute:   "Tokens/min",
+	cerebras.WindowTokensHour:     "Tokens/hr",
+	cerebras.WindowTokensDay:      "Tokens/day",
+}
+
+// Dashboard is what the page renders: the cards of the terminal dashboard
+// computed from a snapshot
+type Dashboard struct {
+	Organization string         `json:"organization"`
+	Model        string         `json:"model"`
+	FetchedAt    time.Time      `json:"fetched_at"`
+	Windows      []Window       `json:"windows"`
+	Warnings     []string       `json:"warnings"`
+	BudgetAlerts []Alert        `json:"budget_alerts"`
+	Spend        *billing.Spend `json:"spend,omitempty"`
+	// Budgets are 0 when disabled
+	DailyBudget   float64 `json:"daily_budget,omitempty"`
+	MonthlyBudget float64 `json:"monthly_budget,omitempty"`
+}
+
+// Window is one rate limit window card
+type Window struct {
+	Name      string         `json:"name"`
+	Label     string         `json:"label"`
+	Used      int64          `json:"used"`
+	Limit     cerebras.Limit `json:"limit"`
+	Remaining int64          `json:"remaining"`
+	// Percent is the used share of a finite limit
+	Percent float64 `json:"percent"`
+	// Pace and Expected compare usage with an even burn across the window
+	Pace     string   `json:"pace,omitempty"`
+	Delta    float64  `json:"pace_delta"`
+	Expected *float64 `json:"expected,omitempty"`
+	// ResetSeconds is 0 when unknown; ResetMarker flags estimated resets
+	ResetSeconds int64  `json:"reset_seconds"`
+	ResetMarker  string `json:"reset_marker,omitempty"`
+}
+
+// Alert is a budget alert with its message
+type Alert struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// NewDashboard computes the dashboard of a snapshot as of now
+func NewDashboard(snap *monitor.Snapshot, budgets billing.Budgets, now time.Time) Dashboard {
+	snap = snap.At(now)
+	d := Dashboard{
+		Organization:  snap.Organization,
+		Model:         snap.Model,
+		FetchedAt:     snap.FetchedAt,
+		Windows:       []Window{},
+		Warnings:      []string{},
+		BudgetAlerts:  []Alert{},
+		Spend:         snap.Spend,
+		DailyBudget:   budgets.Daily,
+		MonthlyBudget: budgets.Monthly,
+	}
+	for _, b := range snap.Breaches {
+		d.Warnings = append(d.Warnings, b.Message())
+	}
+	if snap.Spend != nil {
+		for _, a := range snap.BudgetAlerts {
+			d.BudgetAlerts = append(d.BudgetAlerts, Alert{Severity: a.Severity, Message: a.Message(snap.Spend.Currency)})
+		}
+	}
+	if snap.Metrics == nil {
+		return d
+	}
+
+	for _, name := range cerebras.Windows {
+		w, err := snap.Metrics.Window(name)
+		if err != nil {
+			continue
+		}
+		view := Window{
+			Name:         name,
+			Label:        windowLabels[name],
+			Used:         w.Used,
+			Limit:        w.Limit,
+			Remaining:    w.Remaining,
+			Percent:      w.Percent(),
+			ResetSeconds: w.Reset,
+		}
+		if est, ok := snap.Estimates[name]; ok {
+			w.Reset = est.Seconds(now)
+			view.ResetSeconds = w.Reset
+			view.ResetMarker = est.Confidence.Marker()
+		}
+		if !w.Limit.IsUnlimited() {
+			if pace, ok := pacing.ForWindow(w, now); ok {
+				expected := pace.Expected
+				view.Pace = pace.String()
+				view.Delta = pace.Delta()
+				view.Expected = &expected
+			}
+		}
+		d.Windows = append(d.Windows, view)
+	}
+	return d
+}
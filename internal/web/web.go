@@ -0,0 +1,144 @@
+// Package web serves a single-page dashboard over HTTP. The page shows the
+// cards of the terminal dashboard, usage_metrics charts and alerts, and is
+// kept live with server-sent events. Data comes from a daemon.Server, whose
+// JSON API is also mounted under /api.
+package web
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Handler serves the dashboard of a daemon server
+type Handler struct {
+	server  *daemon.Server
+	budgets billing.Budgets
+	handler http.Handler
+}
+
+// NewHandler returns the dashboard of the server, protected by auth
+func NewHandler(server *daemon.Server, budgets billing.Budgets, auth Auth) *Handler {
+	h := &Handler{server: server, budgets: budgets}
+	mux := http.NewServeMux()
+
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/dashboard", h.handleDashboard)
+	mux.HandleFunc("GET /api/stream", h.handleStream)
+	mux.Handle("GET /api/", http.StripPrefix("/api", server.Handler()))
+	h.handler = auth.Wrap(mux)
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// Serve serves the dashboard on l until ctx is cancelled
+func (h *Handler) Serve(ctx context.Context, l net.Listener) error {
+	server := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(l)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdown)
+	case err := <-errs:
+		return err
+	}
+}
+
+// dashboard returns the dashboard of the latest snapshot, or nil before the
+// first poll
+func (h *Handler) dashboard(snap *monitor.Snapshot) *Dashboard {
+	if snap == nil {
+		return nil
+	}
+	d := NewDashboard(snap, h.budgets, time.Now())
+	return &d
+}
+
+func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	d := h.dashboard(h.server.Latest())
+	if d == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": daemon.ErrNoData.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// handleStream sends a "dashboard" event with the latest dashboard and then
+// one per poll
+func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, unsubscribe := h.server.Subscribe()
+	defer unsubscribe()
+	latest := h.server.Latest()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(snap *monitor.Snapshot) bool {
+		data, err := json.Marshal(h.dashboard(snap))
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "event: dashboard\ndata: %s\n\n", data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	if latest != nil && !send(latest) {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case snap := <-ch:
+			if !send(snap) {
+				return
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/billing"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/cerebras"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/daemon"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/monitor"
+	"github.com/nathabonfim59/cerebras-code-monitor/internal/pacing"
+)
+
+type fakePoller struct{}
+
+func (fakePoller) Poll(ctx context.Context) (*monitor.Snapshot, error) {
+	return &monitor.Snapshot{
+		Organization: "org_1",
+		Model:        "qwen-3-coder-480b",
+		FetchedAt:    time.Now(),
+		Metrics: &cerebras.RateLimitInfo{
+			LimitRequestsDay:     1000,
+			UsageRequestsDay:     250,
+			RemainingRequestsDay: 750,
+			ResetRequestsDay:     6 * 3600,
+		},
+	}, nil
+}
+
+func TestNewDashboard(t *testing.T) {
+	now := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	snap := &monitor.Snapshot{
+		Model:     "qwen-3-coder-480b",
+		FetchedAt: now.Add(-10 * time.Second),
+		Metrics: &cerebras.RateLimitInfo{
+			LimitRequestsDay:     1000,
+			UsageRequestsDay:     250,
+			RemainingRequestsDay: 750,
+			ResetRequestsDay:     6 * 3600,
+			LimitTokensMinute:    cerebras.LimitUnlimited,
+			UsageTokensMinute:    42,
+		},
+		Spend:        &billing.Spend{Currency: "USD", Day: 12},
+		BudgetAlerts: []billing.BudgetAlert{{Period: "daily", Spent: 12, Budget: 10, Percent: 120, Severity: billing.SeverityCritical}},
+		Breaches:     []pacing.Breach{{Limit: pacing.SoftLimit{Window: cerebras.WindowRequestsDay, MaxPercent: 20}, Percent: 25}},
+	}
+
+	d := NewDashboard(snap, billing.Budgets{Daily: 10}, now)
+
+	if len(d.Windows) != len(cerebras.Windows) {
+		t.Fatalf("Expected every window, got %d", len(d.Windows))
+	}
+	var day, minute Window
+	for _, w := range d.Windows {
+		switch w.Name {
+		case cerebras.WindowRequestsDay:
+			day = w
+		case cerebras.WindowTokensMinute:
+			minute = w
+		}
+	}
+	if day.Label != "Requests/day" || day.Percent != 25 {
+		t.Errorf("Unexpected daily window: %+v", day)
+	}
+	// The countdown is aged to now
+	if day.ResetSeconds != 6*3600-10 {
+		t.Errorf("Expected the reset 10s closer, got %d", day.ResetSeconds)
+	}
+	// About 6h left of a day is 75% elapsed, 50 points behind the 25% used
+	if day.Expected == nil || math.Abs(*day.Expected-75) > 0.1 || day.Pace != "50% behind pace" {
+		t.Errorf("Unexpected pace: %v, %q", day.Expected, day.Pace)
+	}
+	if !minute.Limit.IsUnlimited() || minute.Pace != "" || minute.Used != 42 {
+		t.Errorf("Expected an unlimited window without pace, got %+v", minute)
+	}
+	if len(d.Warnings) != 1 || len(d.BudgetAlerts) != 1 || d.BudgetAlerts[0].Severity != billing.SeverityCritical {
+		t.Errorf("Expected the breach and budget alert, got %v, %v", d.Warnings, d.BudgetAlerts)
+	}
+	if d.DailyBudget != 10 {
+		t.Errorf("Expected the daily budget, got %v", d.DailyBudget)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	auth := Auth{Token: "secret", Username: "admin", Password: "pw"}
+	handler := auth.Wrap(ok)
+
+	tests := []struct {
+		name   string
+		setup  func(r *http.Request)
+		url    string
+		status int
+	}{
+		{"none", func(r *http.Request) {}, "/", http.StatusUnauthorized},
+		{"bearer", func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }, "/", http.StatusOK},
+		{"wrong bearer", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, "/", http.StatusUnauthorized},
+		{"query", func(r *http.Request) {}, "/?token=secret", http.StatusOK},
+		{"cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "secret"}) }, "/", http.StatusOK},
+		{"basic", func(r *http.Request) { r.SetBasicAuth("admin", "pw") }, "/", http.StatusOK},
+		{"wrong basic", func(r *http.Request) { r.SetBasicAuth("admin", "nope") }, "/", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		tt.setup(r)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, w.Code)
+		}
+	}
+
+	if (Auth{}).Enabled() {
+		t.Error("Expected the zero Auth to be disabled")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := daemon.NewServer(fakePoller{}, "org_1", "qwen-3-coder-480b", "", time.Hour)
+	ts := httptest.NewServer(NewHandler(server, billing.Budgets{}, Auth{}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the first poll, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("Expected the embedded page, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/stream", nil)
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = stream.Body.Close()
+	}()
+
+	go server.Run(ctx)
+
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var d Dashboard
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			t.Fatalf("Decoding event: %v", err)
+		}
+		if d.Organization != "org_1" || len(d.Windows) == 0 {
+			t.Errorf("Unexpected dashboard: %+v", d)
+		}
+		break
+	}
+
+	resp, err = http.Get(ts.URL + "/api/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the daemon API under /api, got %d", resp.StatusCode)
+	}
+}